@@ -0,0 +1,27 @@
+package classfile
+
+/*
+AnnotationDefault_attribute {
+    u2            attribute_name_index;
+    u4            attribute_length;
+    element_value default_value;
+}
+*/
+type AnnotationDefaultAttribute struct {
+	data         []byte
+	defaultValue *ElementValue
+}
+
+func (annotationDefaultAttribute *AnnotationDefaultAttribute) Read(classReader *ClassReader) {
+	data := classReader.data
+	annotationDefaultAttribute.defaultValue = readElementValue(classReader)
+	annotationDefaultAttribute.data = data[:len(data)-len(classReader.data)]
+}
+
+func (annotationDefaultAttribute *AnnotationDefaultAttribute) GetData() []byte {
+	return annotationDefaultAttribute.data
+}
+
+func (annotationDefaultAttribute *AnnotationDefaultAttribute) GetDefaultValue() *ElementValue {
+	return annotationDefaultAttribute.defaultValue
+}
@@ -0,0 +1,101 @@
+package classfile
+
+/*
+RuntimeVisibleAnnotations_attribute {
+    u2         attribute_name_index;
+    u4         attribute_length;
+    u2         num_annotations;
+    annotation annotations[num_annotations];
+}
+
+annotation {
+    u2 type_index;
+    u2 num_element_value_pairs;
+    {   u2            element_name_index;
+        element_value value;
+    } element_value_pairs[num_element_value_pairs];
+}
+*/
+type AnnotationsAttribute struct {
+	// The attribute as it is in the class file, reflection parses it again in Java
+	data        []byte
+	annotations []*Annotation
+}
+
+type RuntimeVisibleAnnotationsAttribute struct {
+	AnnotationsAttribute
+}
+
+type RuntimeInvisibleAnnotationsAttribute struct {
+	AnnotationsAttribute
+}
+
+type Annotation struct {
+	typeIndex         uint16
+	elementValuePairs []*ElementValuePair
+}
+
+type ElementValuePair struct {
+	elementNameIndex uint16
+	value            *ElementValue
+}
+
+func (annotationsAttribute *AnnotationsAttribute) Read(classReader *ClassReader) {
+	data := classReader.data
+	annotationsAttribute.annotations = readAnnotations(classReader)
+	annotationsAttribute.data = data[:len(data)-len(classReader.data)]
+}
+
+func readAnnotations(classReader *ClassReader) []*Annotation {
+	annotationsCount := classReader.ReadUint16()
+	annotations := make([]*Annotation, annotationsCount)
+
+	for i := range annotations {
+		annotations[i] = readAnnotation(classReader)
+	}
+
+	return annotations
+}
+
+func readAnnotation(classReader *ClassReader) *Annotation {
+	annotation := &Annotation{
+		typeIndex: classReader.ReadUint16(),
+	}
+
+	elementValuePairsCount := classReader.ReadUint16()
+	annotation.elementValuePairs = make([]*ElementValuePair, elementValuePairsCount)
+
+	for i := range annotation.elementValuePairs {
+		annotation.elementValuePairs[i] = &ElementValuePair{
+			elementNameIndex: classReader.ReadUint16(),
+			value:            readElementValue(classReader),
+		}
+	}
+
+	return annotation
+}
+
+func (annotationsAttribute *AnnotationsAttribute) GetData() []byte {
+	return annotationsAttribute.data
+}
+
+func (annotationsAttribute *AnnotationsAttribute) GetAnnotations() []*Annotation {
+	return annotationsAttribute.annotations
+}
+
+// Utf8 constant of the field descriptor of the annotation interface
+func (annotation *Annotation) GetTypeIndex() uint16 {
+	return annotation.typeIndex
+}
+
+func (annotation *Annotation) GetElementValuePairs() []*ElementValuePair {
+	return annotation.elementValuePairs
+}
+
+func (elementValuePair *ElementValuePair) GetElementNameIndex() uint16 {
+	return elementValuePair.elementNameIndex
+}
+
+func (elementValuePair *ElementValuePair) GetValue() *ElementValue {
+	return elementValuePair.value
+}
@@ -22,30 +22,81 @@ func readAttributes(classReader *ClassReader, constantPool ConstantPool) []Attri
 	return attributes
 }
 
+// Every attribute is read from its own attribute_length bytes
 func readAttribute(classReader *ClassReader, constantPool ConstantPool) AttributeInfo {
-	attributeNameIndex := classReader.ReadUint16()
-	attributeName := constantPool.GetUtf8String(attributeNameIndex)
+	offset := classReader.GetOffset()
+	attributeName := constantPool.checkUtf8String(offset, classReader.ReadUint16())
 	attributeLength := classReader.ReadUint32()
+	attributeReader := &ClassReader{offset: classReader.GetOffset()}
+	attributeReader.data = classReader.ReadBytes(attributeLength)
 	attributeInfo := newAttributeInfo(attributeName, attributeLength, constantPool)
-	attributeInfo.Read(classReader)
+	attributeInfo.Read(attributeReader)
+
+	if len(attributeReader.data) > 0 {
+		panic(newClassFormatError(offset, "Wrong size %d of %s attribute in class file", attributeLength, attributeName))
+	}
 
 	return attributeInfo
 }
 
 func newAttributeInfo(attributeName string, attributeLength uint32, constantPool ConstantPool) AttributeInfo {
 	switch attributeName {
+	case "AnnotationDefault":
+		return &AnnotationDefaultAttribute{}
+	case "BootstrapMethods":
+		return &BootstrapMethodsAttribute{}
 	case "Code":
 		return &CodeAttribute{constantPool: constantPool}
 	case "ConstantValue":
 		return &ConstantValueAttribute{}
 	case "Deprecated":
 		return &DeprecatedAttribute{}
+	case "EnclosingMethod":
+		return &EnclosingMethodAttribute{constantPool: constantPool}
 	case "Exceptions":
 		return &ExceptionsAttribute{}
+	case "InnerClasses":
+		return &InnerClassesAttribute{}
 	case "LineNumberTable":
 		return &LineNumberTableAttribute{}
 	case "LocalVariableTable":
 		return &LocalVariableTableAttribute{}
+	case "LocalVariableTypeTable":
+		return &LocalVariableTypeTableAttribute{}
+	case "MethodParameters":
+		return &MethodParametersAttribute{}
+	case "Module":
+		return &ModuleAttribute{constantPool: constantPool}
+	case "ModuleMainClass":
+		return &ModuleMainClassAttribute{constantPool: constantPool}
+	case "ModulePackages":
+		return &ModulePackagesAttribute{constantPool: constantPool}
+	case "NestHost":
+		return &NestHostAttribute{constantPool: constantPool}
+	case "NestMembers":
+		return &NestMembersAttribute{constantPool: constantPool}
+	case "PermittedSubclasses":
+		return &PermittedSubclassesAttribute{constantPool: constantPool}
+	case "Record":
+		return &RecordAttribute{constantPool: constantPool}
+	case "RuntimeInvisibleAnnotations":
+		return &RuntimeInvisibleAnnotationsAttribute{}
+	case "RuntimeInvisibleParameterAnnotations":
+		return &RuntimeInvisibleParameterAnnotationsAttribute{}
+	case "RuntimeInvisibleTypeAnnotations":
+		return &RuntimeInvisibleTypeAnnotationsAttribute{}
+	case "RuntimeVisibleAnnotations":
+		return &RuntimeVisibleAnnotationsAttribute{}
+	case "RuntimeVisibleParameterAnnotations":
+		return &RuntimeVisibleParameterAnnotationsAttribute{}
+	case "RuntimeVisibleTypeAnnotations":
+		return &RuntimeVisibleTypeAnnotationsAttribute{}
+	case "Signature":
+		return &SignatureAttribute{constantPool: constantPool}
+	case "SourceDebugExtension":
+		return &SourceDebugExtensionAttribute{debugExtensionLength: attributeLength}
+	case "StackMapTable":
+		return &StackMapTableAttribute{}
 	case "SourceFile":
 		return &SourceFileAttribute{constantPool: constantPool}
 	case "Synthetic":
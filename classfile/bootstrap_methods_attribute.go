@@ -0,0 +1,47 @@
+package classfile
+
+/*
+BootstrapMethods_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 num_bootstrap_methods;
+    {   u2 bootstrap_method_ref;
+        u2 num_bootstrap_arguments;
+        u2 bootstrap_arguments[num_bootstrap_arguments];
+    } bootstrap_methods[num_bootstrap_methods];
+}
+*/
+type BootstrapMethodsAttribute struct {
+	bootstrapMethods []*BootstrapMethod
+}
+
+type BootstrapMethod struct {
+	bootstrapMethodReference uint16
+	bootstrapArguments       []uint16
+}
+
+func (bootstrapMethodsAttribute *BootstrapMethodsAttribute) Read(classReader *ClassReader) {
+	bootstrapMethodsCount := classReader.ReadUint16()
+	bootstrapMethods := make([]*BootstrapMethod, bootstrapMethodsCount)
+
+	for i := range bootstrapMethods {
+		bootstrapMethods[i] = &BootstrapMethod{
+			bootstrapMethodReference: classReader.ReadUint16(),
+			bootstrapArguments:       classReader.ReadUint16Table(),
+		}
+	}
+
+	bootstrapMethodsAttribute.bootstrapMethods = bootstrapMethods
+}
+
+func (bootstrapMethodsAttribute *BootstrapMethodsAttribute) GetBootstrapMethods() []*BootstrapMethod {
+	return bootstrapMethodsAttribute.bootstrapMethods
+}
+
+func (bootstrapMethod *BootstrapMethod) GetBootstrapMethodReference() uint16 {
+	return bootstrapMethod.bootstrapMethodReference
+}
+
+func (bootstrapMethod *BootstrapMethod) GetBootstrapArguments() []uint16 {
+	return bootstrapMethod.bootstrapArguments
+}
@@ -1,7 +1,5 @@
 package classfile
 
-import "fmt"
-
 /*
 ClassFile {
     u4             magic;
@@ -36,22 +34,25 @@ type ClassFile struct {
 	attributes       []AttributeInfo
 }
 
+// Malformed data is reported as a *FormatError
 func Parse(classData []byte) (classFile *ClassFile, err error) {
+	classReader := &ClassReader{data: classData}
+
 	defer func() {
 		r := recover()
 
 		if r != nil {
-			var isOk bool
-
-			err, isOk = r.(error)
+			formatError, isOk := r.(*FormatError)
 
 			if !isOk {
-				err = fmt.Errorf("%v", r)
+				formatError = newClassFormatError(classReader.offset, "%v", r)
 			}
+
+			classFile = nil
+			err = formatError
 		}
 	}()
 
-	classReader := &ClassReader{classData}
 	classFile = &ClassFile{}
 
 	classFile.Read(classReader)
@@ -62,21 +63,33 @@ func Parse(classData []byte) (classFile *ClassFile, err error) {
 func (classFile *ClassFile) Read(classReader *ClassReader) {
 	classFile.ReadAndCheckMagicNumber(classReader)
 	classFile.ReadAndCheckVersion(classReader)
-	classFile.constantPool = readConstantPool(classReader)
+	classFile.constantPool = readConstantPool(classReader, classFile.majorVersion)
+
+	classOffset := classReader.GetOffset()
 	classFile.accessFlags = classReader.ReadUint16()
 	classFile.thisClassIndex = classReader.ReadUint16()
 	classFile.superClassIndex = classReader.ReadUint16()
 	classFile.interfaceIndices = classReader.ReadUint16Table()
+	classFile.checkClass(classOffset)
+
 	classFile.fields = readMembers(classReader, classFile.constantPool)
 	classFile.methods = readMembers(classReader, classFile.constantPool)
+	classFile.checkMembers()
+
+	attributesOffset := classReader.GetOffset()
 	classFile.attributes = readAttributes(classReader, classFile.constantPool)
+	classFile.checkInnerClasses(attributesOffset)
+
+	if len(classReader.data) > 0 {
+		panic(newClassFormatError(classReader.GetOffset(), "Extra bytes at the end of class file"))
+	}
 }
 
 func (classFile *ClassFile) ReadAndCheckMagicNumber(classReader *ClassReader) {
 	magicNumber := classReader.ReadUint32()
 
 	if magicNumber != 0xCAFEBABE {
-		panic("java.lang.ClassFormatError: invalid magic number!")
+		panic(newClassFormatError(0, "Incompatible magic value %d in class file", magicNumber))
 	}
 }
 
@@ -84,16 +97,18 @@ func (classFile *ClassFile) ReadAndCheckVersion(classReader *ClassReader) {
 	classFile.minorVersion = classReader.ReadUint16()
 	classFile.majorVersion = classReader.ReadUint16()
 
-	switch classFile.majorVersion {
-	case 45:
+	// Any minor version goes before Java 12, from then on it is 0, or 65535 for preview features
+	switch {
+	case classFile.majorVersion >= 45 && classFile.majorVersion <= 55:
 		return
-	case 46, 47, 48, 49, 50, 51, 52:
-		if classFile.minorVersion == 0 {
+	case classFile.majorVersion >= 56 && classFile.majorVersion <= 69:
+		if classFile.minorVersion == 0 || classFile.minorVersion == 65535 {
 			return
 		}
 	}
 
-	panic("java.lang.UnsupportedClassVersionError!")
+	panic(newUnsupportedClassVersionError(4, "Unsupported class file version %d.%d, versions 45.0 to 69.0 are supported",
+		classFile.majorVersion, classFile.minorVersion))
 }
 
 func (classFile *ClassFile) GetMinorVersion() uint16 {
@@ -152,3 +167,157 @@ func (classFile *ClassFile) GetSourceFileAttribute() *SourceFileAttribute {
 
 	return nil
 }
+
+func (classFile *ClassFile) GetBootstrapMethodsAttribute() *BootstrapMethodsAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *BootstrapMethodsAttribute:
+			return attributeInfo.(*BootstrapMethodsAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetModuleAttribute() *ModuleAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *ModuleAttribute:
+			return attributeInfo.(*ModuleAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetModuleMainClassAttribute() *ModuleMainClassAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *ModuleMainClassAttribute:
+			return attributeInfo.(*ModuleMainClassAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetInnerClassesAttribute() *InnerClassesAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *InnerClassesAttribute:
+			return attributeInfo.(*InnerClassesAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetEnclosingMethodAttribute() *EnclosingMethodAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *EnclosingMethodAttribute:
+			return attributeInfo.(*EnclosingMethodAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetSignatureAttribute() *SignatureAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *SignatureAttribute:
+			return attributeInfo.(*SignatureAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetSourceDebugExtensionAttribute() *SourceDebugExtensionAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *SourceDebugExtensionAttribute:
+			return attributeInfo.(*SourceDebugExtensionAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetNestHostAttribute() *NestHostAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *NestHostAttribute:
+			return attributeInfo.(*NestHostAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetNestMembersAttribute() *NestMembersAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *NestMembersAttribute:
+			return attributeInfo.(*NestMembersAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetRecordAttribute() *RecordAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *RecordAttribute:
+			return attributeInfo.(*RecordAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetPermittedSubclassesAttribute() *PermittedSubclassesAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *PermittedSubclassesAttribute:
+			return attributeInfo.(*PermittedSubclassesAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetModulePackagesAttribute() *ModulePackagesAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *ModulePackagesAttribute:
+			return attributeInfo.(*ModulePackagesAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetRuntimeVisibleAnnotationsAttribute() *RuntimeVisibleAnnotationsAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *RuntimeVisibleAnnotationsAttribute:
+			return attributeInfo.(*RuntimeVisibleAnnotationsAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (classFile *ClassFile) GetRuntimeVisibleTypeAnnotationsAttribute() *RuntimeVisibleTypeAnnotationsAttribute {
+	for _, attributeInfo := range classFile.attributes {
+		switch attributeInfo.(type) {
+		case *RuntimeVisibleTypeAnnotationsAttribute:
+			return attributeInfo.(*RuntimeVisibleTypeAnnotationsAttribute)
+		}
+	}
+
+	return nil
+}
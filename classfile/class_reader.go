@@ -4,37 +4,27 @@ import "encoding/binary"
 
 type ClassReader struct {
 	data []byte
+	// Position of data in the class file, format errors report it
+	offset int
 }
 
 // u1
 func (classReader *ClassReader) ReadUint8() uint8 {
-	value := classReader.data[0]
-	classReader.data = classReader.data[1:]
-
-	return value
+	return classReader.readData(1)[0]
 }
 
 // u2
 func (classReader *ClassReader) ReadUint16() uint16 {
-	value := binary.BigEndian.Uint16(classReader.data)
-	classReader.data = classReader.data[2:]
-
-	return value
+	return binary.BigEndian.Uint16(classReader.readData(2))
 }
 
 // u4
 func (classReader *ClassReader) ReadUint32() uint32 {
-	value := binary.BigEndian.Uint32(classReader.data)
-	classReader.data = classReader.data[4:]
-
-	return value
+	return binary.BigEndian.Uint32(classReader.readData(4))
 }
 
 func (classReader *ClassReader) ReadUint64() uint64 {
-	value := binary.BigEndian.Uint64(classReader.data)
-	classReader.data = classReader.data[8:]
-
-	return value
+	return binary.BigEndian.Uint64(classReader.readData(8))
 }
 
 func (classReader *ClassReader) ReadUint16Table() []uint16 {
@@ -49,8 +39,21 @@ func (classReader *ClassReader) ReadUint16Table() []uint16 {
 }
 
 func (classReader *ClassReader) ReadBytes(numberOfBytes uint32) []byte {
-	bytes := classReader.data[:numberOfBytes]
+	return classReader.readData(int(numberOfBytes))
+}
+
+func (classReader *ClassReader) GetOffset() int {
+	return classReader.offset
+}
+
+func (classReader *ClassReader) readData(numberOfBytes int) []byte {
+	if numberOfBytes > len(classReader.data) {
+		panic(newClassFormatError(classReader.offset, "Truncated class file"))
+	}
+
+	data := classReader.data[:numberOfBytes]
 	classReader.data = classReader.data[numberOfBytes:]
+	classReader.offset += numberOfBytes
 
-	return bytes
+	return data
 }
@@ -62,3 +62,25 @@ func (codeAttribute *CodeAttribute) GetLineNumberTableAttribute() *LineNumberTab
 
 	return nil
 }
+
+func (codeAttribute *CodeAttribute) GetStackMapTableAttribute() *StackMapTableAttribute {
+	for _, attributeInfo := range codeAttribute.attributes {
+		switch attributeInfo.(type) {
+		case *StackMapTableAttribute:
+			return attributeInfo.(*StackMapTableAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (codeAttribute *CodeAttribute) GetLocalVariableTypeTableAttribute() *LocalVariableTypeTableAttribute {
+	for _, attributeInfo := range codeAttribute.attributes {
+		switch attributeInfo.(type) {
+		case *LocalVariableTypeTableAttribute:
+			return attributeInfo.(*LocalVariableTypeTableAttribute)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package classfile
+
+/*
+CONSTANT_Dynamic_info {
+    u1 tag;
+    u2 bootstrap_method_attr_index;
+    u2 name_and_type_index;
+}
+*/
+type ConstantDynamicInfo struct {
+	constantPool                  ConstantPool
+	bootstrapMethodAttributeIndex uint16
+	nameAndTypeIndex              uint16
+}
+
+func (constantDynamicInfo *ConstantDynamicInfo) Read(classReader *ClassReader) {
+	constantDynamicInfo.bootstrapMethodAttributeIndex = classReader.ReadUint16()
+	constantDynamicInfo.nameAndTypeIndex = classReader.ReadUint16()
+}
+
+func (constantDynamicInfo *ConstantDynamicInfo) GetBootstrapMethodAttributeIndex() uint16 {
+	return constantDynamicInfo.bootstrapMethodAttributeIndex
+}
+
+func (constantDynamicInfo *ConstantDynamicInfo) GetNameAndTypeDescriptor() (string, string) {
+	return constantDynamicInfo.constantPool.GetNameAndTypeDescriptor(constantDynamicInfo.nameAndTypeIndex)
+}
@@ -16,7 +16,10 @@ const (
 	constantTypeUtf8String               = 1
 	constantTypeMethodHandle             = 15
 	constantTypeMethodType               = 16
+	constantTypeDynamic                  = 17
 	constantTypeInvokeDynamic            = 18
+	constantTypeModule                   = 19
+	constantTypePackage                  = 20
 )
 
 type ConstantInfo interface {
@@ -24,8 +27,14 @@ type ConstantInfo interface {
 }
 
 func readConstantInfo(classReader *ClassReader, constantPool ConstantPool) ConstantInfo {
+	offset := classReader.GetOffset()
 	constantInfoType := classReader.ReadUint8()
 	constantInfo := newConstantInfo(constantInfoType, constantPool)
+
+	if constantInfo == nil {
+		panic(newClassFormatError(offset, "Unknown constant tag %d in class file", constantInfoType))
+	}
+
 	constantInfo.Read(classReader)
 
 	return constantInfo
@@ -56,12 +65,18 @@ func newConstantInfo(constantInfoType uint8, constantPool ConstantPool) Constant
 	case constantTypeNameAndTypeDescriptor:
 		return &ConstantNameAndTypeDescriptorInfo{}
 	case constantTypeMethodType:
-		return &ConstantMethodTypeInfo{}
+		return &ConstantMethodTypeInfo{constantPool: constantPool}
 	case constantTypeMethodHandle:
 		return &ConstantMethodHandleInfo{}
+	case constantTypeDynamic:
+		return &ConstantDynamicInfo{constantPool: constantPool}
 	case constantTypeInvokeDynamic:
-		return &ConstantInvokeDynamicInfo{}
+		return &ConstantInvokeDynamicInfo{constantPool: constantPool}
+	case constantTypeModule:
+		return &ConstantModuleInfo{constantPool: constantPool}
+	case constantTypePackage:
+		return &ConstantPackageInfo{constantPool: constantPool}
 	default:
-		panic("java.lang.ClassFormatError: unsupported constant pool tag!")
+		return nil
 	}
 }
@@ -8,6 +8,7 @@ CONSTANT_InvokeDynamic_info {
 }
 */
 type ConstantInvokeDynamicInfo struct {
+	constantPool                  ConstantPool
 	bootstrapMethodAttributeIndex uint16
 	nameAndTypeIndex              uint16
 }
@@ -16,3 +17,11 @@ func (constantInvokeDynamicInfo *ConstantInvokeDynamicInfo) Read(classReader *Cl
 	constantInvokeDynamicInfo.bootstrapMethodAttributeIndex = classReader.ReadUint16()
 	constantInvokeDynamicInfo.nameAndTypeIndex = classReader.ReadUint16()
 }
+
+func (constantInvokeDynamicInfo *ConstantInvokeDynamicInfo) GetBootstrapMethodAttributeIndex() uint16 {
+	return constantInvokeDynamicInfo.bootstrapMethodAttributeIndex
+}
+
+func (constantInvokeDynamicInfo *ConstantInvokeDynamicInfo) GetNameAndTypeDescriptor() (string, string) {
+	return constantInvokeDynamicInfo.constantPool.GetNameAndTypeDescriptor(constantInvokeDynamicInfo.nameAndTypeIndex)
+}
@@ -16,3 +16,11 @@ func (constantMethodHandleInfo *ConstantMethodHandleInfo) Read(classReader *Clas
 	constantMethodHandleInfo.methodHandleKind = classReader.ReadUint8()
 	constantMethodHandleInfo.methodHandleReferenceIndex = classReader.ReadUint16()
 }
+
+func (constantMethodHandleInfo *ConstantMethodHandleInfo) GetMethodHandleKind() uint8 {
+	return constantMethodHandleInfo.methodHandleKind
+}
+
+func (constantMethodHandleInfo *ConstantMethodHandleInfo) GetMethodHandleReferenceIndex() uint16 {
+	return constantMethodHandleInfo.methodHandleReferenceIndex
+}
@@ -7,9 +7,14 @@ CONSTANT_MethodType_info {
 }
 */
 type ConstantMethodTypeInfo struct {
+	constantPool    ConstantPool
 	descriptorIndex uint16
 }
 
 func (constantMethodTypeInfo *ConstantMethodTypeInfo) Read(classReader *ClassReader) {
 	constantMethodTypeInfo.descriptorIndex = classReader.ReadUint16()
 }
+
+func (constantMethodTypeInfo *ConstantMethodTypeInfo) GetDescriptor() string {
+	return constantMethodTypeInfo.constantPool.GetUtf8String(constantMethodTypeInfo.descriptorIndex)
+}
@@ -0,0 +1,20 @@
+package classfile
+
+/*
+CONSTANT_Module_info {
+    u1 tag;
+    u2 name_index;
+}
+*/
+type ConstantModuleInfo struct {
+	constantPool ConstantPool
+	nameIndex    uint16
+}
+
+func (constantModuleInfo *ConstantModuleInfo) Read(classReader *ClassReader) {
+	constantModuleInfo.nameIndex = classReader.ReadUint16()
+}
+
+func (constantModuleInfo *ConstantModuleInfo) GetName() string {
+	return constantModuleInfo.constantPool.GetUtf8String(constantModuleInfo.nameIndex)
+}
@@ -0,0 +1,20 @@
+package classfile
+
+/*
+CONSTANT_Package_info {
+    u1 tag;
+    u2 name_index;
+}
+*/
+type ConstantPackageInfo struct {
+	constantPool ConstantPool
+	nameIndex    uint16
+}
+
+func (constantPackageInfo *ConstantPackageInfo) Read(classReader *ClassReader) {
+	constantPackageInfo.nameIndex = classReader.ReadUint16()
+}
+
+func (constantPackageInfo *ConstantPackageInfo) GetName() string {
+	return constantPackageInfo.constantPool.GetUtf8String(constantPackageInfo.nameIndex)
+}
@@ -4,12 +4,14 @@ import "fmt"
 
 type ConstantPool []ConstantInfo
 
-func readConstantPool(classReader *ClassReader) ConstantPool {
+func readConstantPool(classReader *ClassReader, majorVersion uint16) ConstantPool {
 	constantPoolCount := int(classReader.ReadUint16())
 	constantPool := make([]ConstantInfo, constantPoolCount)
+	offsets := make([]int, constantPoolCount)
 
 	// The constant_pool table is indexed from 1 to constant_pool_count - 1
 	for i := 1; i < constantPoolCount; i++ {
+		offsets[i] = classReader.GetOffset()
 		constantPool[i] = readConstantInfo(classReader, constantPool)
 
 		switch constantPool[i].(type) {
@@ -18,6 +20,8 @@ func readConstantPool(classReader *ClassReader) ConstantPool {
 		}
 	}
 
+	checkConstantPool(constantPool, offsets, majorVersion)
+
 	return constantPool
 }
 
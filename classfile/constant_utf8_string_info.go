@@ -22,6 +22,10 @@ func (constantUtf8StringInfo *ConstantUtf8StringInfo) Read(classReader *ClassRea
 	constantUtf8StringInfo.value = decodeMUTF8(bytes)
 }
 
+func (constantUtf8StringInfo *ConstantUtf8StringInfo) GetValue() string {
+	return constantUtf8StringInfo.value
+}
+
 func decodeMUTF8(bytes []byte) string {
 	utfLength := len(bytes)
 	chars := make([]uint16, utfLength)
@@ -0,0 +1,79 @@
+package classfile
+
+/*
+element_value {
+    u1 tag;
+    union {
+        u2 const_value_index;
+
+        {   u2 type_name_index;
+            u2 const_name_index;
+        } enum_const_value;
+
+        u2 class_info_index;
+
+        annotation annotation_value;
+
+        {   u2            num_values;
+            element_value values[num_values];
+        } array_value;
+    } value;
+}
+*/
+type ElementValue struct {
+	tag uint8
+	// const_value_index, type_name_index or class_info_index
+	valueIndex uint16
+	// const_name_index of an enum constant
+	constantNameIndex uint16
+	annotationValue   *Annotation
+	arrayValues       []*ElementValue
+}
+
+func readElementValue(classReader *ClassReader) *ElementValue {
+	elementValue := &ElementValue{
+		tag: classReader.ReadUint8(),
+	}
+
+	switch elementValue.tag {
+	case 'B', 'C', 'D', 'F', 'I', 'J', 'S', 'Z', 's', 'c':
+		elementValue.valueIndex = classReader.ReadUint16()
+	case 'e':
+		elementValue.valueIndex = classReader.ReadUint16()
+		elementValue.constantNameIndex = classReader.ReadUint16()
+	case '@':
+		elementValue.annotationValue = readAnnotation(classReader)
+	case '[':
+		valuesCount := classReader.ReadUint16()
+		elementValue.arrayValues = make([]*ElementValue, valuesCount)
+
+		for i := range elementValue.arrayValues {
+			elementValue.arrayValues[i] = readElementValue(classReader)
+		}
+	default:
+		panic(newClassFormatError(classReader.GetOffset()-1, "Unknown element value tag '%c' in class file", elementValue.tag))
+	}
+
+	return elementValue
+}
+
+func (elementValue *ElementValue) GetTag() uint8 {
+	return elementValue.tag
+}
+
+// Constant value of primitives and strings, Utf8 field descriptor of enums, Utf8 return descriptor of classes
+func (elementValue *ElementValue) GetValueIndex() uint16 {
+	return elementValue.valueIndex
+}
+
+func (elementValue *ElementValue) GetConstantNameIndex() uint16 {
+	return elementValue.constantNameIndex
+}
+
+func (elementValue *ElementValue) GetAnnotationValue() *Annotation {
+	return elementValue.annotationValue
+}
+
+func (elementValue *ElementValue) GetArrayValues() []*ElementValue {
+	return elementValue.arrayValues
+}
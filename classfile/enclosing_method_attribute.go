@@ -0,0 +1,33 @@
+package classfile
+
+/*
+EnclosingMethod_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 class_index;
+    u2 method_index;
+}
+*/
+type EnclosingMethodAttribute struct {
+	constantPool ConstantPool
+	classIndex   uint16
+	methodIndex  uint16
+}
+
+func (enclosingMethodAttribute *EnclosingMethodAttribute) Read(classReader *ClassReader) {
+	enclosingMethodAttribute.classIndex = classReader.ReadUint16()
+	enclosingMethodAttribute.methodIndex = classReader.ReadUint16()
+}
+
+func (enclosingMethodAttribute *EnclosingMethodAttribute) GetClassName() string {
+	return enclosingMethodAttribute.constantPool.GetClassName(enclosingMethodAttribute.classIndex)
+}
+
+// Empty for classes in initializers, which are not enclosed by a method
+func (enclosingMethodAttribute *EnclosingMethodAttribute) GetMethodNameAndDescriptor() (string, string) {
+	if enclosingMethodAttribute.methodIndex == 0 {
+		return "", ""
+	}
+
+	return enclosingMethodAttribute.constantPool.GetNameAndTypeDescriptor(enclosingMethodAttribute.methodIndex)
+}
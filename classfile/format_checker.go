@@ -0,0 +1,533 @@
+package classfile
+
+import "strings"
+
+// Access flags the format checks look at
+const (
+	accessFlagPublic       = 0x0001
+	accessFlagPrivate      = 0x0002
+	accessFlagProtected    = 0x0004
+	accessFlagStatic       = 0x0008
+	accessFlagFinal        = 0x0010
+	accessFlagSynchronized = 0x0020
+	accessFlagVolatile     = 0x0040
+	accessFlagBridge       = 0x0040
+	accessFlagVarargs      = 0x0080
+	accessFlagNative       = 0x0100
+	accessFlagInterface    = 0x0200
+	accessFlagAbstract     = 0x0400
+	accessFlagStrict       = 0x0800
+	accessFlagSynthetic    = 0x1000
+	accessFlagAnnotation   = 0x2000
+	accessFlagEnum         = 0x4000
+	accessFlagModule       = 0x8000
+)
+
+// Method handle kinds
+const (
+	referenceKindGetField         = 1
+	referenceKindPutStatic        = 4
+	referenceKindInvokeVirtual    = 5
+	referenceKindInvokeStatic     = 6
+	referenceKindInvokeSpecial    = 7
+	referenceKindNewInvokeSpecial = 8
+	referenceKindInvokeInterface  = 9
+)
+
+// Every constant has to refer to constants of the right kinds, names and descriptors have to be well formed
+func checkConstantPool(constantPool ConstantPool, offsets []int, majorVersion uint16) {
+	for i, constantInfo := range constantPool {
+		offset := offsets[i]
+
+		switch constantInfo.(type) {
+		case *ConstantClassInfo:
+			className := constantPool.checkUtf8String(offset, constantInfo.(*ConstantClassInfo).nameIndex)
+
+			if !isValidClassName(className) {
+				panic(newClassFormatError(offset, "Illegal class name \"%s\" in class file", className))
+			}
+		case *ConstantStringReferenceInfo:
+			constantPool.checkUtf8String(offset, constantInfo.(*ConstantStringReferenceInfo).stringIndex)
+		case *ConstantFieldReferenceInfo:
+			constantPool.checkMemberReference(offset, &constantInfo.(*ConstantFieldReferenceInfo).ConstantMemberReferenceInfo, false)
+		case *ConstantMethodReferenceInfo:
+			constantPool.checkMemberReference(offset, &constantInfo.(*ConstantMethodReferenceInfo).ConstantMemberReferenceInfo, true)
+		case *ConstantInterfaceMethodReferenceInfo:
+			constantPool.checkMemberReference(offset, &constantInfo.(*ConstantInterfaceMethodReferenceInfo).ConstantMemberReferenceInfo, true)
+		case *ConstantNameAndTypeDescriptorInfo:
+			constantPool.checkUtf8String(offset, constantInfo.(*ConstantNameAndTypeDescriptorInfo).nameIndex)
+			constantPool.checkUtf8String(offset, constantInfo.(*ConstantNameAndTypeDescriptorInfo).descriptorIndex)
+		case *ConstantMethodHandleInfo:
+			checkConstantVersion(offset, majorVersion, 51)
+			constantPool.checkMethodHandle(offset, constantInfo.(*ConstantMethodHandleInfo), majorVersion)
+		case *ConstantMethodTypeInfo:
+			checkConstantVersion(offset, majorVersion, 51)
+			descriptor := constantPool.checkUtf8String(offset, constantInfo.(*ConstantMethodTypeInfo).descriptorIndex)
+
+			if getParameterSlotsCount(descriptor) < 0 {
+				panic(newClassFormatError(offset, "Illegal method descriptor \"%s\" in class file", descriptor))
+			}
+		case *ConstantDynamicInfo:
+			checkConstantVersion(offset, majorVersion, 55)
+			_, descriptor := constantPool.checkNameAndTypeDescriptor(offset, constantInfo.(*ConstantDynamicInfo).nameAndTypeIndex)
+
+			if !isValidFieldDescriptor(descriptor) {
+				panic(newClassFormatError(offset, "Illegal field descriptor \"%s\" in class file", descriptor))
+			}
+		case *ConstantInvokeDynamicInfo:
+			checkConstantVersion(offset, majorVersion, 51)
+			_, descriptor := constantPool.checkNameAndTypeDescriptor(offset, constantInfo.(*ConstantInvokeDynamicInfo).nameAndTypeIndex)
+
+			if getParameterSlotsCount(descriptor) < 0 {
+				panic(newClassFormatError(offset, "Illegal method descriptor \"%s\" in class file", descriptor))
+			}
+		case *ConstantModuleInfo:
+			checkConstantVersion(offset, majorVersion, 53)
+			constantPool.checkUtf8String(offset, constantInfo.(*ConstantModuleInfo).nameIndex)
+		case *ConstantPackageInfo:
+			checkConstantVersion(offset, majorVersion, 53)
+			constantPool.checkUtf8String(offset, constantInfo.(*ConstantPackageInfo).nameIndex)
+		}
+	}
+}
+
+func checkConstantVersion(offset int, majorVersion, minimumMajorVersion uint16) {
+	if majorVersion < minimumMajorVersion {
+		panic(newClassFormatError(offset, "Class file version %d does not support this constant tag", majorVersion))
+	}
+}
+
+func (constantPool ConstantPool) checkIndex(offset int, index uint16) ConstantInfo {
+	if int(index) >= len(constantPool) || constantPool[index] == nil {
+		panic(newClassFormatError(offset, "Invalid constant pool index %d in class file", index))
+	}
+
+	return constantPool[index]
+}
+
+func (constantPool ConstantPool) checkUtf8String(offset int, index uint16) string {
+	constantUtf8StringInfo, ok := constantPool.checkIndex(offset, index).(*ConstantUtf8StringInfo)
+
+	if !ok {
+		panic(newClassFormatError(offset, "Constant pool index %d is not a Utf8 string in class file", index))
+	}
+
+	return constantUtf8StringInfo.value
+}
+
+func (constantPool ConstantPool) checkClassName(offset int, index uint16) string {
+	constantClassInfo, ok := constantPool.checkIndex(offset, index).(*ConstantClassInfo)
+
+	if !ok {
+		panic(newClassFormatError(offset, "Constant pool index %d is not a class in class file", index))
+	}
+
+	return constantPool.checkUtf8String(offset, constantClassInfo.nameIndex)
+}
+
+func (constantPool ConstantPool) checkNameAndTypeDescriptor(offset int, index uint16) (string, string) {
+	constantNameAndTypeDescriptorInfo, ok := constantPool.checkIndex(offset, index).(*ConstantNameAndTypeDescriptorInfo)
+
+	if !ok {
+		panic(newClassFormatError(offset, "Constant pool index %d is not a name and type in class file", index))
+	}
+
+	name := constantPool.checkUtf8String(offset, constantNameAndTypeDescriptorInfo.nameIndex)
+	descriptor := constantPool.checkUtf8String(offset, constantNameAndTypeDescriptorInfo.descriptorIndex)
+
+	return name, descriptor
+}
+
+// Field references need a field name and descriptor, method references a method name and descriptor
+func (constantPool ConstantPool) checkMemberReference(offset int, constantMemberReferenceInfo *ConstantMemberReferenceInfo, isMethod bool) {
+	constantPool.checkClassName(offset, constantMemberReferenceInfo.classIndex)
+	name, descriptor := constantPool.checkNameAndTypeDescriptor(offset, constantMemberReferenceInfo.nameAndTypeIndex)
+
+	if !isMethod {
+		if !isValidUnqualifiedName(name) {
+			panic(newClassFormatError(offset, "Illegal field name \"%s\" in class file", name))
+		}
+
+		if !isValidFieldDescriptor(descriptor) {
+			panic(newClassFormatError(offset, "Illegal field descriptor \"%s\" in class file", descriptor))
+		}
+
+		return
+	}
+
+	if name == "<clinit>" || !isValidMethodName(name) {
+		panic(newClassFormatError(offset, "Illegal method name \"%s\" in class file", name))
+	}
+
+	if getParameterSlotsCount(descriptor) < 0 || name == "<init>" && !strings.HasSuffix(descriptor, ")V") {
+		panic(newClassFormatError(offset, "Illegal method descriptor \"%s\" in class file", descriptor))
+	}
+}
+
+func (constantPool ConstantPool) checkMethodHandle(offset int, constantMethodHandleInfo *ConstantMethodHandleInfo, majorVersion uint16) {
+	referenceKind := constantMethodHandleInfo.methodHandleKind
+	referenceInfo := constantPool.checkIndex(offset, constantMethodHandleInfo.methodHandleReferenceIndex)
+	var constantMemberReferenceInfo *ConstantMemberReferenceInfo
+	isValid := false
+
+	switch referenceInfo.(type) {
+	case *ConstantFieldReferenceInfo:
+		constantMemberReferenceInfo = &referenceInfo.(*ConstantFieldReferenceInfo).ConstantMemberReferenceInfo
+		isValid = referenceKind >= referenceKindGetField && referenceKind <= referenceKindPutStatic
+	case *ConstantMethodReferenceInfo:
+		constantMemberReferenceInfo = &referenceInfo.(*ConstantMethodReferenceInfo).ConstantMemberReferenceInfo
+		isValid = referenceKind >= referenceKindInvokeVirtual && referenceKind <= referenceKindNewInvokeSpecial
+	case *ConstantInterfaceMethodReferenceInfo:
+		constantMemberReferenceInfo = &referenceInfo.(*ConstantInterfaceMethodReferenceInfo).ConstantMemberReferenceInfo
+		isValid = referenceKind == referenceKindInvokeInterface ||
+			majorVersion >= 52 && (referenceKind == referenceKindInvokeStatic || referenceKind == referenceKindInvokeSpecial)
+	}
+
+	if !isValid {
+		panic(newClassFormatError(offset, "Bad method handle kind %d in class file", referenceKind))
+	}
+
+	name, _ := constantPool.checkNameAndTypeDescriptor(offset, constantMemberReferenceInfo.nameAndTypeIndex)
+
+	if (referenceKind == referenceKindNewInvokeSpecial) != (name == "<init>") {
+		panic(newClassFormatError(offset, "Bad method handle name \"%s\" in class file", name))
+	}
+}
+
+// this_class, super_class and interfaces, and the access flags of the class
+func (classFile *ClassFile) checkClass(offset int) {
+	constantPool := classFile.constantPool
+	accessFlags := classFile.accessFlags
+	className := constantPool.checkClassName(offset, classFile.thisClassIndex)
+
+	if accessFlags&accessFlagModule != 0 {
+		if classFile.majorVersion < 53 || className != "module-info" || classFile.superClassIndex != 0 {
+			panic(newClassFormatError(offset, "Illegal module descriptor in class file %s", className))
+		}
+
+		return
+	}
+
+	isInterface := accessFlags&accessFlagInterface != 0
+
+	// Old compilers left ACC_ABSTRACT off interfaces
+	if isInterface && (accessFlags&(accessFlagFinal|accessFlagEnum) != 0 ||
+		accessFlags&accessFlagAbstract == 0 && classFile.majorVersion >= 50) ||
+		!isInterface && (accessFlags&accessFlagAnnotation != 0 || accessFlags&accessFlagFinal != 0 && accessFlags&accessFlagAbstract != 0) {
+		panic(newClassFormatError(offset, "Illegal class modifiers in class %s: 0x%X", className, accessFlags))
+	}
+
+	if classFile.superClassIndex == 0 {
+		if className != "java/lang/Object" {
+			panic(newClassFormatError(offset, "Invalid superclass index 0 in class file %s", className))
+		}
+	} else {
+		superClassName := constantPool.checkClassName(offset, classFile.superClassIndex)
+
+		if superClassName[0] == '[' || isInterface && superClassName != "java/lang/Object" {
+			panic(newClassFormatError(offset, "Illegal superclass \"%s\" in class file %s", superClassName, className))
+		}
+	}
+
+	interfaceNames := map[string]bool{}
+
+	for _, interfaceIndex := range classFile.interfaceIndices {
+		interfaceName := constantPool.checkClassName(offset, interfaceIndex)
+
+		if interfaceNames[interfaceName] {
+			panic(newClassFormatError(offset, "Duplicate interface name \"%s\" in class file %s", interfaceName, className))
+		}
+
+		interfaceNames[interfaceName] = true
+	}
+}
+
+// The class loader takes the modifiers of nested classes from their InnerClasses entries
+func (classFile *ClassFile) checkInnerClasses(offset int) {
+	innerClassesAttribute := classFile.GetInnerClassesAttribute()
+
+	if innerClassesAttribute == nil {
+		return
+	}
+
+	for _, innerClassEntry := range innerClassesAttribute.classes {
+		if innerClassEntry.innerClassInfoIndex == 0 {
+			panic(newClassFormatError(offset, "Invalid inner class index 0 in class file"))
+		}
+
+		classFile.constantPool.checkClassName(offset, innerClassEntry.innerClassInfoIndex)
+	}
+}
+
+func (classFile *ClassFile) checkMembers() {
+	memberKeys := map[string]bool{}
+
+	for _, field := range classFile.fields {
+		classFile.checkField(field)
+
+		memberKey := field.GetName() + ":" + field.GetDescriptor()
+
+		if memberKeys[memberKey] {
+			panic(newClassFormatError(field.offset, "Duplicate field name \"%s\" with signature \"%s\" in class file", field.GetName(), field.GetDescriptor()))
+		}
+
+		memberKeys[memberKey] = true
+	}
+
+	for _, method := range classFile.methods {
+		classFile.checkMethod(method)
+
+		memberKey := method.GetName() + method.GetDescriptor()
+
+		if memberKeys[memberKey] {
+			panic(newClassFormatError(method.offset, "Duplicate method name \"%s\" with signature \"%s\" in class file", method.GetName(), method.GetDescriptor()))
+		}
+
+		memberKeys[memberKey] = true
+	}
+}
+
+func (classFile *ClassFile) checkField(field *MemberInfo) {
+	offset := field.offset
+	name := classFile.constantPool.checkUtf8String(offset, field.nameIndex)
+	descriptor := classFile.constantPool.checkUtf8String(offset, field.descriptorIndex)
+	accessFlags := field.accessFlags
+
+	if !isValidUnqualifiedName(name) {
+		panic(newClassFormatError(offset, "Illegal field name \"%s\" in class file", name))
+	}
+
+	if !isValidFieldDescriptor(descriptor) {
+		panic(newClassFormatError(offset, "Illegal field descriptor \"%s\" for field %s in class file", descriptor, name))
+	}
+
+	isInterfaceField := classFile.accessFlags&accessFlagInterface != 0
+	interfaceFieldFlags := uint16(accessFlagPublic | accessFlagStatic | accessFlagFinal)
+
+	if !hasOneVisibility(accessFlags, false) || accessFlags&accessFlagFinal != 0 && accessFlags&accessFlagVolatile != 0 ||
+		isInterfaceField && accessFlags&^accessFlagSynthetic != interfaceFieldFlags {
+		panic(newClassFormatError(offset, "Illegal field modifiers in class file: 0x%X", accessFlags))
+	}
+
+	constantValueAttribute := field.GetConstantValueAttribute()
+
+	if constantValueAttribute != nil && accessFlags&accessFlagStatic != 0 {
+		classFile.checkConstantValue(offset, constantValueAttribute.constantValueIndex, descriptor)
+	}
+}
+
+func (classFile *ClassFile) checkConstantValue(offset int, index uint16, descriptor string) {
+	constantInfo := classFile.constantPool.checkIndex(offset, index)
+	isValid := false
+
+	switch constantInfo.(type) {
+	case *ConstantIntegerInfo:
+		isValid = len(descriptor) == 1 && strings.Contains("IBCSZ", descriptor)
+	case *ConstantLongInfo:
+		isValid = descriptor == "J"
+	case *ConstantFloatInfo:
+		isValid = descriptor == "F"
+	case *ConstantDoubleInfo:
+		isValid = descriptor == "D"
+	case *ConstantStringReferenceInfo:
+		isValid = descriptor == "Ljava/lang/String;"
+	}
+
+	if !isValid {
+		panic(newClassFormatError(offset, "Inconsistent constant value type in class file"))
+	}
+}
+
+func (classFile *ClassFile) checkMethod(method *MemberInfo) {
+	offset := method.offset
+	name := classFile.constantPool.checkUtf8String(offset, method.nameIndex)
+	descriptor := classFile.constantPool.checkUtf8String(offset, method.descriptorIndex)
+	accessFlags := method.accessFlags
+	isInterfaceMethod := classFile.accessFlags&accessFlagInterface != 0
+
+	if !isValidMethodName(name) {
+		panic(newClassFormatError(offset, "Illegal method name \"%s\" in class file", name))
+	}
+
+	parameterSlotsCount := getParameterSlotsCount(descriptor)
+
+	if accessFlags&accessFlagStatic == 0 {
+		// this
+		parameterSlotsCount++
+	}
+
+	if getParameterSlotsCount(descriptor) < 0 || parameterSlotsCount > 255 ||
+		name == "<init>" && !strings.HasSuffix(descriptor, ")V") || name == "<clinit>" && descriptor != "()V" {
+		panic(newClassFormatError(offset, "Illegal method descriptor \"%s\" for method %s in class file", descriptor, name))
+	}
+
+	if name != "<clinit>" && !classFile.isValidMethodAccessFlags(name, accessFlags, isInterfaceMethod) {
+		panic(newClassFormatError(offset, "Method %s in class file has illegal modifiers: 0x%X", name, accessFlags))
+	}
+
+	codeAttributesCount := 0
+
+	for _, attribute := range method.attributes {
+		switch attribute.(type) {
+		case *CodeAttribute:
+			codeAttributesCount++
+			codeLength := len(attribute.(*CodeAttribute).code)
+
+			if codeLength == 0 || codeLength > 65535 {
+				panic(newClassFormatError(offset, "Invalid method Code length %d in class file", codeLength))
+			}
+		}
+	}
+
+	if accessFlags&(accessFlagNative|accessFlagAbstract) != 0 && codeAttributesCount != 0 {
+		panic(newClassFormatError(offset, "Code attribute in native or abstract method %s in class file", name))
+	}
+
+	if accessFlags&(accessFlagNative|accessFlagAbstract) == 0 && codeAttributesCount != 1 {
+		panic(newClassFormatError(offset, "Method %s in class file needs exactly one Code attribute", name))
+	}
+}
+
+// Class initializers are not checked, their flags other than ACC_STATIC do not matter
+func (classFile *ClassFile) isValidMethodAccessFlags(name string, accessFlags uint16, isInterfaceMethod bool) bool {
+	if !hasOneVisibility(accessFlags, false) {
+		return false
+	}
+
+	if name == "<init>" {
+		return !isInterfaceMethod && accessFlags&^(accessFlagPublic|accessFlagPrivate|accessFlagProtected|accessFlagVarargs|accessFlagStrict|accessFlagSynthetic) == 0
+	}
+
+	if isInterfaceMethod {
+		if classFile.majorVersion < 52 {
+			return accessFlags&(accessFlagPublic|accessFlagAbstract) == accessFlagPublic|accessFlagAbstract &&
+				accessFlags&^(accessFlagPublic|accessFlagAbstract|accessFlagVarargs|accessFlagBridge|accessFlagSynthetic) == 0
+		}
+
+		if !hasOneVisibility(accessFlags&^accessFlagProtected, true) ||
+			accessFlags&(accessFlagProtected|accessFlagFinal|accessFlagSynchronized|accessFlagNative) != 0 {
+			return false
+		}
+	}
+
+	// ACC_STRICT means nothing from Java 17, where every method is strict
+	if accessFlags&accessFlagAbstract != 0 {
+		otherFlags := uint16(accessFlagPrivate | accessFlagStatic | accessFlagFinal | accessFlagSynchronized | accessFlagNative)
+
+		if classFile.majorVersion >= 46 && classFile.majorVersion < 61 {
+			otherFlags |= accessFlagStrict
+		}
+
+		return accessFlags&otherFlags == 0
+	}
+
+	return true
+}
+
+// At most one of public, private and protected, exactly one if isRequired
+func hasOneVisibility(accessFlags uint16, isRequired bool) bool {
+	visibilitiesCount := 0
+
+	for _, visibilityFlag := range []uint16{accessFlagPublic, accessFlagPrivate, accessFlagProtected} {
+		if accessFlags&visibilityFlag != 0 {
+			visibilitiesCount++
+		}
+	}
+
+	return visibilitiesCount == 1 || visibilitiesCount == 0 && !isRequired
+}
+
+// Names of fields, methods and the parts of class names
+func isValidUnqualifiedName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, ".;[/")
+}
+
+func isValidMethodName(name string) bool {
+	return name == "<init>" || name == "<clinit>" || isValidUnqualifiedName(name) && !strings.ContainsAny(name, "<>")
+}
+
+// Class constants name array classes by their descriptors
+func isValidClassName(className string) bool {
+	if strings.HasPrefix(className, "[") {
+		return isValidFieldDescriptor(className)
+	}
+
+	for _, part := range strings.Split(className, "/") {
+		if !isValidUnqualifiedName(part) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isValidFieldDescriptor(descriptor string) bool {
+	return parseFieldDescriptor(descriptor, 0) == len(descriptor)
+}
+
+// Index after the field type starting at index, -1 if there is none
+func parseFieldDescriptor(descriptor string, index int) int {
+	dimensions := 0
+
+	for index < len(descriptor) && descriptor[index] == '[' {
+		dimensions++
+		index++
+	}
+
+	if index >= len(descriptor) || dimensions > 255 {
+		return -1
+	}
+
+	switch descriptor[index] {
+	case 'B', 'C', 'D', 'F', 'I', 'J', 'S', 'Z':
+		return index + 1
+	case 'L':
+		endIndex := strings.IndexByte(descriptor[index:], ';')
+
+		if endIndex < 0 || !isValidClassName(descriptor[index+1:index+endIndex]) {
+			return -1
+		}
+
+		return index + endIndex + 1
+	}
+
+	return -1
+}
+
+// Local variable slots the parameters of a method descriptor take, -1 if the descriptor is malformed
+func getParameterSlotsCount(descriptor string) int {
+	if !strings.HasPrefix(descriptor, "(") {
+		return -1
+	}
+
+	parameterSlotsCount := 0
+	index := 1
+
+	for index < len(descriptor) && descriptor[index] != ')' {
+		nextIndex := parseFieldDescriptor(descriptor, index)
+
+		if nextIndex < 0 {
+			return -1
+		}
+
+		if nextIndex == index+1 && (descriptor[index] == 'J' || descriptor[index] == 'D') {
+			parameterSlotsCount += 2
+		} else {
+			parameterSlotsCount++
+		}
+
+		index = nextIndex
+	}
+
+	if index >= len(descriptor) {
+		return -1
+	}
+
+	returnType := descriptor[index+1:]
+
+	if returnType != "V" && !isValidFieldDescriptor(returnType) {
+		return -1
+	}
+
+	return parameterSlotsCount
+}
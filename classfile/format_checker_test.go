@@ -0,0 +1,200 @@
+package classfile
+
+import "testing"
+
+func TestGetParameterSlotsCount(t *testing.T) {
+	tests := []struct {
+		descriptor string
+		slotsCount int
+	}{
+		{"()V", 0},
+		{"(I)V", 1},
+		{"(JD)V", 4},
+		{"([J)V", 1},
+		{"([[D)V", 1},
+		{"(Ljava/lang/String;J)I", 3},
+		{"(IJLjava/lang/Object;[I)V", 5},
+		{"I", -1},
+		{"(", -1},
+		{"(I)", -1},
+		{"(I)Q", -1},
+		{"(I)[V", -1},
+		{"(Q)V", -1},
+		{"(Ljava/lang/String)V", -1},
+		{"(L;)V", -1},
+		{"(Ljava//String;)V", -1},
+		{"([)V", -1},
+	}
+
+	for _, test := range tests {
+		slotsCount := getParameterSlotsCount(test.descriptor)
+
+		if slotsCount != test.slotsCount {
+			t.Errorf("getParameterSlotsCount(%q) = %d, expected %d", test.descriptor, slotsCount, test.slotsCount)
+		}
+	}
+}
+
+func TestIsValidClassName(t *testing.T) {
+	tests := []struct {
+		className string
+		isValid   bool
+	}{
+		{"java/lang/Object", true},
+		{"Foo", true},
+		{"Foo$Bar", true},
+		{"[I", true},
+		{"[Ljava/lang/String;", true},
+		{"[[Ljava/lang/String;", true},
+		{"", false},
+		{"java.lang.Object", false},
+		{"java/lang/", false},
+		{"/Foo", false},
+		{"java//Foo", false},
+		{"Foo;", false},
+		{"[", false},
+		{"[Ljava/lang/String", false},
+		{"[V", false},
+		{"[Ljava/lang/String;I", false},
+	}
+
+	for _, test := range tests {
+		isValid := isValidClassName(test.className)
+
+		if isValid != test.isValid {
+			t.Errorf("isValidClassName(%q) = %t, expected %t", test.className, isValid, test.isValid)
+		}
+	}
+}
+
+func TestIsValidMethodName(t *testing.T) {
+	tests := []struct {
+		name    string
+		isValid bool
+	}{
+		{"main", true},
+		{"<init>", true},
+		{"<clinit>", true},
+		{"lambda$main$0", true},
+		{"", false},
+		{"<main>", false},
+		{"a.b", false},
+		{"a/b", false},
+		{"a;", false},
+		{"[a", false},
+	}
+
+	for _, test := range tests {
+		isValid := isValidMethodName(test.name)
+
+		if isValid != test.isValid {
+			t.Errorf("isValidMethodName(%q) = %t, expected %t", test.name, isValid, test.isValid)
+		}
+	}
+}
+
+func TestHasOneVisibility(t *testing.T) {
+	tests := []struct {
+		accessFlags uint16
+		isRequired  bool
+		isValid     bool
+	}{
+		{0, false, true},
+		{0, true, false},
+		{accessFlagPublic, true, true},
+		{accessFlagPrivate | accessFlagStatic, true, true},
+		{accessFlagProtected | accessFlagFinal, false, true},
+		{accessFlagPublic | accessFlagPrivate, false, false},
+		{accessFlagPublic | accessFlagProtected, true, false},
+		{accessFlagPublic | accessFlagPrivate | accessFlagProtected, false, false},
+	}
+
+	for _, test := range tests {
+		isValid := hasOneVisibility(test.accessFlags, test.isRequired)
+
+		if isValid != test.isValid {
+			t.Errorf("hasOneVisibility(%#x, %t) = %t, expected %t", test.accessFlags, test.isRequired, isValid, test.isValid)
+		}
+	}
+}
+
+func TestIsValidMethodAccessFlags(t *testing.T) {
+	tests := []struct {
+		majorVersion      uint16
+		name              string
+		accessFlags       uint16
+		isInterfaceMethod bool
+		isValid           bool
+	}{
+		{52, "run", accessFlagPublic, false, true},
+		{52, "run", accessFlagPublic | accessFlagPrivate, false, false},
+		{52, "<init>", accessFlagPublic | accessFlagVarargs, false, true},
+		{52, "<init>", accessFlagPublic | accessFlagStatic, false, false},
+		{52, "<init>", accessFlagPublic, true, false},
+		{52, "run", accessFlagPublic | accessFlagAbstract, false, true},
+		{52, "run", accessFlagPublic | accessFlagAbstract | accessFlagFinal, false, false},
+		{52, "run", accessFlagPrivate | accessFlagAbstract, false, false},
+		{52, "run", accessFlagPublic | accessFlagAbstract | accessFlagStrict, false, false},
+		{61, "run", accessFlagPublic | accessFlagAbstract | accessFlagStrict, false, true},
+		{45, "run", accessFlagPublic | accessFlagAbstract | accessFlagStrict, false, true},
+		// Interfaces get static, private and default methods in Java 8
+		{51, "run", accessFlagPublic | accessFlagAbstract, true, true},
+		{51, "run", accessFlagPublic | accessFlagStatic, true, false},
+		{52, "run", accessFlagPublic | accessFlagStatic, true, true},
+		{52, "run", accessFlagPrivate, true, true},
+		{52, "run", 0, true, false},
+		{52, "run", accessFlagProtected | accessFlagAbstract, true, false},
+		{52, "run", accessFlagPublic | accessFlagSynchronized, true, false},
+		{52, "run", accessFlagPublic | accessFlagFinal, true, false},
+	}
+
+	for _, test := range tests {
+		classFile := &ClassFile{majorVersion: test.majorVersion}
+		isValid := classFile.isValidMethodAccessFlags(test.name, test.accessFlags, test.isInterfaceMethod)
+
+		if isValid != test.isValid {
+			t.Errorf("version %d: isValidMethodAccessFlags(%q, %#x, %t) = %t, expected %t",
+				test.majorVersion, test.name, test.accessFlags, test.isInterfaceMethod, isValid, test.isValid)
+		}
+	}
+}
+
+func TestReadAndCheckVersion(t *testing.T) {
+	tests := []struct {
+		majorVersion uint16
+		minorVersion uint16
+		isSupported  bool
+	}{
+		{45, 3, true},
+		{52, 0, true},
+		{55, 7, true},
+		{56, 0, true},
+		{56, 65535, true},
+		{56, 1, false},
+		{69, 0, true},
+		{70, 0, false},
+		{44, 0, false},
+	}
+
+	for _, test := range tests {
+		isSupported := readVersion(test.majorVersion, test.minorVersion)
+
+		if isSupported != test.isSupported {
+			t.Errorf("version %d.%d: supported = %t, expected %t", test.majorVersion, test.minorVersion, isSupported, test.isSupported)
+		}
+	}
+}
+
+func readVersion(majorVersion, minorVersion uint16) (isSupported bool) {
+	classReader := &ClassReader{data: []byte{byte(minorVersion >> 8), byte(minorVersion), byte(majorVersion >> 8), byte(majorVersion)}}
+
+	defer func() {
+		if recover() != nil {
+			isSupported = false
+		}
+	}()
+
+	(&ClassFile{}).ReadAndCheckVersion(classReader)
+
+	return true
+}
@@ -0,0 +1,38 @@
+package classfile
+
+import "fmt"
+
+// Malformed class data, the class loader throws it as the Java exception it names
+type FormatError struct {
+	exceptionClassName string
+	message            string
+	offset             int
+}
+
+func newClassFormatError(offset int, format string, arguments ...interface{}) *FormatError {
+	return &FormatError{
+		exceptionClassName: "java/lang/ClassFormatError",
+		message:            fmt.Sprintf(format, arguments...),
+		offset:             offset,
+	}
+}
+
+func newUnsupportedClassVersionError(offset int, format string, arguments ...interface{}) *FormatError {
+	return &FormatError{
+		exceptionClassName: "java/lang/UnsupportedClassVersionError",
+		message:            fmt.Sprintf(format, arguments...),
+		offset:             offset,
+	}
+}
+
+func (formatError *FormatError) Error() string {
+	return fmt.Sprintf("%s at offset %d", formatError.message, formatError.offset)
+}
+
+func (formatError *FormatError) GetExceptionClassName() string {
+	return formatError.exceptionClassName
+}
+
+func (formatError *FormatError) GetOffset() int {
+	return formatError.offset
+}
@@ -0,0 +1,62 @@
+package classfile
+
+/*
+InnerClasses_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 number_of_classes;
+    {   u2 inner_class_info_index;
+        u2 outer_class_info_index;
+        u2 inner_name_index;
+        u2 inner_class_access_flags;
+    } classes[number_of_classes];
+}
+*/
+type InnerClassesAttribute struct {
+	classes []*InnerClassEntry
+}
+
+type InnerClassEntry struct {
+	innerClassInfoIndex   uint16
+	outerClassInfoIndex   uint16
+	innerNameIndex        uint16
+	innerClassAccessFlags uint16
+}
+
+func (innerClassesAttribute *InnerClassesAttribute) Read(classReader *ClassReader) {
+	classesCount := classReader.ReadUint16()
+	classes := make([]*InnerClassEntry, classesCount)
+
+	for i := range classes {
+		classes[i] = &InnerClassEntry{
+			innerClassInfoIndex:   classReader.ReadUint16(),
+			outerClassInfoIndex:   classReader.ReadUint16(),
+			innerNameIndex:        classReader.ReadUint16(),
+			innerClassAccessFlags: classReader.ReadUint16(),
+		}
+	}
+
+	innerClassesAttribute.classes = classes
+}
+
+func (innerClassesAttribute *InnerClassesAttribute) GetClasses() []*InnerClassEntry {
+	return innerClassesAttribute.classes
+}
+
+func (innerClassEntry *InnerClassEntry) GetInnerClassInfoIndex() uint16 {
+	return innerClassEntry.innerClassInfoIndex
+}
+
+// 0 for local and anonymous classes
+func (innerClassEntry *InnerClassEntry) GetOuterClassInfoIndex() uint16 {
+	return innerClassEntry.outerClassInfoIndex
+}
+
+// 0 for anonymous classes
+func (innerClassEntry *InnerClassEntry) GetInnerNameIndex() uint16 {
+	return innerClassEntry.innerNameIndex
+}
+
+func (innerClassEntry *InnerClassEntry) GetInnerClassAccessFlags() uint16 {
+	return innerClassEntry.innerClassAccessFlags
+}
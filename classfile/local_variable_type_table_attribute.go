@@ -0,0 +1,67 @@
+package classfile
+
+/*
+LocalVariableTypeTable_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 local_variable_type_table_length;
+    {   u2 start_pc;
+        u2 length;
+        u2 name_index;
+        u2 signature_index;
+        u2 index;
+    } local_variable_type_table[local_variable_type_table_length];
+}
+*/
+type LocalVariableTypeTableAttribute struct {
+	localVariableTypeTable []*LocalVariableTypeTableEntry
+}
+
+type LocalVariableTypeTableEntry struct {
+	startPC        uint16
+	length         uint16
+	nameIndex      uint16
+	signatureIndex uint16
+	index          uint16
+}
+
+func (localVariableTypeTableAttribute *LocalVariableTypeTableAttribute) Read(classReader *ClassReader) {
+	localVariableTypeTableLength := classReader.ReadUint16()
+	localVariableTypeTable := make([]*LocalVariableTypeTableEntry, localVariableTypeTableLength)
+
+	for i := range localVariableTypeTable {
+		localVariableTypeTable[i] = &LocalVariableTypeTableEntry{
+			startPC:        classReader.ReadUint16(),
+			length:         classReader.ReadUint16(),
+			nameIndex:      classReader.ReadUint16(),
+			signatureIndex: classReader.ReadUint16(),
+			index:          classReader.ReadUint16(),
+		}
+	}
+
+	localVariableTypeTableAttribute.localVariableTypeTable = localVariableTypeTable
+}
+
+func (localVariableTypeTableAttribute *LocalVariableTypeTableAttribute) GetLocalVariableTypeTable() []*LocalVariableTypeTableEntry {
+	return localVariableTypeTableAttribute.localVariableTypeTable
+}
+
+func (localVariableTypeTableEntry *LocalVariableTypeTableEntry) GetStartPC() uint16 {
+	return localVariableTypeTableEntry.startPC
+}
+
+func (localVariableTypeTableEntry *LocalVariableTypeTableEntry) GetLength() uint16 {
+	return localVariableTypeTableEntry.length
+}
+
+func (localVariableTypeTableEntry *LocalVariableTypeTableEntry) GetNameIndex() uint16 {
+	return localVariableTypeTableEntry.nameIndex
+}
+
+func (localVariableTypeTableEntry *LocalVariableTypeTableEntry) GetSignatureIndex() uint16 {
+	return localVariableTypeTableEntry.signatureIndex
+}
+
+func (localVariableTypeTableEntry *LocalVariableTypeTableEntry) GetIndex() uint16 {
+	return localVariableTypeTableEntry.index
+}
@@ -19,6 +19,7 @@ method_info {
 */
 type MemberInfo struct {
 	constantPool    ConstantPool
+	offset          int
 	accessFlags     uint16
 	nameIndex       uint16
 	descriptorIndex uint16
@@ -39,6 +40,7 @@ func readMembers(classReader *ClassReader, constantPool ConstantPool) []*MemberI
 func readMember(classReader *ClassReader, constantPool ConstantPool) *MemberInfo {
 	return &MemberInfo{
 		constantPool:    constantPool,
+		offset:          classReader.GetOffset(),
 		accessFlags:     classReader.ReadUint16(),
 		nameIndex:       classReader.ReadUint16(),
 		descriptorIndex: classReader.ReadUint16(),
@@ -79,3 +81,80 @@ func (memberInfo *MemberInfo) GetConstantValueAttribute() *ConstantValueAttribut
 
 	return nil
 }
+
+func (memberInfo *MemberInfo) GetExceptionsAttribute() *ExceptionsAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *ExceptionsAttribute:
+			return attribute.(*ExceptionsAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (memberInfo *MemberInfo) GetSignatureAttribute() *SignatureAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *SignatureAttribute:
+			return attribute.(*SignatureAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (memberInfo *MemberInfo) GetMethodParametersAttribute() *MethodParametersAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *MethodParametersAttribute:
+			return attribute.(*MethodParametersAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (memberInfo *MemberInfo) GetRuntimeVisibleAnnotationsAttribute() *RuntimeVisibleAnnotationsAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *RuntimeVisibleAnnotationsAttribute:
+			return attribute.(*RuntimeVisibleAnnotationsAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (memberInfo *MemberInfo) GetRuntimeVisibleParameterAnnotationsAttribute() *RuntimeVisibleParameterAnnotationsAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *RuntimeVisibleParameterAnnotationsAttribute:
+			return attribute.(*RuntimeVisibleParameterAnnotationsAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (memberInfo *MemberInfo) GetRuntimeVisibleTypeAnnotationsAttribute() *RuntimeVisibleTypeAnnotationsAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *RuntimeVisibleTypeAnnotationsAttribute:
+			return attribute.(*RuntimeVisibleTypeAnnotationsAttribute)
+		}
+	}
+
+	return nil
+}
+
+func (memberInfo *MemberInfo) GetAnnotationDefaultAttribute() *AnnotationDefaultAttribute {
+	for _, attribute := range memberInfo.attributes {
+		switch attribute.(type) {
+		case *AnnotationDefaultAttribute:
+			return attribute.(*AnnotationDefaultAttribute)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package classfile
+
+/*
+MethodParameters_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u1 parameters_count;
+    {   u2 name_index;
+        u2 access_flags;
+    } parameters[parameters_count];
+}
+*/
+type MethodParametersAttribute struct {
+	parameters []*MethodParameter
+}
+
+type MethodParameter struct {
+	nameIndex   uint16
+	accessFlags uint16
+}
+
+func (methodParametersAttribute *MethodParametersAttribute) Read(classReader *ClassReader) {
+	parametersCount := classReader.ReadUint8()
+	parameters := make([]*MethodParameter, parametersCount)
+
+	for i := range parameters {
+		parameters[i] = &MethodParameter{
+			nameIndex:   classReader.ReadUint16(),
+			accessFlags: classReader.ReadUint16(),
+		}
+	}
+
+	methodParametersAttribute.parameters = parameters
+}
+
+func (methodParametersAttribute *MethodParametersAttribute) GetParameters() []*MethodParameter {
+	return methodParametersAttribute.parameters
+}
+
+// 0 for a parameter without a name
+func (methodParameter *MethodParameter) GetNameIndex() uint16 {
+	return methodParameter.nameIndex
+}
+
+func (methodParameter *MethodParameter) GetAccessFlags() uint16 {
+	return methodParameter.accessFlags
+}
@@ -0,0 +1,148 @@
+package classfile
+
+/*
+Module_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+
+    u2 module_name_index;
+    u2 module_flags;
+    u2 module_version_index;
+
+    u2 requires_count;
+    {   u2 requires_index;
+        u2 requires_flags;
+        u2 requires_version_index;
+    } requires[requires_count];
+
+    u2 exports_count;
+    {   u2 exports_index;
+        u2 exports_flags;
+        u2 exports_to_count;
+        u2 exports_to_index[exports_to_count];
+    } exports[exports_count];
+
+    u2 opens_count;
+    {   u2 opens_index;
+        u2 opens_flags;
+        u2 opens_to_count;
+        u2 opens_to_index[opens_to_count];
+    } opens[opens_count];
+
+    u2 uses_count;
+    u2 uses_index[uses_count];
+
+    u2 provides_count;
+    {   u2 provides_index;
+        u2 provides_with_count;
+        u2 provides_with_index[provides_with_count];
+    } provides[provides_count];
+}
+*/
+type ModuleAttribute struct {
+	constantPool       ConstantPool
+	moduleNameIndex    uint16
+	moduleFlags        uint16
+	moduleVersionIndex uint16
+	requires           []*ModuleRequiresEntry
+	exports            []*ModuleExportsEntry
+	opens              []*ModuleExportsEntry
+	usesIndices        []uint16
+	provides           []*ModuleProvidesEntry
+}
+
+type ModuleRequiresEntry struct {
+	requiresIndex        uint16
+	requiresFlags        uint16
+	requiresVersionIndex uint16
+}
+
+// Used for both exports and opens, which share the same layout
+type ModuleExportsEntry struct {
+	packageIndex    uint16
+	flags           uint16
+	toModuleIndices []uint16
+}
+
+type ModuleProvidesEntry struct {
+	providesIndex       uint16
+	providesWithIndices []uint16
+}
+
+func (moduleAttribute *ModuleAttribute) Read(classReader *ClassReader) {
+	moduleAttribute.moduleNameIndex = classReader.ReadUint16()
+	moduleAttribute.moduleFlags = classReader.ReadUint16()
+	moduleAttribute.moduleVersionIndex = classReader.ReadUint16()
+
+	requiresCount := classReader.ReadUint16()
+	moduleAttribute.requires = make([]*ModuleRequiresEntry, requiresCount)
+
+	for i := range moduleAttribute.requires {
+		moduleAttribute.requires[i] = &ModuleRequiresEntry{
+			requiresIndex:        classReader.ReadUint16(),
+			requiresFlags:        classReader.ReadUint16(),
+			requiresVersionIndex: classReader.ReadUint16(),
+		}
+	}
+
+	moduleAttribute.exports = readModuleExportsEntries(classReader)
+	moduleAttribute.opens = readModuleExportsEntries(classReader)
+	moduleAttribute.usesIndices = classReader.ReadUint16Table()
+
+	providesCount := classReader.ReadUint16()
+	moduleAttribute.provides = make([]*ModuleProvidesEntry, providesCount)
+
+	for i := range moduleAttribute.provides {
+		moduleAttribute.provides[i] = &ModuleProvidesEntry{
+			providesIndex:       classReader.ReadUint16(),
+			providesWithIndices: classReader.ReadUint16Table(),
+		}
+	}
+}
+
+func readModuleExportsEntries(classReader *ClassReader) []*ModuleExportsEntry {
+	entriesCount := classReader.ReadUint16()
+	entries := make([]*ModuleExportsEntry, entriesCount)
+
+	for i := range entries {
+		entries[i] = &ModuleExportsEntry{
+			packageIndex:    classReader.ReadUint16(),
+			flags:           classReader.ReadUint16(),
+			toModuleIndices: classReader.ReadUint16Table(),
+		}
+	}
+
+	return entries
+}
+
+func (moduleAttribute *ModuleAttribute) GetModuleName() string {
+	constantModuleInfo := moduleAttribute.constantPool.GetConstantInfo(moduleAttribute.moduleNameIndex).(*ConstantModuleInfo)
+
+	return constantModuleInfo.GetName()
+}
+
+func (moduleAttribute *ModuleAttribute) GetModuleFlags() uint16 {
+	return moduleAttribute.moduleFlags
+}
+
+func (moduleAttribute *ModuleAttribute) GetRequiredModuleNames() []string {
+	requiredModuleNames := make([]string, len(moduleAttribute.requires))
+
+	for i, requiresEntry := range moduleAttribute.requires {
+		constantModuleInfo := moduleAttribute.constantPool.GetConstantInfo(requiresEntry.requiresIndex).(*ConstantModuleInfo)
+		requiredModuleNames[i] = constantModuleInfo.GetName()
+	}
+
+	return requiredModuleNames
+}
+
+func (moduleAttribute *ModuleAttribute) GetExportedPackageNames() []string {
+	exportedPackageNames := make([]string, len(moduleAttribute.exports))
+
+	for i, exportsEntry := range moduleAttribute.exports {
+		constantPackageInfo := moduleAttribute.constantPool.GetConstantInfo(exportsEntry.packageIndex).(*ConstantPackageInfo)
+		exportedPackageNames[i] = constantPackageInfo.GetName()
+	}
+
+	return exportedPackageNames
+}
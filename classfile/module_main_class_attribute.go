@@ -0,0 +1,21 @@
+package classfile
+
+/*
+ModuleMainClass_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 main_class_index;
+}
+*/
+type ModuleMainClassAttribute struct {
+	constantPool   ConstantPool
+	mainClassIndex uint16
+}
+
+func (moduleMainClassAttribute *ModuleMainClassAttribute) Read(classReader *ClassReader) {
+	moduleMainClassAttribute.mainClassIndex = classReader.ReadUint16()
+}
+
+func (moduleMainClassAttribute *ModuleMainClassAttribute) GetMainClassName() string {
+	return moduleMainClassAttribute.constantPool.GetClassName(moduleMainClassAttribute.mainClassIndex)
+}
@@ -0,0 +1,29 @@
+package classfile
+
+/*
+ModulePackages_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 package_count;
+    u2 package_index[package_count];
+}
+*/
+type ModulePackagesAttribute struct {
+	constantPool   ConstantPool
+	packageIndices []uint16
+}
+
+func (modulePackagesAttribute *ModulePackagesAttribute) Read(classReader *ClassReader) {
+	modulePackagesAttribute.packageIndices = classReader.ReadUint16Table()
+}
+
+func (modulePackagesAttribute *ModulePackagesAttribute) GetPackageNames() []string {
+	packageNames := make([]string, len(modulePackagesAttribute.packageIndices))
+
+	for i, packageIndex := range modulePackagesAttribute.packageIndices {
+		constantPackageInfo := modulePackagesAttribute.constantPool.GetConstantInfo(packageIndex).(*ConstantPackageInfo)
+		packageNames[i] = constantPackageInfo.GetName()
+	}
+
+	return packageNames
+}
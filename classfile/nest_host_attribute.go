@@ -0,0 +1,21 @@
+package classfile
+
+/*
+NestHost_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 host_class_index;
+}
+*/
+type NestHostAttribute struct {
+	constantPool   ConstantPool
+	hostClassIndex uint16
+}
+
+func (nestHostAttribute *NestHostAttribute) Read(classReader *ClassReader) {
+	nestHostAttribute.hostClassIndex = classReader.ReadUint16()
+}
+
+func (nestHostAttribute *NestHostAttribute) GetHostClassName() string {
+	return nestHostAttribute.constantPool.GetClassName(nestHostAttribute.hostClassIndex)
+}
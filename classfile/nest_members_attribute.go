@@ -0,0 +1,28 @@
+package classfile
+
+/*
+NestMembers_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 number_of_classes;
+    u2 classes[number_of_classes];
+}
+*/
+type NestMembersAttribute struct {
+	constantPool ConstantPool
+	classIndices []uint16
+}
+
+func (nestMembersAttribute *NestMembersAttribute) Read(classReader *ClassReader) {
+	nestMembersAttribute.classIndices = classReader.ReadUint16Table()
+}
+
+func (nestMembersAttribute *NestMembersAttribute) GetClassNames() []string {
+	classNames := make([]string, len(nestMembersAttribute.classIndices))
+
+	for i, classIndex := range nestMembersAttribute.classIndices {
+		classNames[i] = nestMembersAttribute.constantPool.GetClassName(classIndex)
+	}
+
+	return classNames
+}
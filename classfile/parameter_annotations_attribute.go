@@ -0,0 +1,44 @@
+package classfile
+
+/*
+RuntimeVisibleParameterAnnotations_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u1 num_parameters;
+    {   u2         num_annotations;
+        annotation annotations[num_annotations];
+    } parameter_annotations[num_parameters];
+}
+*/
+type ParameterAnnotationsAttribute struct {
+	data                 []byte
+	parameterAnnotations [][]*Annotation
+}
+
+type RuntimeVisibleParameterAnnotationsAttribute struct {
+	ParameterAnnotationsAttribute
+}
+
+type RuntimeInvisibleParameterAnnotationsAttribute struct {
+	ParameterAnnotationsAttribute
+}
+
+func (parameterAnnotationsAttribute *ParameterAnnotationsAttribute) Read(classReader *ClassReader) {
+	data := classReader.data
+	parametersCount := classReader.ReadUint8()
+	parameterAnnotationsAttribute.parameterAnnotations = make([][]*Annotation, parametersCount)
+
+	for i := range parameterAnnotationsAttribute.parameterAnnotations {
+		parameterAnnotationsAttribute.parameterAnnotations[i] = readAnnotations(classReader)
+	}
+
+	parameterAnnotationsAttribute.data = data[:len(data)-len(classReader.data)]
+}
+
+func (parameterAnnotationsAttribute *ParameterAnnotationsAttribute) GetData() []byte {
+	return parameterAnnotationsAttribute.data
+}
+
+func (parameterAnnotationsAttribute *ParameterAnnotationsAttribute) GetParameterAnnotations() [][]*Annotation {
+	return parameterAnnotationsAttribute.parameterAnnotations
+}
@@ -0,0 +1,28 @@
+package classfile
+
+/*
+PermittedSubclasses_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 number_of_classes;
+    u2 classes[number_of_classes];
+}
+*/
+type PermittedSubclassesAttribute struct {
+	constantPool ConstantPool
+	classIndices []uint16
+}
+
+func (permittedSubclassesAttribute *PermittedSubclassesAttribute) Read(classReader *ClassReader) {
+	permittedSubclassesAttribute.classIndices = classReader.ReadUint16Table()
+}
+
+func (permittedSubclassesAttribute *PermittedSubclassesAttribute) GetClassNames() []string {
+	classNames := make([]string, len(permittedSubclassesAttribute.classIndices))
+
+	for i, classIndex := range permittedSubclassesAttribute.classIndices {
+		classNames[i] = permittedSubclassesAttribute.constantPool.GetClassName(classIndex)
+	}
+
+	return classNames
+}
@@ -0,0 +1,67 @@
+package classfile
+
+/*
+Record_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 components_count;
+    record_component_info components[components_count];
+}
+
+record_component_info {
+    u2 name_index;
+    u2 descriptor_index;
+    u2 attributes_count;
+    attribute_info attributes[attributes_count];
+}
+*/
+type RecordAttribute struct {
+	constantPool ConstantPool
+	components   []*RecordComponentInfo
+}
+
+type RecordComponentInfo struct {
+	constantPool    ConstantPool
+	nameIndex       uint16
+	descriptorIndex uint16
+	attributes      []AttributeInfo
+}
+
+func (recordAttribute *RecordAttribute) Read(classReader *ClassReader) {
+	componentsCount := classReader.ReadUint16()
+	components := make([]*RecordComponentInfo, componentsCount)
+
+	for i := range components {
+		components[i] = &RecordComponentInfo{
+			constantPool:    recordAttribute.constantPool,
+			nameIndex:       classReader.ReadUint16(),
+			descriptorIndex: classReader.ReadUint16(),
+			attributes:      readAttributes(classReader, recordAttribute.constantPool),
+		}
+	}
+
+	recordAttribute.components = components
+}
+
+func (recordAttribute *RecordAttribute) GetComponents() []*RecordComponentInfo {
+	return recordAttribute.components
+}
+
+func (recordComponentInfo *RecordComponentInfo) GetName() string {
+	return recordComponentInfo.constantPool.GetUtf8String(recordComponentInfo.nameIndex)
+}
+
+func (recordComponentInfo *RecordComponentInfo) GetDescriptor() string {
+	return recordComponentInfo.constantPool.GetUtf8String(recordComponentInfo.descriptorIndex)
+}
+
+func (recordComponentInfo *RecordComponentInfo) GetSignatureAttribute() *SignatureAttribute {
+	for _, attributeInfo := range recordComponentInfo.attributes {
+		switch attributeInfo.(type) {
+		case *SignatureAttribute:
+			return attributeInfo.(*SignatureAttribute)
+		}
+	}
+
+	return nil
+}
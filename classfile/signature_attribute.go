@@ -0,0 +1,21 @@
+package classfile
+
+/*
+Signature_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u2 signature_index;
+}
+*/
+type SignatureAttribute struct {
+	constantPool   ConstantPool
+	signatureIndex uint16
+}
+
+func (signatureAttribute *SignatureAttribute) Read(classReader *ClassReader) {
+	signatureAttribute.signatureIndex = classReader.ReadUint16()
+}
+
+func (signatureAttribute *SignatureAttribute) GetSignature() string {
+	return signatureAttribute.constantPool.GetUtf8String(signatureAttribute.signatureIndex)
+}
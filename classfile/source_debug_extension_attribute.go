@@ -0,0 +1,22 @@
+package classfile
+
+/*
+SourceDebugExtension_attribute {
+    u2 attribute_name_index;
+    u4 attribute_length;
+    u1 debug_extension[attribute_length];
+}
+*/
+type SourceDebugExtensionAttribute struct {
+	debugExtensionLength uint32
+	debugExtension       []byte
+}
+
+func (sourceDebugExtensionAttribute *SourceDebugExtensionAttribute) Read(classReader *ClassReader) {
+	sourceDebugExtensionAttribute.debugExtension = classReader.ReadBytes(sourceDebugExtensionAttribute.debugExtensionLength)
+}
+
+// The extension is modified UTF-8 without the length prefix of a Utf8 constant
+func (sourceDebugExtensionAttribute *SourceDebugExtensionAttribute) GetDebugExtension() string {
+	return decodeMUTF8(sourceDebugExtensionAttribute.debugExtension)
+}
@@ -0,0 +1,144 @@
+package classfile
+
+/*
+StackMapTable_attribute {
+    u2              attribute_name_index;
+    u4              attribute_length;
+    u2              number_of_entries;
+    stack_map_frame entries[number_of_entries];
+}
+
+union stack_map_frame {
+    same_frame;                           // frame_type = 0-63
+    same_locals_1_stack_item_frame;       // frame_type = 64-127
+    same_locals_1_stack_item_frame_extended; // frame_type = 247
+    chop_frame;                           // frame_type = 248-250
+    same_frame_extended;                  // frame_type = 251
+    append_frame;                         // frame_type = 252-254
+    full_frame;                           // frame_type = 255
+}
+*/
+type StackMapTableAttribute struct {
+	entries []*StackMapFrame
+}
+
+// Every kind of frame is read into the shape of a full_frame, the fields it does not have are empty
+type StackMapFrame struct {
+	frameType   uint8
+	offsetDelta uint16
+	locals      []*VerificationTypeInfo
+	stack       []*VerificationTypeInfo
+}
+
+/*
+union verification_type_info {
+    Top_variable_info;               // tag = 0
+    Integer_variable_info;           // tag = 1
+    Float_variable_info;             // tag = 2
+    Double_variable_info;            // tag = 3
+    Long_variable_info;              // tag = 4
+    Null_variable_info;              // tag = 5
+    UninitializedThis_variable_info; // tag = 6
+    Object_variable_info;            // tag = 7, u2 cpool_index
+    Uninitialized_variable_info;     // tag = 8, u2 offset
+}
+*/
+const (
+	ITEM_Top               = 0
+	ITEM_Integer           = 1
+	ITEM_Float             = 2
+	ITEM_Double            = 3
+	ITEM_Long              = 4
+	ITEM_Null              = 5
+	ITEM_UninitializedThis = 6
+	ITEM_Object            = 7
+	ITEM_Uninitialized     = 8
+)
+
+type VerificationTypeInfo struct {
+	tag uint8
+	// cpool_index of Object_variable_info, offset of Uninitialized_variable_info
+	value uint16
+}
+
+func (stackMapTableAttribute *StackMapTableAttribute) Read(classReader *ClassReader) {
+	numberOfEntries := classReader.ReadUint16()
+	stackMapTableAttribute.entries = make([]*StackMapFrame, numberOfEntries)
+
+	for i := range stackMapTableAttribute.entries {
+		stackMapTableAttribute.entries[i] = readStackMapFrame(classReader)
+	}
+}
+
+func readStackMapFrame(classReader *ClassReader) *StackMapFrame {
+	frameType := classReader.ReadUint8()
+	stackMapFrame := &StackMapFrame{frameType: frameType}
+
+	switch {
+	case frameType <= 63:
+		stackMapFrame.offsetDelta = uint16(frameType)
+	case frameType <= 127:
+		stackMapFrame.offsetDelta = uint16(frameType - 64)
+		stackMapFrame.stack = readVerificationTypeInfos(classReader, 1)
+	case frameType == 247:
+		stackMapFrame.offsetDelta = classReader.ReadUint16()
+		stackMapFrame.stack = readVerificationTypeInfos(classReader, 1)
+	case frameType >= 248 && frameType <= 251:
+		stackMapFrame.offsetDelta = classReader.ReadUint16()
+	case frameType >= 252 && frameType <= 254:
+		stackMapFrame.offsetDelta = classReader.ReadUint16()
+		stackMapFrame.locals = readVerificationTypeInfos(classReader, uint16(frameType-251))
+	case frameType == 255:
+		stackMapFrame.offsetDelta = classReader.ReadUint16()
+		stackMapFrame.locals = readVerificationTypeInfos(classReader, classReader.ReadUint16())
+		stackMapFrame.stack = readVerificationTypeInfos(classReader, classReader.ReadUint16())
+	default:
+		// 128-246 are reserved, the verifier rejects them
+	}
+
+	return stackMapFrame
+}
+
+func readVerificationTypeInfos(classReader *ClassReader, count uint16) []*VerificationTypeInfo {
+	verificationTypeInfos := make([]*VerificationTypeInfo, count)
+
+	for i := range verificationTypeInfos {
+		verificationTypeInfo := &VerificationTypeInfo{tag: classReader.ReadUint8()}
+
+		if verificationTypeInfo.tag == ITEM_Object || verificationTypeInfo.tag == ITEM_Uninitialized {
+			verificationTypeInfo.value = classReader.ReadUint16()
+		}
+
+		verificationTypeInfos[i] = verificationTypeInfo
+	}
+
+	return verificationTypeInfos
+}
+
+func (stackMapTableAttribute *StackMapTableAttribute) GetEntries() []*StackMapFrame {
+	return stackMapTableAttribute.entries
+}
+
+func (stackMapFrame *StackMapFrame) GetFrameType() uint8 {
+	return stackMapFrame.frameType
+}
+
+func (stackMapFrame *StackMapFrame) GetOffsetDelta() uint16 {
+	return stackMapFrame.offsetDelta
+}
+
+func (stackMapFrame *StackMapFrame) GetLocals() []*VerificationTypeInfo {
+	return stackMapFrame.locals
+}
+
+func (stackMapFrame *StackMapFrame) GetStack() []*VerificationTypeInfo {
+	return stackMapFrame.stack
+}
+
+func (verificationTypeInfo *VerificationTypeInfo) GetTag() uint8 {
+	return verificationTypeInfo.tag
+}
+
+func (verificationTypeInfo *VerificationTypeInfo) GetValue() uint16 {
+	return verificationTypeInfo.value
+}
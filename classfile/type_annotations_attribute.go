@@ -0,0 +1,191 @@
+package classfile
+
+/*
+RuntimeVisibleTypeAnnotations_attribute {
+    u2              attribute_name_index;
+    u4              attribute_length;
+    u2              num_annotations;
+    type_annotation annotations[num_annotations];
+}
+
+type_annotation {
+    u1 target_type;
+    union {
+        type_parameter_target;
+        supertype_target;
+        type_parameter_bound_target;
+        empty_target;
+        formal_parameter_target;
+        throws_target;
+        localvar_target;
+        catch_target;
+        offset_target;
+        type_argument_target;
+    } target_info;
+    type_path target_path;
+    u2        type_index;
+    u2        num_element_value_pairs;
+    {   u2            element_name_index;
+        element_value value;
+    } element_value_pairs[num_element_value_pairs];
+}
+
+type_path {
+    u1 path_length;
+    {   u1 type_path_kind;
+        u1 type_argument_index;
+    } path[path_length];
+}
+*/
+type TypeAnnotationsAttribute struct {
+	data            []byte
+	typeAnnotations []*TypeAnnotation
+}
+
+type RuntimeVisibleTypeAnnotationsAttribute struct {
+	TypeAnnotationsAttribute
+}
+
+type RuntimeInvisibleTypeAnnotationsAttribute struct {
+	TypeAnnotationsAttribute
+}
+
+type TypeAnnotation struct {
+	targetType uint8
+	// The index or bytecode offset of target_info, what it means depends on target_type
+	targetIndex uint16
+	// bound_index of type_parameter_bound_target, type_argument_index of type_argument_target
+	targetArgumentIndex  uint8
+	localVariableTargets []*LocalVariableTarget
+	targetPath           []*TypePathEntry
+	annotation           *Annotation
+}
+
+// Range of code a local variable lives in, from localvar_target
+type LocalVariableTarget struct {
+	startPC uint16
+	length  uint16
+	index   uint16
+}
+
+type TypePathEntry struct {
+	typePathKind      uint8
+	typeArgumentIndex uint8
+}
+
+func (typeAnnotationsAttribute *TypeAnnotationsAttribute) Read(classReader *ClassReader) {
+	data := classReader.data
+	typeAnnotationsCount := classReader.ReadUint16()
+	typeAnnotationsAttribute.typeAnnotations = make([]*TypeAnnotation, typeAnnotationsCount)
+
+	for i := range typeAnnotationsAttribute.typeAnnotations {
+		typeAnnotationsAttribute.typeAnnotations[i] = readTypeAnnotation(classReader)
+	}
+
+	typeAnnotationsAttribute.data = data[:len(data)-len(classReader.data)]
+}
+
+func readTypeAnnotation(classReader *ClassReader) *TypeAnnotation {
+	typeAnnotation := &TypeAnnotation{
+		targetType: classReader.ReadUint8(),
+	}
+
+	switch typeAnnotation.targetType {
+	case 0x00, 0x01, 0x16:
+		// type_parameter_target and formal_parameter_target
+		typeAnnotation.targetIndex = uint16(classReader.ReadUint8())
+	case 0x10, 0x17, 0x42, 0x43, 0x44, 0x45, 0x46:
+		// supertype_target, throws_target, catch_target and offset_target
+		typeAnnotation.targetIndex = classReader.ReadUint16()
+	case 0x11, 0x12:
+		// type_parameter_bound_target
+		typeAnnotation.targetIndex = uint16(classReader.ReadUint8())
+		typeAnnotation.targetArgumentIndex = classReader.ReadUint8()
+	case 0x13, 0x14, 0x15:
+		// empty_target
+	case 0x40, 0x41:
+		// localvar_target
+		tableLength := classReader.ReadUint16()
+		typeAnnotation.localVariableTargets = make([]*LocalVariableTarget, tableLength)
+
+		for i := range typeAnnotation.localVariableTargets {
+			typeAnnotation.localVariableTargets[i] = &LocalVariableTarget{
+				startPC: classReader.ReadUint16(),
+				length:  classReader.ReadUint16(),
+				index:   classReader.ReadUint16(),
+			}
+		}
+	case 0x47, 0x48, 0x49, 0x4a, 0x4b:
+		// type_argument_target
+		typeAnnotation.targetIndex = classReader.ReadUint16()
+		typeAnnotation.targetArgumentIndex = classReader.ReadUint8()
+	default:
+		panic(newClassFormatError(classReader.GetOffset()-1, "Unknown type annotation target type 0x%X in class file", typeAnnotation.targetType))
+	}
+
+	pathLength := classReader.ReadUint8()
+	typeAnnotation.targetPath = make([]*TypePathEntry, pathLength)
+
+	for i := range typeAnnotation.targetPath {
+		typeAnnotation.targetPath[i] = &TypePathEntry{
+			typePathKind:      classReader.ReadUint8(),
+			typeArgumentIndex: classReader.ReadUint8(),
+		}
+	}
+
+	typeAnnotation.annotation = readAnnotation(classReader)
+
+	return typeAnnotation
+}
+
+func (typeAnnotationsAttribute *TypeAnnotationsAttribute) GetData() []byte {
+	return typeAnnotationsAttribute.data
+}
+
+func (typeAnnotationsAttribute *TypeAnnotationsAttribute) GetTypeAnnotations() []*TypeAnnotation {
+	return typeAnnotationsAttribute.typeAnnotations
+}
+
+func (typeAnnotation *TypeAnnotation) GetTargetType() uint8 {
+	return typeAnnotation.targetType
+}
+
+func (typeAnnotation *TypeAnnotation) GetTargetIndex() uint16 {
+	return typeAnnotation.targetIndex
+}
+
+func (typeAnnotation *TypeAnnotation) GetTargetArgumentIndex() uint8 {
+	return typeAnnotation.targetArgumentIndex
+}
+
+func (typeAnnotation *TypeAnnotation) GetLocalVariableTargets() []*LocalVariableTarget {
+	return typeAnnotation.localVariableTargets
+}
+
+func (typeAnnotation *TypeAnnotation) GetTargetPath() []*TypePathEntry {
+	return typeAnnotation.targetPath
+}
+
+func (typeAnnotation *TypeAnnotation) GetAnnotation() *Annotation {
+	return typeAnnotation.annotation
+}
+
+func (localVariableTarget *LocalVariableTarget) GetStartPC() uint16 {
+	return localVariableTarget.startPC
+}
+
+func (localVariableTarget *LocalVariableTarget) GetLength() uint16 {
+	return localVariableTarget.length
+}
+
+func (localVariableTarget *LocalVariableTarget) GetIndex() uint16 {
+	return localVariableTarget.index
+}
+
+func (typePathEntry *TypePathEntry) GetTypePathKind() uint8 {
+	return typePathEntry.typePathKind
+}
+
+func (typePathEntry *TypePathEntry) GetTypeArgumentIndex() uint8 {
+	return typePathEntry.typeArgumentIndex
+}
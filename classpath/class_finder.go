@@ -1,26 +1,118 @@
 package classpath
 
 import (
+	"bufio"
+	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/options"
 )
 
+// Java SE release of the runtime classes, used for multi-release jars
+var runtimeRelease = 8
+
 type ClassFinder struct {
 	bootstrapClasspathEntry  ClasspathEntry
 	extensionsClasspathEntry ClasspathEntry
 	userClasspathEntry       ClasspathEntry
+	javaHome                 string
+	classpath                string
 }
 
-func Parse(jrePath, classpath string) *ClassFinder {
+func Parse(jrePath, classpath string) (*ClassFinder, error) {
 	classFinder := &ClassFinder{}
-	classFinder.parseBootstrapAndExtensionsClasspathEntry(jrePath)
+	err := classFinder.parseBootstrapAndExtensionsClasspathEntry(jrePath)
+
+	if err != nil {
+		return nil, err
+	}
+
 	classFinder.parseUserClasspathEntry(classpath)
 
-	return classFinder
+	return classFinder, nil
+}
+
+func ParseJar(jrePath, jarPath string, manifest *Manifest) (*ClassFinder, error) {
+	classFinder := &ClassFinder{}
+	err := classFinder.parseBootstrapAndExtensionsClasspathEntry(jrePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	classFinder.userClasspathEntry = NewJarClasspathEntry(jarPath, manifest)
+	classFinder.classpath = jarPath
+
+	return classFinder, nil
+}
+
+// Every observable module is searched, followed by the class path
+func ParseModulePath(jrePath, classpath string, moduleClasspathEntries []*ModuleClasspathEntry) (*ClassFinder, error) {
+	classFinder := &ClassFinder{}
+	err := classFinder.parseBootstrapAndExtensionsClasspathEntry(jrePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	compositeClasspathEntry := CompositeClasspathEntry{}
+
+	for _, moduleClasspathEntry := range moduleClasspathEntries {
+		compositeClasspathEntry = append(compositeClasspathEntry, moduleClasspathEntry)
+	}
+
+	if classpath != "" {
+		compositeClasspathEntry = append(compositeClasspathEntry, NewClasspathEntry(classpath))
+	}
+
+	classFinder.userClasspathEntry = compositeClasspathEntry
+	classFinder.classpath = classpath
+
+	return classFinder, nil
 }
 
-func (classFinder *ClassFinder) parseBootstrapAndExtensionsClasspathEntry(jrePath string) {
-	jreDirectory := getJreDirectory(jrePath)
+// The runtime directory the boot classes are read from, java.home
+func (classFinder *ClassFinder) GetJavaHome() string {
+	return classFinder.javaHome
+}
+
+// The user class path as given on the command line, java.class.path
+func (classFinder *ClassFinder) GetClasspath() string {
+	return classFinder.classpath
+}
+
+func GetRuntimeRelease() int {
+	return runtimeRelease
+}
+
+func (classFinder *ClassFinder) parseBootstrapAndExtensionsClasspathEntry(jrePath string) error {
+	jreDirectory, err := getJreDirectory(jrePath)
+
+	if err != nil {
+		return err
+	}
+
+	javaHome, err := filepath.Abs(jreDirectory)
+
+	if err != nil {
+		javaHome = jreDirectory
+	}
+
+	classFinder.javaHome = javaHome
+
+	// Java 9+ runtime image: lib/modules
+	modulesPath := filepath.Join(jreDirectory, "lib", "modules")
+
+	if isFileExists(modulesPath) {
+		runtimeRelease = readRuntimeRelease(jreDirectory)
+		classFinder.bootstrapClasspathEntry = NewJImageClasspathEntry(modulesPath)
+		classFinder.extensionsClasspathEntry = CompositeClasspathEntry{}
+
+		return nil
+	}
 
 	// jre/lib/*
 	jreLibPath := filepath.Join(jreDirectory, "lib", "*")
@@ -29,6 +121,8 @@ func (classFinder *ClassFinder) parseBootstrapAndExtensionsClasspathEntry(jrePat
 	// jre/lib/ext/*
 	jreExtensionsPath := filepath.Join(jreDirectory, "lib", "ext", "*")
 	classFinder.extensionsClasspathEntry = NewWildcardClasspathEntry(jreExtensionsPath)
+
+	return nil
 }
 
 func (classFinder *ClassFinder) parseUserClasspathEntry(classpath string) {
@@ -36,25 +130,87 @@ func (classFinder *ClassFinder) parseUserClasspathEntry(classpath string) {
 		classpath = "."
 	}
 
+	classFinder.classpath = classpath
 	classFinder.userClasspathEntry = NewClasspathEntry(classpath)
 }
 
-func getJreDirectory(jrePath string) string {
+func getJreDirectory(jrePath string) (string, error) {
 	if jrePath != "" && isDirectoryExists(jrePath) {
-		return jrePath
+		return jrePath, nil
 	}
 
 	if isDirectoryExists("./jre") {
-		return ".jre"
+		return "./jre", nil
 	}
 
 	javaHome := os.Getenv("JAVA_HOME")
 
 	if javaHome != "" {
-		return filepath.Join(javaHome, "jre")
+		jreDirectory := filepath.Join(javaHome, "jre")
+
+		if isDirectoryExists(jreDirectory) {
+			return jreDirectory, nil
+		}
+
+		// Java 9+ has no separate jre folder
+		if isFileExists(filepath.Join(javaHome, "lib", "modules")) {
+			return javaHome, nil
+		}
+	}
+
+	return "", errors.New("Error: Cannot find jre folder, use -jre <jre path> or set JAVA_HOME")
+}
+
+// Read JAVA_VERSION="11.0.2" from the release file of a Java 9+ runtime
+func readRuntimeRelease(javaHome string) int {
+	file, err := os.Open(filepath.Join(javaHome, "release"))
+
+	if err != nil {
+		return 9
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !strings.HasPrefix(line, "JAVA_VERSION=") {
+			continue
+		}
+
+		version := strings.Trim(line[len("JAVA_VERSION="):], "\"")
+		index := strings.IndexAny(version, ".-+")
+
+		if index >= 0 {
+			version = version[:index]
+		}
+
+		release, err := strconv.Atoi(version)
+
+		if err == nil && release >= 9 {
+			return release
+		}
 	}
 
-	panic("Cannot find jre folder!")
+	return 9
+}
+
+// jdk.util.jar.version can lower the release multi-release jars are read for, never raise it
+// above the runtime's
+func getTargetRelease() int {
+	if options.TargetRelease > 0 && options.TargetRelease < runtimeRelease {
+		return options.TargetRelease
+	}
+
+	return runtimeRelease
+}
+
+func isFileExists(path string) bool {
+	fileInfo, err := os.Stat(path)
+
+	return err == nil && !fileInfo.IsDir()
 }
 
 func isDirectoryExists(path string) bool {
@@ -68,19 +224,56 @@ func isDirectoryExists(path string) bool {
 }
 
 func (classFinder *ClassFinder) ReadClass(className string) ([]byte, ClasspathEntry, error) {
-	className = className + ".class"
+	classNotFoundError := &ClassNotFoundError{
+		className:   className,
+		classFinder: classFinder,
+	}
+
+	groups := []string{"bootstrap", "extensions", "user"}
+	classpathEntries := []ClasspathEntry{
+		classFinder.bootstrapClasspathEntry,
+		classFinder.extensionsClasspathEntry,
+		classFinder.userClasspathEntry,
+	}
 
-	data, classpathEntry, err := classFinder.bootstrapClasspathEntry.ReadClass(className)
+	for i, classpathEntry := range classpathEntries {
+		data, targetClasspathEntry, err := classpathEntry.ReadClass(className + ".class")
 
-	if err == nil {
-		return data, classpathEntry, err
+		if err == nil {
+			return data, targetClasspathEntry, nil
+		}
+
+		classNotFoundError.groups = append(classNotFoundError.groups, groups[i])
+		classNotFoundError.entryErrors = append(classNotFoundError.entryErrors, newClasspathEntryErrors(classpathEntry, err))
 	}
 
-	data, classpathEntry, err = classFinder.extensionsClasspathEntry.ReadClass(className)
+	return nil, nil, classNotFoundError
+}
+
+// Classes read from the runtime's own boot and extension class paths are trusted and not verified
+func (classFinder *ClassFinder) IsSystemClasspathEntry(classpathEntry ClasspathEntry) bool {
+	return containsClasspathEntry(classFinder.bootstrapClasspathEntry, classpathEntry) ||
+		containsClasspathEntry(classFinder.extensionsClasspathEntry, classpathEntry)
+}
+
+func containsClasspathEntry(classpathEntry, targetClasspathEntry ClasspathEntry) bool {
+	switch classpathEntry.(type) {
+	case CompositeClasspathEntry:
+		for _, childClasspathEntry := range classpathEntry.(CompositeClasspathEntry) {
+			if containsClasspathEntry(childClasspathEntry, targetClasspathEntry) {
+				return true
+			}
+		}
 
-	if err == nil {
-		return data, classpathEntry, err
+		return false
 	}
 
-	return classFinder.userClasspathEntry.ReadClass(className)
+	return classpathEntry == targetClasspathEntry
+}
+
+func (classFinder *ClassFinder) getClassNames() []string {
+	classNames := getClassNames(classFinder.userClasspathEntry)
+	classNames = append(classNames, getClassNames(classFinder.extensionsClasspathEntry)...)
+
+	return append(classNames, getClassNames(classFinder.bootstrapClasspathEntry)...)
 }
@@ -0,0 +1,141 @@
+package classpath
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const maxSuggestionsCount = 5
+
+// Every classpath entry that was searched, and why it did not have the class
+type ClasspathEntryErrors []*ClasspathEntryError
+
+type ClasspathEntryError struct {
+	classpathEntry ClasspathEntry
+	err            error
+}
+
+type ClassNotFoundError struct {
+	className   string
+	groups      []string
+	entryErrors []ClasspathEntryErrors
+	classFinder *ClassFinder
+}
+
+func newClasspathEntryErrors(classpathEntry ClasspathEntry, err error) ClasspathEntryErrors {
+	classpathEntryErrors, ok := err.(ClasspathEntryErrors)
+
+	if ok {
+		return classpathEntryErrors
+	}
+
+	return ClasspathEntryErrors{&ClasspathEntryError{classpathEntry, err}}
+}
+
+func (classpathEntryErrors ClasspathEntryErrors) Error() string {
+	messages := make([]string, len(classpathEntryErrors))
+
+	for i, classpathEntryError := range classpathEntryErrors {
+		messages[i] = classpathEntryError.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (classpathEntryError *ClasspathEntryError) Error() string {
+	name := classpathEntryError.classpathEntry.ToString()
+
+	if name == "" {
+		name = "(no entries)"
+	}
+
+	return name + ": " + classpathEntryError.err.Error()
+}
+
+func (classNotFoundError *ClassNotFoundError) Error() string {
+	return "Class not found: " + classNotFoundError.className
+}
+
+func (classNotFoundError *ClassNotFoundError) GetClassName() string {
+	return classNotFoundError.className
+}
+
+// Multi-line report of the searched entries and near-miss class names
+func (classNotFoundError *ClassNotFoundError) GetDiagnostics() string {
+	lines := []string{classNotFoundError.GetSearchReport()}
+	suggestions := classNotFoundError.GetSuggestions()
+
+	if len(suggestions) > 0 {
+		lines = append(lines, "Did you mean:")
+
+		for _, suggestion := range suggestions {
+			lines = append(lines, "  "+suggestion)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Multi-line report of the searched entries only, cheap enough for every failed lookup
+func (classNotFoundError *ClassNotFoundError) GetSearchReport() string {
+	lines := []string{"Class " + classNotFoundError.className + " was not found. Searched:"}
+
+	for i, classpathEntryErrors := range classNotFoundError.entryErrors {
+		for _, classpathEntryError := range classpathEntryErrors {
+			lines = append(lines, "  ["+classNotFoundError.groups[i]+"] "+classpathEntryError.Error())
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Classes with the same name in another package, or differing only in case
+func (classNotFoundError *ClassNotFoundError) GetSuggestions() []string {
+	className := classNotFoundError.className
+	simpleName := className[strings.LastIndex(className, "/")+1:]
+	suggestions := []string{}
+
+	for _, candidate := range classNotFoundError.classFinder.getClassNames() {
+		if len(suggestions) >= maxSuggestionsCount {
+			break
+		}
+
+		if candidate == className {
+			continue
+		}
+
+		if strings.EqualFold(candidate, className) {
+			suggestions = append(suggestions, candidate+" (case mismatch)")
+		} else if candidate[strings.LastIndex(candidate, "/")+1:] == simpleName {
+			suggestions = append(suggestions, candidate+" (wrong package)")
+		}
+	}
+
+	return suggestions
+}
+
+// Implemented by classpath entries which can enumerate their classes
+type classNamesLister interface {
+	getClassNames() []string
+}
+
+func getClassNames(classpathEntry ClasspathEntry) []string {
+	lister, ok := classpathEntry.(classNamesLister)
+
+	if ok {
+		return lister.getClassNames()
+	}
+
+	return nil
+}
+
+// java/lang/Object.class -> java/lang/Object
+func getClassNameFromPath(path string) (string, bool) {
+	path = filepath.ToSlash(path)
+
+	if !strings.HasSuffix(path, ".class") || strings.HasPrefix(path, "META-INF/") {
+		return "", false
+	}
+
+	return strings.TrimSuffix(path, ".class"), true
+}
@@ -17,7 +17,8 @@ func NewClasspathEntry(path string) ClasspathEntry {
 		return NewCompositeClasspathEntry(path)
 	}
 
-	if strings.HasSuffix(path, ".zip") || strings.HasSuffix(path, ".ZIP") {
+	if strings.HasSuffix(path, ".zip") || strings.HasSuffix(path, ".ZIP") ||
+		strings.HasSuffix(path, ".jar") || strings.HasSuffix(path, ".JAR") {
 		return NewZipClasspathEntry(path)
 	}
 
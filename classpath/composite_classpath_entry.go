@@ -19,15 +19,33 @@ func NewCompositeClasspathEntry(pathList string) CompositeClasspathEntry {
 }
 
 func (compositeClasspathEntry CompositeClasspathEntry) ReadClass(className string) ([]byte, ClasspathEntry, error) {
+	classpathEntryErrors := ClasspathEntryErrors{}
+
 	for _, classpathEntry := range compositeClasspathEntry {
 		data, targetClasspathEntry, err := classpathEntry.ReadClass(className)
 
 		if err == nil {
 			return data, targetClasspathEntry, nil
 		}
+
+		classpathEntryErrors = append(classpathEntryErrors, newClasspathEntryErrors(classpathEntry, err)...)
+	}
+
+	if len(classpathEntryErrors) == 0 {
+		return nil, nil, errors.New("Class not found: " + className)
+	}
+
+	return nil, nil, classpathEntryErrors
+}
+
+func (compositeClasspathEntry CompositeClasspathEntry) getClassNames() []string {
+	classNames := []string{}
+
+	for _, classpathEntry := range compositeClasspathEntry {
+		classNames = append(classNames, getClassNames(classpathEntry)...)
 	}
 
-	return nil, nil, errors.New("Class not found: " + className)
+	return classNames
 }
 
 func (compositeClasspathEntry CompositeClasspathEntry) ToString() string {
@@ -1,10 +1,15 @@
 package classpath
 
 import (
+	"errors"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 )
 
+// Stop listing huge directories such as a class path of "."
+const maxListedFilesCount = 10000
+
 type DirectoryClasspathEntry struct {
 	absoluteDirectory string
 }
@@ -23,9 +28,48 @@ func (directoryClasspathEntry DirectoryClasspathEntry) ReadClass(className strin
 	fileName := filepath.Join(directoryClasspathEntry.absoluteDirectory, className)
 	data, err := ioutil.ReadFile(fileName)
 
+	if os.IsNotExist(err) {
+		return nil, nil, errors.New("Class not found: " + className)
+	}
+
 	return data, directoryClasspathEntry, err
 }
 
 func (directoryClasspathEntry DirectoryClasspathEntry) ToString() string {
 	return directoryClasspathEntry.absoluteDirectory
 }
+
+func (directoryClasspathEntry DirectoryClasspathEntry) getClassNames() []string {
+	classNames := []string{}
+	absoluteDirectory := directoryClasspathEntry.absoluteDirectory
+	errTooManyFiles := errors.New("Too many files")
+	filesCount := 0
+
+	filepath.Walk(absoluteDirectory, func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		filesCount++
+
+		if filesCount > maxListedFilesCount {
+			return errTooManyFiles
+		}
+
+		relativePath, err := filepath.Rel(absoluteDirectory, path)
+
+		if err != nil || fileInfo.IsDir() {
+			return nil
+		}
+
+		className, ok := getClassNameFromPath(relativePath)
+
+		if ok {
+			classNames = append(classNames, className)
+		}
+
+		return nil
+	})
+
+	return classNames
+}
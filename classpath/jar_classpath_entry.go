@@ -0,0 +1,31 @@
+package classpath
+
+import (
+	"net/url"
+	"path/filepath"
+)
+
+// The jar itself followed by the entries of its Class-Path manifest attribute
+func NewJarClasspathEntry(jarPath string, manifest *Manifest) CompositeClasspathEntry {
+	compositeClasspathEntry := []ClasspathEntry{NewZipClasspathEntry(jarPath)}
+	jarDirectory := filepath.Dir(jarPath)
+
+	for _, classPath := range manifest.GetClassPath() {
+		// Class-Path entries are relative URLs
+		classPathURL, err := url.Parse(classPath)
+
+		if err != nil || classPathURL.Path == "" {
+			continue
+		}
+
+		path := filepath.FromSlash(classPathURL.Path)
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(jarDirectory, path)
+		}
+
+		compositeClasspathEntry = append(compositeClasspathEntry, NewClasspathEntry(path))
+	}
+
+	return compositeClasspathEntry
+}
@@ -0,0 +1,429 @@
+package classpath
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+/*
+jimage (lib/modules) {
+    u4 magic;
+    u4 version;
+    u4 flags;
+    u4 resource_count;
+    u4 table_length;
+    u4 locations_size;
+    u4 strings_size;
+    s4 redirect[table_length];
+    u4 offsets[table_length];
+    u1 locations[locations_size];
+    u1 strings[strings_size];
+    u1 resources[];
+}
+*/
+const (
+	jimageMagic          = 0xCAFEDADA
+	jimageHeaderSize     = 28
+	jimageHashMultiplier = 0x01000193
+)
+
+// Location attribute kinds
+const (
+	jimageAttributeEnd          = 0
+	jimageAttributeModule       = 1
+	jimageAttributeParent       = 2
+	jimageAttributeBase         = 3
+	jimageAttributeExtension    = 4
+	jimageAttributeOffset       = 5
+	jimageAttributeCompressed   = 6
+	jimageAttributeUncompressed = 7
+	jimageAttributeCount        = 8
+)
+
+/*
+compressed_resource_header {
+    u4 magic;
+    u8 compressed_size;
+    u8 uncompressed_size;
+    u4 decompressor_name_offset;
+    u4 content_offset;
+    u1 is_terminal;
+}
+*/
+const (
+	compressedResourceMagic      = 0xCAFEFAFA
+	compressedResourceHeaderSize = 29
+)
+
+type jimageLocation [jimageAttributeCount]uint64
+
+type JImageClasspathEntry struct {
+	absolutePath   string
+	lock           sync.Mutex
+	isOpened       bool
+	openError      error
+	file           *os.File
+	byteOrder      binary.ByteOrder
+	redirectTable  []int32
+	offsetsTable   []uint32
+	locations      []byte
+	strings        []byte
+	indexSize      int64
+	packageModules map[string][]string
+}
+
+func NewJImageClasspathEntry(path string) *JImageClasspathEntry {
+	absolutePath, err := filepath.Abs(path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &JImageClasspathEntry{
+		absolutePath:   absolutePath,
+		packageModules: map[string][]string{},
+	}
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) ReadClass(className string) ([]byte, ClasspathEntry, error) {
+	err := jimageClasspathEntry.open()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, module := range jimageClasspathEntry.getPackageModules(getPackageName(className)) {
+		location, ok := jimageClasspathEntry.findLocation("/" + module + "/" + className)
+
+		if !ok {
+			continue
+		}
+
+		data, err := jimageClasspathEntry.readResource(location)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return data, jimageClasspathEntry, nil
+	}
+
+	return nil, nil, errors.New("Class not found: " + className)
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) getClassNames() []string {
+	classNames := []string{}
+
+	if jimageClasspathEntry.open() != nil {
+		return classNames
+	}
+
+	for _, offset := range jimageClasspathEntry.offsetsTable {
+		location := jimageClasspathEntry.decompressLocation(offset)
+		module := jimageClasspathEntry.getString(uint32(location[jimageAttributeModule]))
+
+		if module == "" || module == "packages" || module == "modules" {
+			continue
+		}
+
+		// Strip the /<module>/ prefix
+		name := jimageClasspathEntry.getFullName(location)[len(module)+2:]
+		className, ok := getClassNameFromPath(name)
+
+		if ok {
+			classNames = append(classNames, className)
+		}
+	}
+
+	return classNames
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) ToString() string {
+	return jimageClasspathEntry.absolutePath
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) open() error {
+	jimageClasspathEntry.lock.Lock()
+	defer jimageClasspathEntry.lock.Unlock()
+
+	if jimageClasspathEntry.isOpened {
+		return jimageClasspathEntry.openError
+	}
+
+	jimageClasspathEntry.isOpened = true
+	jimageClasspathEntry.openError = jimageClasspathEntry.readIndex()
+
+	return jimageClasspathEntry.openError
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) readIndex() error {
+	file, err := os.Open(jimageClasspathEntry.absolutePath)
+
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, jimageHeaderSize)
+
+	if _, err = file.ReadAt(header, 0); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	// The image is written in the byte order of the platform it was built for
+	var byteOrder binary.ByteOrder
+
+	if binary.LittleEndian.Uint32(header) == jimageMagic {
+		byteOrder = binary.LittleEndian
+	} else if binary.BigEndian.Uint32(header) == jimageMagic {
+		byteOrder = binary.BigEndian
+	} else {
+		file.Close()
+
+		return errors.New("Invalid jimage magic number: " + jimageClasspathEntry.absolutePath)
+	}
+
+	tableLength := int64(byteOrder.Uint32(header[16:]))
+	locationsSize := int64(byteOrder.Uint32(header[20:]))
+	stringsSize := int64(byteOrder.Uint32(header[24:]))
+	indexSize := jimageHeaderSize + tableLength*8 + locationsSize + stringsSize
+	index := make([]byte, indexSize-jimageHeaderSize)
+
+	if _, err = file.ReadAt(index, jimageHeaderSize); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	jimageClasspathEntry.redirectTable = make([]int32, tableLength)
+	jimageClasspathEntry.offsetsTable = make([]uint32, tableLength)
+
+	for i := int64(0); i < tableLength; i++ {
+		jimageClasspathEntry.redirectTable[i] = int32(byteOrder.Uint32(index[i*4:]))
+		jimageClasspathEntry.offsetsTable[i] = byteOrder.Uint32(index[(tableLength+i)*4:])
+	}
+
+	locationsStart := tableLength * 8
+	jimageClasspathEntry.locations = index[locationsStart : locationsStart+locationsSize]
+	jimageClasspathEntry.strings = index[locationsStart+locationsSize:]
+	jimageClasspathEntry.file = file
+	jimageClasspathEntry.byteOrder = byteOrder
+	jimageClasspathEntry.indexSize = indexSize
+
+	return nil
+}
+
+// Modules containing the package, read from the /packages/<package> resource
+func (jimageClasspathEntry *JImageClasspathEntry) getPackageModules(packageName string) []string {
+	jimageClasspathEntry.lock.Lock()
+	defer jimageClasspathEntry.lock.Unlock()
+
+	modules, ok := jimageClasspathEntry.packageModules[packageName]
+
+	if ok {
+		return modules
+	}
+
+	location, ok := jimageClasspathEntry.findLocation("/packages/" + strings.Replace(packageName, "/", ".", -1))
+
+	if ok {
+		data, err := jimageClasspathEntry.readResource(location)
+
+		if err == nil {
+			byteOrder := jimageClasspathEntry.byteOrder
+			emptyModules := []string{}
+
+			// Pairs of (is_empty, module_name_offset)
+			for i := 0; i+8 <= len(data); i += 8 {
+				module := jimageClasspathEntry.getString(byteOrder.Uint32(data[i+4:]))
+
+				if byteOrder.Uint32(data[i:]) == 0 {
+					modules = append(modules, module)
+				} else {
+					emptyModules = append(emptyModules, module)
+				}
+			}
+
+			modules = append(modules, emptyModules...)
+		}
+	}
+
+	jimageClasspathEntry.packageModules[packageName] = modules
+
+	return modules
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) findLocation(name string) (*jimageLocation, bool) {
+	tableLength := int32(len(jimageClasspathEntry.redirectTable))
+
+	if tableLength == 0 {
+		return nil, false
+	}
+
+	index := jimageClasspathEntry.redirectTable[getJImageHashCode(name, jimageHashMultiplier)%tableLength]
+
+	if index < 0 {
+		index = -index - 1
+	} else if index > 0 {
+		index = getJImageHashCode(name, index) % tableLength
+	} else {
+		return nil, false
+	}
+
+	location := jimageClasspathEntry.decompressLocation(jimageClasspathEntry.offsetsTable[index])
+
+	// Different names can share a hash slot
+	if jimageClasspathEntry.getFullName(location) != name {
+		return nil, false
+	}
+
+	return location, true
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) decompressLocation(offset uint32) *jimageLocation {
+	location := &jimageLocation{}
+	locations := jimageClasspathEntry.locations
+
+	for i := int(offset); i < len(locations); {
+		data := locations[i]
+		i++
+
+		kind := data >> 3
+
+		if kind == jimageAttributeEnd || kind >= jimageAttributeCount {
+			break
+		}
+
+		length := int(data&0x7) + 1
+		value := uint64(0)
+
+		for j := 0; j < length && i < len(locations); j++ {
+			value = value<<8 | uint64(locations[i])
+			i++
+		}
+
+		location[kind] = value
+	}
+
+	return location
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) getFullName(location *jimageLocation) string {
+	fullName := ""
+
+	if location[jimageAttributeModule] != 0 {
+		fullName += "/" + jimageClasspathEntry.getString(uint32(location[jimageAttributeModule])) + "/"
+	}
+
+	if location[jimageAttributeParent] != 0 {
+		fullName += jimageClasspathEntry.getString(uint32(location[jimageAttributeParent])) + "/"
+	}
+
+	fullName += jimageClasspathEntry.getString(uint32(location[jimageAttributeBase]))
+
+	if location[jimageAttributeExtension] != 0 {
+		fullName += "." + jimageClasspathEntry.getString(uint32(location[jimageAttributeExtension]))
+	}
+
+	return fullName
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) getString(offset uint32) string {
+	imageStrings := jimageClasspathEntry.strings
+
+	if int(offset) >= len(imageStrings) {
+		return ""
+	}
+
+	end := bytes.IndexByte(imageStrings[offset:], 0)
+
+	if end < 0 {
+		return string(imageStrings[offset:])
+	}
+
+	return string(imageStrings[offset : int(offset)+end])
+}
+
+func (jimageClasspathEntry *JImageClasspathEntry) readResource(location *jimageLocation) ([]byte, error) {
+	offset := jimageClasspathEntry.indexSize + int64(location[jimageAttributeOffset])
+	compressedSize := location[jimageAttributeCompressed]
+	uncompressedSize := location[jimageAttributeUncompressed]
+
+	if compressedSize == 0 {
+		data := make([]byte, uncompressedSize)
+		_, err := jimageClasspathEntry.file.ReadAt(data, offset)
+
+		return data, err
+	}
+
+	data := make([]byte, compressedSize)
+
+	if _, err := jimageClasspathEntry.file.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+
+	return jimageClasspathEntry.decompressResource(data)
+}
+
+// A resource may be compressed several times, each layer has its own header
+func (jimageClasspathEntry *JImageClasspathEntry) decompressResource(data []byte) ([]byte, error) {
+	byteOrder := jimageClasspathEntry.byteOrder
+
+	for len(data) >= compressedResourceHeaderSize && byteOrder.Uint32(data) == compressedResourceMagic {
+		compressedSize := byteOrder.Uint64(data[4:])
+		decompressorName := jimageClasspathEntry.getString(byteOrder.Uint32(data[20:]))
+		content := data[compressedResourceHeaderSize:]
+
+		if compressedSize < uint64(len(content)) {
+			content = content[:compressedSize]
+		}
+
+		switch decompressorName {
+		case "zip":
+			zlibReader, err := zlib.NewReader(bytes.NewReader(content))
+
+			if err != nil {
+				return nil, err
+			}
+
+			data, err = ioutil.ReadAll(zlibReader)
+			zlibReader.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.New("Unsupported jimage decompressor: " + decompressorName)
+		}
+	}
+
+	return data, nil
+}
+
+func getJImageHashCode(name string, seed int32) int32 {
+	hashCode := uint32(seed)
+
+	for i := 0; i < len(name); i++ {
+		hashCode = (hashCode * jimageHashMultiplier) ^ uint32(name[i])
+	}
+
+	return int32(hashCode & 0x7FFFFFFF)
+}
+
+func getPackageName(className string) string {
+	index := strings.LastIndex(className, "/")
+
+	if index < 0 {
+		return ""
+	}
+
+	return className[:index]
+}
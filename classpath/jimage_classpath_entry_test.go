@@ -0,0 +1,309 @@
+package classpath
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+type testJImageResource struct {
+	module       string
+	parent       string
+	base         string
+	extension    string
+	data         []byte
+	isCompressed bool
+}
+
+// Writes a jimage holding the resources, every name gets a slot of its own in the redirect table.
+// The strings start with "zip" and the module names, so their offsets are known in advance
+func writeTestJImage(t *testing.T, byteOrder binary.ByteOrder, moduleNames []string, resources []testJImageResource) string {
+	imageStrings := []byte{0}
+	stringOffsets := map[string]uint64{"": 0}
+
+	addString := func(value string) uint64 {
+		offset, ok := stringOffsets[value]
+
+		if !ok {
+			offset = uint64(len(imageStrings))
+			imageStrings = append(append(imageStrings, value...), 0)
+			stringOffsets[value] = offset
+		}
+
+		return offset
+	}
+
+	decompressorNameOffset := addString("zip")
+
+	for _, moduleName := range moduleNames {
+		addString(moduleName)
+	}
+
+	locations := []byte{}
+	locationOffsets := []uint32{}
+	names := []string{}
+	content := []byte{}
+
+	for _, resource := range resources {
+		data := resource.data
+		uncompressedSize := uint64(len(data))
+		compressedSize := uint64(0)
+
+		if resource.isCompressed {
+			data = compressTestJImageResource(t, byteOrder, data, decompressorNameOffset)
+			compressedSize = uint64(len(data))
+		}
+
+		locationOffsets = append(locationOffsets, uint32(len(locations)))
+		locations = appendTestJImageAttribute(locations, jimageAttributeModule, addString(resource.module))
+		locations = appendTestJImageAttribute(locations, jimageAttributeParent, addString(resource.parent))
+		locations = appendTestJImageAttribute(locations, jimageAttributeBase, addString(resource.base))
+		locations = appendTestJImageAttribute(locations, jimageAttributeExtension, addString(resource.extension))
+		locations = appendTestJImageAttribute(locations, jimageAttributeOffset, uint64(len(content)))
+		locations = appendTestJImageAttribute(locations, jimageAttributeCompressed, compressedSize)
+		locations = appendTestJImageAttribute(locations, jimageAttributeUncompressed, uncompressedSize)
+		locations = append(locations, jimageAttributeEnd)
+
+		name := "/" + resource.module + "/"
+
+		if resource.parent != "" {
+			name += resource.parent + "/"
+		}
+
+		name += resource.base
+
+		if resource.extension != "" {
+			name += "." + resource.extension
+		}
+
+		names = append(names, name)
+		content = append(content, data...)
+	}
+
+	buckets := getTestJImageBuckets(names)
+	tableLength := len(buckets)
+	redirectTable := make([]int32, tableLength)
+	offsetsTable := make([]uint32, tableLength)
+
+	for i, bucket := range buckets {
+		if bucket >= 0 {
+			redirectTable[i] = int32(-bucket - 1)
+			offsetsTable[bucket] = locationOffsets[bucket]
+		}
+	}
+
+	image := []byte{}
+
+	for _, value := range []uint32{jimageMagic, 0x00010000, 0, uint32(len(resources)), uint32(tableLength), uint32(len(locations)), uint32(len(imageStrings))} {
+		image = appendTestJImageUint32(image, byteOrder, value)
+	}
+
+	for _, value := range redirectTable {
+		image = appendTestJImageUint32(image, byteOrder, uint32(value))
+	}
+
+	for _, value := range offsetsTable {
+		image = appendTestJImageUint32(image, byteOrder, value)
+	}
+
+	image = append(append(append(image, locations...), imageStrings...), content...)
+
+	directory := t.TempDir()
+
+	path := filepath.Join(directory, "modules")
+
+	if err := ioutil.WriteFile(path, image, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// The smallest table, at least as long as there are names, in which no two names hash to the same bucket
+func getTestJImageBuckets(names []string) []int {
+	for tableLength := len(names); ; tableLength++ {
+		buckets := make([]int, tableLength)
+
+		for i := range buckets {
+			buckets[i] = -1
+		}
+
+		isCollisionFree := true
+
+		for i, name := range names {
+			bucket := getJImageHashCode(name, jimageHashMultiplier) % int32(tableLength)
+
+			if buckets[bucket] >= 0 {
+				isCollisionFree = false
+
+				break
+			}
+
+			buckets[bucket] = i
+		}
+
+		if isCollisionFree {
+			return buckets
+		}
+	}
+}
+
+// Values are big endian whatever the byte order of the image, in as few bytes as they fit
+func appendTestJImageAttribute(locations []byte, kind byte, value uint64) []byte {
+	if value == 0 {
+		return locations
+	}
+
+	valueBytes := []byte{}
+
+	for ; value > 0; value >>= 8 {
+		valueBytes = append([]byte{byte(value)}, valueBytes...)
+	}
+
+	return append(append(locations, kind<<3|byte(len(valueBytes)-1)), valueBytes...)
+}
+
+func appendTestJImageUint32(data []byte, byteOrder binary.ByteOrder, value uint32) []byte {
+	valueBytes := make([]byte, 4)
+	byteOrder.PutUint32(valueBytes, value)
+
+	return append(data, valueBytes...)
+}
+
+func compressTestJImageResource(t *testing.T, byteOrder binary.ByteOrder, data []byte, decompressorNameOffset uint64) []byte {
+	var buffer bytes.Buffer
+	zlibWriter := zlib.NewWriter(&buffer)
+
+	if _, err := zlibWriter.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zlibWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header := make([]byte, compressedResourceHeaderSize)
+	byteOrder.PutUint32(header, compressedResourceMagic)
+	byteOrder.PutUint64(header[4:], uint64(buffer.Len()))
+	byteOrder.PutUint64(header[12:], uint64(len(data)))
+	byteOrder.PutUint32(header[20:], uint32(decompressorNameOffset))
+	header[28] = 1
+
+	return append(header, buffer.Bytes()...)
+}
+
+// java.lang is in java.base, java.util is empty in java.desktop and holds a compressed class in java.base
+func newTestJImageClasspathEntry(t *testing.T, byteOrder binary.ByteOrder) *JImageClasspathEntry {
+	javaBaseOffset := uint32(len("\x00zip\x00"))
+	javaDesktopOffset := javaBaseOffset + uint32(len("java.base\x00"))
+
+	javaLangPackage := appendTestJImageUint32(appendTestJImageUint32(nil, byteOrder, 0), byteOrder, javaBaseOffset)
+	javaUtilPackage := appendTestJImageUint32(appendTestJImageUint32(nil, byteOrder, 1), byteOrder, javaDesktopOffset)
+	javaUtilPackage = appendTestJImageUint32(appendTestJImageUint32(javaUtilPackage, byteOrder, 0), byteOrder, javaBaseOffset)
+
+	resources := []testJImageResource{
+		{"packages", "", "java.lang", "", javaLangPackage, false},
+		{"packages", "", "java.util", "", javaUtilPackage, false},
+		{"java.base", "java/lang", "Object", "class", []byte("Object class data"), false},
+		{"java.base", "java/util", "List", "class", bytes.Repeat([]byte("List class data "), 16), true},
+		{"java.base", "", "module-info", "class", []byte("module-info class data"), false},
+	}
+
+	return NewJImageClasspathEntry(writeTestJImage(t, byteOrder, []string{"java.base", "java.desktop"}, resources))
+}
+
+func TestJImageClasspathEntryReadClass(t *testing.T) {
+	for _, byteOrder := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		jimageClasspathEntry := newTestJImageClasspathEntry(t, byteOrder)
+
+		data, classpathEntry, err := jimageClasspathEntry.ReadClass("java/lang/Object.class")
+
+		if err != nil {
+			t.Fatalf("%v: %v", byteOrder, err)
+		}
+
+		if string(data) != "Object class data" || classpathEntry != jimageClasspathEntry {
+			t.Errorf("%v: read %q from %v", byteOrder, data, classpathEntry)
+		}
+
+		data, _, err = jimageClasspathEntry.ReadClass("java/util/List.class")
+
+		if err != nil {
+			t.Fatalf("%v: %v", byteOrder, err)
+		}
+
+		if !bytes.Equal(data, bytes.Repeat([]byte("List class data "), 16)) {
+			t.Errorf("%v: decompressed %q", byteOrder, data)
+		}
+
+		for _, className := range []string{"java/lang/String.class", "java/util/Map.class", "javax/swing/JFrame.class", "Main.class"} {
+			if _, _, err := jimageClasspathEntry.ReadClass(className); err == nil {
+				t.Errorf("%v: read missing class %s", byteOrder, className)
+			}
+		}
+	}
+}
+
+func TestJImageClasspathEntryGetPackageModules(t *testing.T) {
+	jimageClasspathEntry := newTestJImageClasspathEntry(t, binary.BigEndian)
+
+	if err := jimageClasspathEntry.open(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Modules where the package is empty go last
+	tests := map[string][]string{
+		"java/lang":   {"java.base"},
+		"java/util":   {"java.base", "java.desktop"},
+		"javax/swing": nil,
+	}
+
+	for packageName, expectedModules := range tests {
+		modules := jimageClasspathEntry.getPackageModules(packageName)
+
+		if len(modules) != len(expectedModules) {
+			t.Errorf("modules of %s: %v, expected %v", packageName, modules, expectedModules)
+
+			continue
+		}
+
+		for i := range modules {
+			if modules[i] != expectedModules[i] {
+				t.Errorf("modules of %s: %v, expected %v", packageName, modules, expectedModules)
+			}
+		}
+	}
+}
+
+func TestJImageClasspathEntryGetClassNames(t *testing.T) {
+	classNames := newTestJImageClasspathEntry(t, binary.LittleEndian).getClassNames()
+	sort.Strings(classNames)
+
+	expectedClassNames := []string{"java/lang/Object", "java/util/List", "module-info"}
+
+	if len(classNames) != len(expectedClassNames) {
+		t.Fatalf("class names %v, expected %v", classNames, expectedClassNames)
+	}
+
+	for i := range classNames {
+		if classNames[i] != expectedClassNames[i] {
+			t.Errorf("class names %v, expected %v", classNames, expectedClassNames)
+		}
+	}
+}
+
+func TestJImageClasspathEntryRejectsInvalidMagic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "modules")
+
+	if err := ioutil.WriteFile(path, make([]byte, jimageHeaderSize), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := NewJImageClasspathEntry(path).ReadClass("java/lang/Object.class"); err == nil {
+		t.Error("read a class from an image without the jimage magic number")
+	}
+}
@@ -0,0 +1,92 @@
+package classpath
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+const manifestName = "META-INF/MANIFEST.MF"
+
+// Main section attributes of META-INF/MANIFEST.MF
+type Manifest struct {
+	attributes map[string]string
+}
+
+func ReadJarManifest(jarPath string) (*Manifest, error) {
+	zipClasspathEntry := NewZipClasspathEntry(jarPath)
+
+	if zipClasspathEntry.open() != nil {
+		return nil, errors.New("Unable to access jarfile " + jarPath)
+	}
+
+	zipFile, err := zipClasspathEntry.findFile(manifestName)
+
+	if err != nil {
+		return &Manifest{map[string]string{}}, nil
+	}
+
+	file, err := zipFile.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return parseManifest(file)
+}
+
+func parseManifest(reader io.Reader) (*Manifest, error) {
+	manifest := &Manifest{map[string]string{}}
+	scanner := bufio.NewScanner(reader)
+	lastName := ""
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// An empty line ends the main section
+		if line == "" {
+			break
+		}
+
+		// Long values are continued on lines starting with a single space
+		if line[0] == ' ' {
+			if lastName != "" {
+				manifest.attributes[lastName] += line[1:]
+			}
+
+			continue
+		}
+
+		index := strings.Index(line, ":")
+
+		if index <= 0 {
+			return nil, errors.New("Invalid manifest header: " + line)
+		}
+
+		lastName = strings.TrimSpace(line[:index])
+		manifest.attributes[lastName] = strings.TrimSpace(line[index+1:])
+	}
+
+	return manifest, scanner.Err()
+}
+
+func (manifest *Manifest) GetAttribute(name string) string {
+	for attributeName, value := range manifest.attributes {
+		if strings.EqualFold(attributeName, name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func (manifest *Manifest) GetMainClass() string {
+	return manifest.GetAttribute("Main-Class")
+}
+
+func (manifest *Manifest) GetClassPath() []string {
+	return strings.Fields(manifest.GetAttribute("Class-Path"))
+}
@@ -0,0 +1,197 @@
+package classpath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/classfile"
+)
+
+const moduleInfoClassName = "module-info.class"
+
+// An exploded module directory or a modular jar on the module path
+type ModuleClasspathEntry struct {
+	classpathEntry ClasspathEntry
+	moduleName     string
+	mainClassName  string
+}
+
+func NewModuleClasspathEntry(path string) (*ModuleClasspathEntry, error) {
+	fileInfo, err := os.Stat(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if fileInfo.IsDir() {
+		return newExplodedModuleClasspathEntry(path)
+	}
+
+	return newModularJarClasspathEntry(path)
+}
+
+func newExplodedModuleClasspathEntry(path string) (*ModuleClasspathEntry, error) {
+	directoryClasspathEntry := NewDirectoryClasspathEntry(path)
+	moduleInfoData, _, err := directoryClasspathEntry.ReadClass(moduleInfoClassName)
+
+	if err != nil {
+		return nil, errors.New("No module-info.class in module directory " + path)
+	}
+
+	moduleClasspathEntry := &ModuleClasspathEntry{classpathEntry: directoryClasspathEntry}
+
+	return moduleClasspathEntry, moduleClasspathEntry.readModuleInfo(moduleInfoData)
+}
+
+func newModularJarClasspathEntry(path string) (*ModuleClasspathEntry, error) {
+	manifest, err := ReadJarManifest(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	moduleClasspathEntry := &ModuleClasspathEntry{
+		classpathEntry: NewZipClasspathEntry(path),
+		mainClassName:  strings.Replace(manifest.GetMainClass(), ".", "/", -1),
+	}
+
+	moduleInfoData, _, err := moduleClasspathEntry.classpathEntry.ReadClass(moduleInfoClassName)
+
+	if err == nil {
+		return moduleClasspathEntry, moduleClasspathEntry.readModuleInfo(moduleInfoData)
+	}
+
+	// Automatic module
+	moduleClasspathEntry.moduleName = manifest.GetAttribute("Automatic-Module-Name")
+
+	if moduleClasspathEntry.moduleName == "" {
+		moduleClasspathEntry.moduleName = getAutomaticModuleName(path)
+	}
+
+	return moduleClasspathEntry, nil
+}
+
+func (moduleClasspathEntry *ModuleClasspathEntry) readModuleInfo(moduleInfoData []byte) error {
+	classFile, err := classfile.Parse(moduleInfoData)
+
+	if err != nil {
+		return err
+	}
+
+	moduleAttribute := classFile.GetModuleAttribute()
+
+	if moduleAttribute == nil {
+		return errors.New("No Module attribute in " + moduleInfoClassName)
+	}
+
+	moduleClasspathEntry.moduleName = moduleAttribute.GetModuleName()
+	moduleMainClassAttribute := classFile.GetModuleMainClassAttribute()
+
+	if moduleMainClassAttribute != nil {
+		moduleClasspathEntry.mainClassName = moduleMainClassAttribute.GetMainClassName()
+	}
+
+	return nil
+}
+
+// foo-bar-1.2.3.jar -> foo.bar
+func getAutomaticModuleName(jarPath string) string {
+	name := strings.TrimSuffix(filepath.Base(jarPath), filepath.Ext(jarPath))
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+
+	for i, part := range parts {
+		if part[0] >= '0' && part[0] <= '9' {
+			parts = parts[:i]
+
+			break
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func (moduleClasspathEntry *ModuleClasspathEntry) ReadClass(className string) ([]byte, ClasspathEntry, error) {
+	data, _, err := moduleClasspathEntry.classpathEntry.ReadClass(className)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, moduleClasspathEntry, nil
+}
+
+func (moduleClasspathEntry *ModuleClasspathEntry) getClassNames() []string {
+	return getClassNames(moduleClasspathEntry.classpathEntry)
+}
+
+func (moduleClasspathEntry *ModuleClasspathEntry) ToString() string {
+	return moduleClasspathEntry.classpathEntry.ToString()
+}
+
+func (moduleClasspathEntry *ModuleClasspathEntry) GetModuleName() string {
+	return moduleClasspathEntry.moduleName
+}
+
+func (moduleClasspathEntry *ModuleClasspathEntry) GetMainClassName() string {
+	return moduleClasspathEntry.mainClassName
+}
+
+// Each module path element is a module, or a directory whose children are modules
+func FindModules(modulePath string) ([]*ModuleClasspathEntry, error) {
+	moduleClasspathEntries := []*ModuleClasspathEntry{}
+
+	for _, path := range strings.Split(modulePath, pathListSeparator) {
+		if path == "" {
+			continue
+		}
+
+		if isFileExists(path) || isFileExists(filepath.Join(path, moduleInfoClassName)) {
+			moduleClasspathEntry, err := NewModuleClasspathEntry(path)
+
+			if err != nil {
+				return nil, err
+			}
+
+			moduleClasspathEntries = append(moduleClasspathEntries, moduleClasspathEntry)
+
+			continue
+		}
+
+		fileInfos, err := ioutil.ReadDir(path)
+
+		if err != nil {
+			continue
+		}
+
+		for _, fileInfo := range fileInfos {
+			childPath := filepath.Join(path, fileInfo.Name())
+
+			if !isModule(childPath, fileInfo) {
+				continue
+			}
+
+			moduleClasspathEntry, err := NewModuleClasspathEntry(childPath)
+
+			if err != nil {
+				return nil, err
+			}
+
+			moduleClasspathEntries = append(moduleClasspathEntries, moduleClasspathEntry)
+		}
+	}
+
+	return moduleClasspathEntries, nil
+}
+
+func isModule(path string, fileInfo os.FileInfo) bool {
+	if fileInfo.IsDir() {
+		return isFileExists(filepath.Join(path, moduleInfoClassName))
+	}
+
+	return strings.HasSuffix(path, ".jar") || strings.HasSuffix(path, ".JAR")
+}
@@ -5,56 +5,203 @@ import (
 	"errors"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 )
 
+const multiReleaseVersionsPrefix = "META-INF/versions/"
+
+// Opened archives shared by every entry pointing at the same file
+var zipClasspathEntries = map[string]*ZipClasspathEntry{}
+var zipClasspathEntriesLock sync.Mutex
+
 type ZipClasspathEntry struct {
 	absolutePath string
+	lock         sync.Mutex
+	isOpened     bool
+	openError    error
+	zipReader    *zip.ReadCloser
+	zipFiles     map[string]*zip.File
+	// Versions under META-INF/versions/ of a multi-release jar, highest first
+	releaseVersions []int
 }
 
-func NewZipClasspathEntry(path string) ZipClasspathEntry {
+func NewZipClasspathEntry(path string) *ZipClasspathEntry {
 	absolutePath, err := filepath.Abs(path)
 
 	if err != nil {
 		panic(err)
 	}
 
-	return ZipClasspathEntry{absolutePath}
+	zipClasspathEntriesLock.Lock()
+	defer zipClasspathEntriesLock.Unlock()
+
+	zipClasspathEntry, ok := zipClasspathEntries[absolutePath]
+
+	if !ok {
+		zipClasspathEntry = &ZipClasspathEntry{absolutePath: absolutePath}
+		zipClasspathEntries[absolutePath] = zipClasspathEntry
+	}
+
+	return zipClasspathEntry
 }
 
-func (zipClasspathEntry ZipClasspathEntry) ReadClass(className string) ([]byte, ClasspathEntry, error) {
-	zipReader, err := zip.OpenReader(zipClasspathEntry.absolutePath)
+func (zipClasspathEntry *ZipClasspathEntry) ReadClass(className string) ([]byte, ClasspathEntry, error) {
+	zipFile, err := zipClasspathEntry.findFile(className)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	defer zipReader.Close()
+	file, err := zipFile.Open()
 
-	for _, zipFile := range zipReader.File {
-		if zipFile.Name != className {
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, zipClasspathEntry, nil
+}
+
+func (zipClasspathEntry *ZipClasspathEntry) findFile(name string) (*zip.File, error) {
+	err := zipClasspathEntry.open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	targetRelease := getTargetRelease()
+
+	for _, releaseVersion := range zipClasspathEntry.releaseVersions {
+		if releaseVersion > targetRelease {
 			continue
 		}
 
-		file, err := zipFile.Open()
+		zipFile, ok := zipClasspathEntry.zipFiles[multiReleaseVersionsPrefix+strconv.Itoa(releaseVersion)+"/"+name]
 
-		if err != nil {
-			return nil, nil, err
+		if ok {
+			return zipFile, nil
 		}
+	}
 
-		defer file.Close()
+	zipFile, ok := zipClasspathEntry.zipFiles[name]
 
-		data, err := ioutil.ReadAll(file)
+	if !ok {
+		return nil, errors.New("Class not found: " + name)
+	}
+
+	return zipFile, nil
+}
+
+// Open the archive and index its entries by name, only once
+func (zipClasspathEntry *ZipClasspathEntry) open() error {
+	zipClasspathEntry.lock.Lock()
+	defer zipClasspathEntry.lock.Unlock()
+
+	if zipClasspathEntry.isOpened {
+		return zipClasspathEntry.openError
+	}
+
+	zipClasspathEntry.isOpened = true
+	zipReader, err := zip.OpenReader(zipClasspathEntry.absolutePath)
+
+	if err != nil {
+		zipClasspathEntry.openError = err
+
+		return err
+	}
 
-		if err != nil {
-			return nil, nil, err
+	zipClasspathEntry.zipReader = zipReader
+	zipClasspathEntry.zipFiles = make(map[string]*zip.File, len(zipReader.File))
+
+	for _, zipFile := range zipReader.File {
+		zipClasspathEntry.zipFiles[zipFile.Name] = zipFile
+	}
+
+	if zipClasspathEntry.isMultiRelease() {
+		zipClasspathEntry.releaseVersions = zipClasspathEntry.findReleaseVersions()
+	}
+
+	return nil
+}
+
+func (zipClasspathEntry *ZipClasspathEntry) isMultiRelease() bool {
+	zipFile, ok := zipClasspathEntry.zipFiles[manifestName]
+
+	if !ok {
+		return false
+	}
+
+	file, err := zipFile.Open()
+
+	if err != nil {
+		return false
+	}
+
+	defer file.Close()
+
+	manifest, err := parseManifest(file)
+
+	return err == nil && strings.EqualFold(manifest.GetAttribute("Multi-Release"), "true")
+}
+
+func (zipClasspathEntry *ZipClasspathEntry) findReleaseVersions() []int {
+	isFound := map[int]bool{}
+	releaseVersions := []int{}
+
+	for name := range zipClasspathEntry.zipFiles {
+		if !strings.HasPrefix(name, multiReleaseVersionsPrefix) {
+			continue
+		}
+
+		versionName := name[len(multiReleaseVersionsPrefix):]
+		index := strings.Index(versionName, "/")
+
+		if index < 0 {
+			continue
+		}
+
+		releaseVersion, err := strconv.Atoi(versionName[:index])
+
+		// Versioned entries only apply from Java 9 on
+		if err == nil && releaseVersion >= 9 && !isFound[releaseVersion] {
+			isFound[releaseVersion] = true
+			releaseVersions = append(releaseVersions, releaseVersion)
 		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(releaseVersions)))
+
+	return releaseVersions
+}
+
+func (zipClasspathEntry *ZipClasspathEntry) getClassNames() []string {
+	classNames := []string{}
 
-		return data, zipClasspathEntry, nil
+	if zipClasspathEntry.open() != nil {
+		return classNames
+	}
+
+	for name := range zipClasspathEntry.zipFiles {
+		className, ok := getClassNameFromPath(name)
+
+		if ok {
+			classNames = append(classNames, className)
+		}
 	}
 
-	return nil, nil, errors.New("Class not found: " + className)
+	return classNames
 }
 
-func (zipClasspathEntry ZipClasspathEntry) ToString() string {
+func (zipClasspathEntry *ZipClasspathEntry) ToString() string {
 	return zipClasspathEntry.absolutePath
 }
@@ -1,38 +1,272 @@
 package main
 
 import (
-	"flag"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// Like java, stacks too small for the VM to run at all are refused
+const minThreadStackSize = 160 * 1024
+
 type Cmd struct {
-	showHelp    bool
-	showVersion bool
-	jrePath     string
-	classpath   string
-	className   string
-	arguments   []string
+	showHelp           bool
+	showVersion        bool
+	verboseClass       bool
+	verboseInstruction bool
+	jrePath            string
+	classpath          string
+	jarPath            string
+	modulePath         string
+	moduleName         string
+	className          string
+	threadStackSize    uint64
+	systemProperties   map[string]string
+	fileSystemRoot     string
+	fileSystemReadOnly bool
+	allowedPaths       []string
+	deniedPaths        []string
+	arguments          []string
 }
 
-func parseCmd() *Cmd {
-	cmd := &Cmd{}
+func parseCmd(args []string) (*Cmd, error) {
+	cmd := &Cmd{
+		systemProperties: map[string]string{},
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") {
+			cmd.className = arg
+			cmd.arguments = args[i+1:]
+
+			return cmd, nil
+		}
+
+		switch {
+		case arg == "-help" || arg == "-h" || arg == "-?" || arg == "--help":
+			cmd.showHelp = true
+		case arg == "-version" || arg == "--version":
+			cmd.showVersion = true
+		case arg == "-verbose" || arg == "-verbose:class":
+			cmd.verboseClass = true
+		case arg == "-verbose:inst":
+			cmd.verboseInstruction = true
+		case arg == "-cp" || arg == "-classpath" || arg == "--class-path":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cmd.classpath = value
+			i++
+		case arg == "-jre":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cmd.jrePath = value
+			i++
+		case arg == "-p" || arg == "--module-path":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cmd.modulePath = value
+			i++
+		case arg == "-m" || arg == "--module":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cmd.moduleName = value
+			cmd.arguments = args[i+2:]
+
+			return cmd, nil
+		case arg == "-jar":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cmd.jarPath = value
+			cmd.arguments = args[i+2:]
+
+			return cmd, nil
+		case arg == "--fs-root":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cmd.fileSystemRoot = value
+			i++
+		case arg == "--fs-readonly":
+			cmd.fileSystemReadOnly = true
+		case arg == "--fs-allow" || arg == "--fs-deny":
+			value, err := getOptionValue(args, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			paths := filepath.SplitList(value)
+
+			if arg == "--fs-allow" {
+				cmd.allowedPaths = append(cmd.allowedPaths, paths...)
+			} else {
+				cmd.deniedPaths = append(cmd.deniedPaths, paths...)
+			}
+
+			i++
+		case strings.HasPrefix(arg, "-D"):
+			name, value := parseSystemProperty(arg[2:])
+
+			if name == "" {
+				return nil, errors.New("Invalid system property: " + arg)
+			}
+
+			cmd.systemProperties[name] = value
+		case strings.HasPrefix(arg, "-Xss"):
+			threadStackSize, err := parseMemorySize(arg[4:])
+
+			if err != nil || threadStackSize == 0 {
+				return nil, errors.New("Invalid thread stack size: " + arg)
+			}
+
+			if threadStackSize < minThreadStackSize {
+				return nil, fmt.Errorf("The Java thread stack size specified is too small. Specify at least %dk", minThreadStackSize/1024)
+			}
+
+			cmd.threadStackSize = threadStackSize
+		case strings.HasPrefix(arg, "-Xmx") || strings.HasPrefix(arg, "-Xms"):
+			// Objects live in the Go heap, its size is not set
+			_, err := parseMemorySize(arg[4:])
+
+			if err != nil {
+				return nil, errors.New("Invalid heap size: " + arg)
+			}
+		case isIgnoredOption(arg):
+		default:
+			return nil, errors.New("Unrecognized option: " + arg)
+		}
+	}
+
+	return cmd, nil
+}
+
+// Options of the java launcher scripts commonly pass, which mean nothing to this VM
+func isIgnoredOption(arg string) bool {
+	switch arg {
+	case "-server", "-client", "-esa", "-dsa", "-enablesystemassertions", "-disablesystemassertions":
+		return true
+	}
+
+	for _, prefix := range []string{"-ea", "-da", "-enableassertions", "-disableassertions"} {
+		if arg == prefix || strings.HasPrefix(arg, prefix+":") {
+			return true
+		}
+	}
+
+	return strings.HasPrefix(arg, "-XX:")
+}
+
+func getOptionValue(args []string, index int) (string, error) {
+	if index+1 >= len(args) {
+		return "", fmt.Errorf("%s requires an argument", args[index])
+	}
+
+	return args[index+1], nil
+}
+
+func parseSystemProperty(property string) (string, string) {
+	index := strings.Index(property, "=")
+
+	if index < 0 {
+		return property, ""
+	}
+
+	return property[:index], property[index+1:]
+}
+
+// Parse sizes like 512k, 1m or 1g into bytes
+func parseMemorySize(size string) (uint64, error) {
+	if size == "" {
+		return 0, errors.New("Empty memory size")
+	}
+
+	multiplier := uint64(1)
+
+	switch size[len(size)-1] {
+	case 'k', 'K':
+		multiplier = 1024
+	case 'm', 'M':
+		multiplier = 1024 * 1024
+	case 'g', 'G':
+		multiplier = 1024 * 1024 * 1024
+	}
+
+	if multiplier != 1 {
+		size = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseUint(size, 10, 64)
 
-	flag.Usage = printCmdUsage
-	flag.BoolVar(&cmd.showHelp, "help", false, "Print help message")
-	flag.BoolVar(&cmd.showHelp, "?", false, "Print help message")
-	flag.BoolVar(&cmd.showVersion, "version", false, "Print version and exit")
-	flag.StringVar(&cmd.jrePath, "jre", "", "jre path")
-	flag.StringVar(&cmd.classpath, "classpath", "", "Classpath")
-	flag.StringVar(&cmd.classpath, "cp", "", "Classpath")
-	flag.StringVar(&cmd.className, "class", "", "Class name")
-	flag.Parse()
+	if err != nil {
+		return 0, err
+	}
 
-	cmd.arguments = flag.Args()
+	if value > math.MaxUint64/multiplier {
+		return 0, errors.New("Memory size out of range")
+	}
 
-	return cmd
+	return value * multiplier, nil
 }
 
-func printCmdUsage() {
-	fmt.Printf("Usage: %s -classpath class/path -class ClassName [arguments...]\n", os.Args[0])
+func printCmdUsage(writer io.Writer) {
+	fmt.Fprintf(writer, "Usage: %s [options] <mainclass> [args...]\n", os.Args[0])
+	fmt.Fprintf(writer, "           (to execute a class)\n")
+	fmt.Fprintf(writer, "   or  %s [options] -jar <jarfile> [args...]\n", os.Args[0])
+	fmt.Fprintf(writer, "           (to execute a jar file)\n")
+	fmt.Fprintf(writer, "   or  %s [options] -m <module>[/<mainclass>] [args...]\n", os.Args[0])
+	fmt.Fprintf(writer, "       %s [options] --module <module>[/<mainclass>] [args...]\n", os.Args[0])
+	fmt.Fprintf(writer, "           (to execute the main class in a module)\n")
+	fmt.Fprintf(writer, "where options include:\n")
+	fmt.Fprintf(writer, "    -cp <class search path of directories and zip/jar files>\n")
+	fmt.Fprintf(writer, "    -classpath <class search path of directories and zip/jar files>\n")
+	fmt.Fprintf(writer, "    -p <module path>\n")
+	fmt.Fprintf(writer, "    --module-path <module path>\n")
+	fmt.Fprintf(writer, "                  directories of modules, exploded modules and modular jars\n")
+	fmt.Fprintf(writer, "    -jre <jre path>\n")
+	fmt.Fprintf(writer, "    -D<name>=<value>\n")
+	fmt.Fprintf(writer, "                  set a system property\n")
+	fmt.Fprintf(writer, "    -Xss<size>    set java thread stack size\n")
+	fmt.Fprintf(writer, "    -Xms<size> -Xmx<size> -ea -da -server -XX:<option>\n")
+	fmt.Fprintf(writer, "                  accepted for compatibility and ignored\n")
+	fmt.Fprintf(writer, "    --fs-root <directory>\n")
+	fmt.Fprintf(writer, "                  only allow file access below the directory\n")
+	fmt.Fprintf(writer, "    --fs-readonly deny creating, writing and deleting files\n")
+	fmt.Fprintf(writer, "    --fs-allow <paths>\n")
+	fmt.Fprintf(writer, "                  only allow file access below these paths, may be repeated\n")
+	fmt.Fprintf(writer, "    --fs-deny <paths>\n")
+	fmt.Fprintf(writer, "                  deny file access below these paths, may be repeated\n")
+	fmt.Fprintf(writer, "    -verbose:[class|inst]\n")
+	fmt.Fprintf(writer, "                  enable verbose output\n")
+	fmt.Fprintf(writer, "    -version      print product version and exit\n")
+	fmt.Fprintf(writer, "    -? -help      print this help message\n")
 }
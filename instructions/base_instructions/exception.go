@@ -0,0 +1,119 @@
+package base_instructions
+
+import (
+	"reflect"
+
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Frames ThrowException may push: the athrow shim, the constructor and class initializers
+const throwExceptionFramesCount = 16
+
+// Create an exception of the given class and throw it in the guest. The constructor runs
+// first, then a shim frame below it throws the new exception like athrow would.
+func ThrowException(frame *runtime_data_area.Frame, className, message string) {
+	thread := frame.GetThread()
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	exceptionClass := classLoader.LoadClass(className)
+	exception := exceptionClass.NewObject()
+
+	// Exceptions without a message constructor are created without the message
+	constructor := exceptionClass.GetConstructor("(Ljava/lang/String;)V")
+
+	if constructor == nil {
+		constructor = exceptionClass.GetConstructor("()V")
+	}
+
+	// With no frames left for the constructor it goes without message or stack trace,
+	// like HotSpot's preallocated StackOverflowError
+	if constructor == nil || thread.GetFreeFramesCount() < throwExceptionFramesCount {
+		Throw(thread, exception)
+
+		return
+	}
+
+	shimFrame := thread.NewFrame(heap.GetAThrowShimMethod())
+	shimFrame.GetOperandStack().PushReferenceValue(exception)
+	thread.PushFrame(shimFrame)
+
+	var javaMessage *heap.Object
+
+	if message != "" {
+		javaMessage = heap.ConvertGoStringToJavaString(classLoader, message)
+	}
+
+	constructorFrame := thread.NewFrame(constructor)
+	constructorFrame.GetLocalVariables().SetReferenceValue(0, exception)
+
+	if constructor.GetArgumentsCount() == 2 {
+		constructorFrame.GetLocalVariables().SetReferenceValue(1, javaMessage)
+	}
+
+	thread.PushFrame(constructorFrame)
+
+	if !exceptionClass.IsInitializationStarted() {
+		InitializeClass(thread, exceptionClass)
+	}
+}
+
+// Unwind to the nearest handler of the exception, or end the thread with it
+func Throw(thread *runtime_data_area.Thread, exception *heap.Object) {
+	if !findAndGotoExceptionHandler(thread, exception) {
+		handleUncaughtException(thread, exception)
+	}
+}
+
+func findAndGotoExceptionHandler(thread *runtime_data_area.Thread, exception *heap.Object) bool {
+	for {
+		frame := thread.GetCurrentFrame()
+		pc := frame.GetNextPC() - 1
+
+		handlerPC := frame.GetMethod().FindExceptionHandler(exception.GetClass(), pc)
+
+		if handlerPC > 0 {
+			operandStack := frame.GetOperandStack()
+			operandStack.Clear()
+			operandStack.PushReferenceValue(exception)
+
+			frame.SetNextPC(handlerPC)
+
+			return true
+		}
+
+		thread.PopFrame()
+
+		if thread.IsJVMStackEmpty() {
+			break
+		}
+	}
+
+	return false
+}
+
+func handleUncaughtException(thread *runtime_data_area.Thread, exception *heap.Object) {
+	thread.ClearStack()
+	thread.SetUncaughtException(exception)
+
+	javaMessage := exception.GetReferenceValue("detailMessage", "Ljava/lang/String;")
+
+	if javaMessage != nil {
+		println(exception.GetClass().GetJavaName() + ": " + heap.ConvertJavaStringToGoString(javaMessage))
+	} else {
+		println(exception.GetClass().GetJavaName())
+	}
+
+	if exception.GetExtraData() == nil {
+		return
+	}
+
+	stackTraceElements := reflect.ValueOf(exception.GetExtraData())
+
+	for i := 0; i < stackTraceElements.Len(); i++ {
+		stackTraceElement := stackTraceElements.Index(i).Interface().(interface {
+			String() string
+		})
+
+		println("\tat " + stackTraceElement.String())
+	}
+}
@@ -19,4 +19,15 @@ func InvokeMethod(frame *runtime_data_area.Frame, method *heap.Method) {
 			newFrame.GetLocalVariables().SetVariable(uint(i), operand)
 		}
 	}
+
+	newFrame.EnterMethodMonitor()
+}
+
+// Invoke a method returning a single slot value as a statement, for natives calling
+// into Java several times; the calls run in the reverse order they are made
+func InvokeMethodDiscardingResult(frame *runtime_data_area.Frame, method *heap.Method) {
+	thread := frame.GetThread()
+	thread.PushFrame(thread.NewFrame(heap.GetDiscardShimMethod()))
+
+	InvokeMethod(frame, method)
 }
@@ -34,7 +34,83 @@ func loadConstantFromConstantPoolAndPushToOperandStack(frame *runtime_data_area.
 		classReference := constant.(*heap.ClassReference)
 		classObject := classReference.GetResolvedClass().GetJavaClass()
 		operandStack.PushReferenceValue(classObject)
+	case *heap.MethodTypeReference:
+		methodTypeReference := constant.(*heap.MethodTypeReference)
+
+		if methodTypeReference.GetMethodType() != nil {
+			operandStack.PushReferenceValue(methodTypeReference.GetMethodType())
+		} else {
+			loadMethodType(frame, methodTypeReference, index)
+		}
+	case *heap.MethodHandleReference:
+		methodHandleReference := constant.(*heap.MethodHandleReference)
+
+		if methodHandleReference.GetMethodHandle() != nil {
+			operandStack.PushReferenceValue(methodHandleReference.GetMethodHandle())
+		} else {
+			loadMethodHandle(frame, methodHandleReference, index)
+		}
+	case *heap.DynamicConstantReference:
+		constant.(*heap.DynamicConstantReference).ResolveConstant()
 	default:
 		panic("TODO: ldc")
 	}
 }
+
+// Method types and handles are created by the same Java methods HotSpot calls to resolve them,
+// the constant shim keeps their result on the constant and returns it to the ldc
+func loadMethodType(frame *runtime_data_area.Frame, methodTypeReference *heap.MethodTypeReference, index uint) {
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	methodTypeClass := classLoader.LoadClass("java/lang/invoke/MethodType")
+
+	if !methodTypeClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), methodTypeClass)
+
+		return
+	}
+
+	operandStack := frame.GetOperandStack()
+	operandStack.PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, methodTypeReference.GetDescriptor()))
+	operandStack.PushReferenceValue(nil)
+
+	factoryMethod := methodTypeClass.GetStaticMethod("fromMethodDescriptorString",
+		"(Ljava/lang/String;Ljava/lang/ClassLoader;)Ljava/lang/invoke/MethodType;")
+
+	invokeConstantFactory(frame, factoryMethod, index)
+}
+
+func loadMethodHandle(frame *runtime_data_area.Frame, methodHandleReference *heap.MethodHandleReference, index uint) {
+	class := frame.GetMethod().GetClass()
+	classLoader := class.GetClassLoader()
+	methodHandleNativesClass := classLoader.LoadClass("java/lang/invoke/MethodHandleNatives")
+
+	if !methodHandleNativesClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), methodHandleNativesClass)
+
+		return
+	}
+
+	memberReference := methodHandleReference.GetMemberReference()
+	operandStack := frame.GetOperandStack()
+	operandStack.PushReferenceValue(class.GetJavaClass())
+	operandStack.PushIntegerValue(int32(methodHandleReference.GetReferenceKind()))
+	operandStack.PushReferenceValue(memberReference.GetResolvedClass().GetJavaClass())
+	operandStack.PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, memberReference.GetName()))
+	operandStack.PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, memberReference.GetDescriptor()))
+
+	factoryMethod := methodHandleNativesClass.GetStaticMethod("linkMethodHandleConstant",
+		"(Ljava/lang/Class;ILjava/lang/Class;Ljava/lang/String;Ljava/lang/Object;)Ljava/lang/invoke/MethodHandle;")
+
+	invokeConstantFactory(frame, factoryMethod, index)
+}
+
+func invokeConstantFactory(frame *runtime_data_area.Frame, factoryMethod *heap.Method, index uint) {
+	thread := frame.GetThread()
+	shimFrame := thread.NewFrame(heap.GetConstantShimMethod())
+	shimFrame.GetLocalVariables().SetIntegerValue(0, int32(index))
+
+	thread.PushFrame(shimFrame)
+	base_instructions.InvokeMethod(frame, factoryMethod)
+}
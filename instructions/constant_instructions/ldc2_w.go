@@ -3,6 +3,7 @@ package constant_instructions
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // ldc2_w
@@ -21,7 +22,9 @@ func (ldc2W *Ldc2W) Execute(frame *runtime_data_area.Frame) {
 		operandStack.PushLongValue(constant.(int64))
 	case float64:
 		operandStack.PushDoubleValue(constant.(float64))
+	case *heap.DynamicConstantReference:
+		constant.(*heap.DynamicConstantReference).ResolveConstant()
 	default:
-		panic("java.lang.ClassFormatError")
+		panic(heap.NewJavaException("java/lang/ClassFormatError", ""))
 	}
 }
@@ -0,0 +1,19 @@
+package control_instructions
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+)
+
+// jsr
+// Jump subroutine
+type JSR struct {
+	base_instructions.BranchInstruction
+}
+
+// The return address is the pc of the next instruction, ret jumps back to it
+func (jsr *JSR) Execute(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(int32(frame.GetNextPC()))
+
+	base_instructions.JumpToBranch(frame, jsr.Offset)
+}
@@ -0,0 +1,18 @@
+package control_instructions
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+)
+
+// ret
+// Return from subroutine
+type RET struct {
+	base_instructions.Index8Instruction
+}
+
+func (ret *RET) Execute(frame *runtime_data_area.Frame) {
+	returnAddress := frame.GetLocalVariables().GetIntegerValue(ret.Index)
+
+	frame.SetNextPC(int(returnAddress))
+}
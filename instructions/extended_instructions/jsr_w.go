@@ -0,0 +1,22 @@
+package extended_instructions
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+)
+
+// jsr_w
+// Jump subroutine (wide index)
+type JSRW struct {
+	offset int
+}
+
+func (jsrW *JSRW) FetchOperands(bytecodeReader *base_instructions.BytecodeReader) {
+	jsrW.offset = int(bytecodeReader.ReadInt32())
+}
+
+func (jsrW *JSRW) Execute(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(int32(frame.GetNextPC()))
+
+	base_instructions.JumpToBranch(frame, jsrW.offset)
+}
@@ -2,6 +2,7 @@ package extended_instructions
 
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/instructions/control_instructions"
 	"github.com/Frederick-S/jvmgo/instructions/load_instructions"
 	"github.com/Frederick-S/jvmgo/instructions/math_instructions"
 	"github.com/Frederick-S/jvmgo/instructions/store_instructions"
@@ -64,7 +65,9 @@ func (wide *Wide) FetchOperands(bytecodeReader *base_instructions.BytecodeReader
 		instruction.Constant = int32(bytecodeReader.ReadInt16())
 		wide.modifiedInstruction = instruction
 	case 0xa9:
-		panic("Unsupported operation code: 0xa9!")
+		instruction := &control_instructions.RET{}
+		instruction.Index = uint(bytecodeReader.ReadUint16())
+		wide.modifiedInstruction = instruction
 	}
 }
 
@@ -163,8 +163,9 @@ var (
 	_return     = &control_instructions.Return{}
 	arraylength = &reference_instructions.ArrayLength{}
 	athrow      = &reference_instructions.AThrow{}
-	// monitorenter  = &MONITOR_ENTER{}
-	// monitorexit   = &MONITOR_EXIT{}
+
+	monitorenter  = &reference_instructions.MonitorEnter{}
+	monitorexit   = &reference_instructions.MonitorExit{}
 	invoke_native = &reserved_instructions.InvokeNative{}
 )
 
@@ -506,10 +507,10 @@ func NewInstruction(operationCode byte) base_instructions.Instruction {
 		return &comparison_instructions.IfACmpNe{}
 	case 0xa7:
 		return &control_instructions.GoTo{}
-	// case 0xa8:
-	// 	return &JSR{}
-	// case 0xa9:
-	// 	return &RET{}
+	case 0xa8:
+		return &control_instructions.JSR{}
+	case 0xa9:
+		return &control_instructions.RET{}
 	case 0xaa:
 		return &control_instructions.TableSwitch{}
 	case 0xab:
@@ -542,8 +543,8 @@ func NewInstruction(operationCode byte) base_instructions.Instruction {
 		return &reference_instructions.InvokeStatic{}
 	case 0xb9:
 		return &reference_instructions.InvokeInterface{}
-		// case 0xba:
-		// 	return &INVOKE_DYNAMIC{}
+	case 0xba:
+		return &reference_instructions.InvokeDynamic{}
 	case 0xbb:
 		return &reference_instructions.New{}
 	case 0xbc:
@@ -558,10 +559,10 @@ func NewInstruction(operationCode byte) base_instructions.Instruction {
 		return &reference_instructions.CheckCast{}
 	case 0xc1:
 		return &reference_instructions.InstanceOf{}
-	// case 0xc2:
-	// 	return monitorenter
-	// case 0xc3:
-	// 	return monitorexit
+	case 0xc2:
+		return monitorenter
+	case 0xc3:
+		return monitorexit
 	case 0xc4:
 		return &extended_instructions.Wide{}
 	case 0xc5:
@@ -572,9 +573,9 @@ func NewInstruction(operationCode byte) base_instructions.Instruction {
 		return &extended_instructions.IfNoNull{}
 	case 0xc8:
 		return &extended_instructions.GoToW{}
-		// case 0xc9:
-		// 	return &JSR_W{}
-		// case 0xca: breakpoint
+	case 0xc9:
+		return &extended_instructions.JSRW{}
+	// case 0xca: breakpoint
 	case 0xfe:
 		return invoke_native
 	// case 0xff: impdep2
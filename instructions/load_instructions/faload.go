@@ -1,8 +1,11 @@
 package load_instructions
 
 import (
+	"fmt"
+
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // faload
@@ -17,13 +20,13 @@ func (fALoad *FALoad) Execute(frame *runtime_data_area.Frame) {
 	arrayReference := operandStack.PopReferenceValue()
 
 	if arrayReference == nil {
-		panic("java.lang.NullPointerException")
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 	}
 
 	floatArray := arrayReference.GetFloatArray()
 
 	if index < 0 || index >= int32(len(floatArray)) {
-		panic("ArrayIndexOutOfBoundsException")
+		panic(heap.NewJavaException("java/lang/ArrayIndexOutOfBoundsException", fmt.Sprintf("Index %d out of bounds for length %d", index, len(floatArray))))
 	}
 
 	operandStack.PushFloatValue(floatArray[index])
@@ -3,6 +3,7 @@ package math_instructions
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // idiv
@@ -16,5 +17,9 @@ func (iDiv *IDiv) Execute(frame *runtime_data_area.Frame) {
 	integerValue2 := operandStack.PopIntegerValue()
 	integerValue1 := operandStack.PopIntegerValue()
 
+	if integerValue2 == 0 {
+		panic(heap.NewJavaException("java/lang/ArithmeticException", "/ by zero"))
+	}
+
 	operandStack.PushIntegerValue(integerValue1 / integerValue2)
 }
@@ -3,6 +3,7 @@ package math_instructions
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // irem
@@ -17,7 +18,7 @@ func (iRem *IRem) Execute(frame *runtime_data_area.Frame) {
 	integerValue1 := operandStack.PopIntegerValue()
 
 	if integerValue2 == 0 {
-		panic("java.lang.ArithmeticException: / by zero")
+		panic(heap.NewJavaException("java/lang/ArithmeticException", "/ by zero"))
 	}
 
 	operandStack.PushIntegerValue(integerValue1 % integerValue2)
@@ -3,6 +3,7 @@ package math_instructions
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // ldiv
@@ -16,5 +17,9 @@ func (lDiv *LDiv) Execute(frame *runtime_data_area.Frame) {
 	longValue2 := operandStack.PopLongValue()
 	longValue1 := operandStack.PopLongValue()
 
+	if longValue2 == 0 {
+		panic(heap.NewJavaException("java/lang/ArithmeticException", "/ by zero"))
+	}
+
 	operandStack.PushLongValue(longValue1 / longValue2)
 }
@@ -3,6 +3,7 @@ package math_instructions
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // lrem
@@ -17,7 +18,7 @@ func (lRem *LRem) Execute(frame *runtime_data_area.Frame) {
 	longValue1 := operandStack.PopLongValue()
 
 	if longValue2 == 0 {
-		panic("java.lang.ArithmeticException: / by zero")
+		panic(heap.NewJavaException("java/lang/ArithmeticException", "/ by zero"))
 	}
 
 	operandStack.PushLongValue(longValue1 % longValue2)
@@ -27,6 +27,7 @@ func (checkCast *CheckCast) Execute(frame *runtime_data_area.Frame) {
 	class := classReference.GetResolvedClass()
 
 	if !objectReference.IsInstanceOf(class) {
-		panic("java.lang.ClassCastException")
+		panic(heap.NewJavaException("java/lang/ClassCastException",
+			"class "+objectReference.GetClass().GetJavaName()+" cannot be cast to class "+class.GetJavaName()))
 	}
 }
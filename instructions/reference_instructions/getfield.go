@@ -18,14 +18,14 @@ func (getField *GetField) Execute(frame *runtime_data_area.Frame) {
 	field := fieldReference.GetResolvedField()
 
 	if field.IsStatic() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	operandStack := frame.GetOperandStack()
 	objectReference := operandStack.PopReferenceValue()
 
 	if objectReference == nil {
-		panic("java.lang.NullPointerException")
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 	}
 
 	variableIndex := field.GetVariableIndex()
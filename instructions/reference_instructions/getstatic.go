@@ -26,7 +26,7 @@ func (getStatic *GetStatic) Execute(frame *runtime_data_area.Frame) {
 	}
 
 	if !field.IsStatic() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	variableIndex := field.GetVariableIndex()
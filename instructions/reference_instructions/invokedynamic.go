@@ -0,0 +1,28 @@
+package reference_instructions
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// invokedynamic
+// Invoke a dynamically-computed call site
+type InvokeDynamic struct {
+	index uint
+}
+
+func (invokeDynamic *InvokeDynamic) FetchOperands(bytecodeReader *base_instructions.BytecodeReader) {
+	invokeDynamic.index = uint(bytecodeReader.ReadUint16())
+
+	bytecodeReader.ReadUint8()
+	bytecodeReader.ReadUint8()
+}
+
+func (invokeDynamic *InvokeDynamic) Execute(frame *runtime_data_area.Frame) {
+	constantPool := frame.GetMethod().GetClass().GetConstantPool()
+	invokeDynamicReference := constantPool.GetConstant(invokeDynamic.index).(*heap.InvokeDynamicReference)
+	callSiteMethod := invokeDynamicReference.GetResolvedCallSiteMethod()
+
+	base_instructions.InvokeMethod(frame, callSiteMethod)
+}
@@ -25,27 +25,31 @@ func (invokeInterface *InvokeInterface) Execute(frame *runtime_data_area.Frame)
 	resolvedMethod := methodReference.GetResolvedInterfaceMethod()
 
 	if resolvedMethod.IsStatic() || resolvedMethod.IsPrivate() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	referenceValue := frame.GetOperandStack().GetReferenceValueBelowTop(resolvedMethod.GetArgumentsCount() - 1)
 
 	if referenceValue == nil {
-		panic("java.lang.NullPointerException")
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 	}
 
 	if !referenceValue.GetClass().IsImplementsFrom(methodReference.GetResolvedClass()) {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	methodToBeInvoked := heap.LookupMethodInClass(referenceValue.GetClass(), methodReference.GetName(), methodReference.GetDescriptor())
 
 	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
-		panic("java.lang.AbstractMethodError")
+		methodToBeInvoked = heap.LookupDefaultMethod(referenceValue.GetClass(), methodReference.GetName(), methodReference.GetDescriptor())
+	}
+
+	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
+		panic(heap.NewJavaException("java/lang/AbstractMethodError", ""))
 	}
 
 	if !methodToBeInvoked.IsPublic() {
-		panic("java.lang.IllegalAccessError")
+		panic(heap.NewJavaException("java/lang/IllegalAccessError", ""))
 	}
 
 	base_instructions.InvokeMethod(frame, methodToBeInvoked)
@@ -20,24 +20,24 @@ func (invokeSpecial *InvokeSpecial) Execute(frame *runtime_data_area.Frame) {
 	resolvedMethod := methodReference.GetResolvedMethod()
 
 	if resolvedMethod.GetName() == "<init>" && resolvedMethod.GetClass() != resolvedClass {
-		panic("java.lang.NoSuchMethodError")
+		panic(heap.NewJavaException("java/lang/NoSuchMethodError", ""))
 	}
 
 	if resolvedMethod.IsStatic() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	referenceValue := frame.GetOperandStack().GetReferenceValueBelowTop(resolvedMethod.GetArgumentsCount() - 1)
 
 	if referenceValue == nil {
-		panic("java.lang.NullPointerException")
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 	}
 
 	if resolvedMethod.IsProtected() && resolvedMethod.GetClass().IsSuperClassOf(currentClass) &&
 		resolvedMethod.GetClass().GetPackageName() != currentClass.GetPackageName() &&
 		referenceValue.GetClass() != currentClass &&
 		!referenceValue.GetClass().IsSubClassOf(currentClass) {
-		panic("java.lang.IllegalAccessError")
+		panic(heap.NewJavaException("java/lang/IllegalAccessError", ""))
 	}
 
 	methodToBeInvoked := resolvedMethod
@@ -47,7 +47,7 @@ func (invokeSpecial *InvokeSpecial) Execute(frame *runtime_data_area.Frame) {
 	}
 
 	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
-		panic("java.lang.AbstractMethodError")
+		panic(heap.NewJavaException("java/lang/AbstractMethodError", ""))
 	}
 
 	base_instructions.InvokeMethod(frame, methodToBeInvoked)
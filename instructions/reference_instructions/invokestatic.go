@@ -18,7 +18,7 @@ func (invokeStatic *InvokeStatic) Execute(frame *runtime_data_area.Frame) {
 	resolvedMethod := methodReference.GetResolvedMethod()
 
 	if !resolvedMethod.IsStatic() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	class := resolvedMethod.GetClass()
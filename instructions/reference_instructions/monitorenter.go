@@ -0,0 +1,23 @@
+package reference_instructions
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// monitorenter
+// Enter monitor for object
+type MonitorEnter struct {
+	base_instructions.NoOperandsInstruction
+}
+
+func (monitorEnter *MonitorEnter) Execute(frame *runtime_data_area.Frame) {
+	objectReference := frame.GetOperandStack().PopReferenceValue()
+
+	if objectReference == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	frame.GetThread().EnterMonitor(objectReference)
+}
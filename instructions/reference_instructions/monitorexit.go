@@ -0,0 +1,25 @@
+package reference_instructions
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// monitorexit
+// Exit monitor for object
+type MonitorExit struct {
+	base_instructions.NoOperandsInstruction
+}
+
+func (monitorExit *MonitorExit) Execute(frame *runtime_data_area.Frame) {
+	objectReference := frame.GetOperandStack().PopReferenceValue()
+
+	if objectReference == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if !frame.GetThread().ExitMonitor(objectReference) {
+		panic(heap.NewJavaException("java/lang/IllegalMonitorStateException", "current thread is not owner"))
+	}
+}
@@ -1,6 +1,8 @@
 package reference_instructions
 
 import (
+	"strconv"
+
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
 	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
@@ -37,7 +39,7 @@ func getAndCheckArrayLengthInEachDimension(operandStack *runtime_data_area.Opera
 		arrayLengthInEachDimension[i] = operandStack.PopIntegerValue()
 
 		if arrayLengthInEachDimension[i] < 0 {
-			panic("java.lang.NegativeArraySizeException")
+			panic(heap.NewJavaException("java/lang/NegativeArraySizeException", strconv.Itoa(int(arrayLengthInEachDimension[i]))))
 		}
 	}
 
@@ -25,7 +25,7 @@ func (new *New) Execute(frame *runtime_data_area.Frame) {
 	}
 
 	if class.IsInterface() || class.IsAbstract() {
-		panic("java.lang.InstantiationError")
+		panic(heap.NewJavaException("java/lang/InstantiationError", class.GetJavaName()))
 	}
 
 	frame.GetOperandStack().PushReferenceValue(class.NewObject())
@@ -1,6 +1,8 @@
 package reference_instructions
 
 import (
+	"strconv"
+
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
 	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
@@ -32,7 +34,7 @@ func (newArray *NewArray) Execute(frame *runtime_data_area.Frame) {
 	arrayLength := operandStack.PopIntegerValue()
 
 	if arrayLength < 0 {
-		panic("java.lang.NegativeArraySizeException")
+		panic(heap.NewJavaException("java/lang/NegativeArraySizeException", strconv.Itoa(int(arrayLength))))
 	}
 
 	classLoader := frame.GetMethod().GetClass().GetClassLoader()
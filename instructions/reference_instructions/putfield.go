@@ -20,12 +20,12 @@ func (putField *PutField) Execute(frame *runtime_data_area.Frame) {
 	field := fieldReference.GetResolvedField()
 
 	if field.IsStatic() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	if field.IsFinal() {
 		if currentClass != field.GetClass() || currentMethod.GetName() != "<init>" {
-			panic("java.lang.IllegalAccessError")
+			panic(heap.NewJavaException("java/lang/IllegalAccessError", ""))
 		}
 	}
 
@@ -38,7 +38,7 @@ func (putField *PutField) Execute(frame *runtime_data_area.Frame) {
 		objectReference := operandStack.PopReferenceValue()
 
 		if objectReference == nil {
-			panic("java.lang.NullPointerException")
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 		}
 
 		objectReference.GetFields().SetIntegerValue(variableIndex, integerValue)
@@ -47,7 +47,7 @@ func (putField *PutField) Execute(frame *runtime_data_area.Frame) {
 		objectReference := operandStack.PopReferenceValue()
 
 		if objectReference == nil {
-			panic("java.lang.NullPointerException")
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 		}
 
 		objectReference.GetFields().SetFloatValue(variableIndex, floatValue)
@@ -56,7 +56,7 @@ func (putField *PutField) Execute(frame *runtime_data_area.Frame) {
 		objectReference := operandStack.PopReferenceValue()
 
 		if objectReference == nil {
-			panic("java.lang.NullPointerException")
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 		}
 
 		objectReference.GetFields().SetLongValue(variableIndex, longValue)
@@ -65,7 +65,7 @@ func (putField *PutField) Execute(frame *runtime_data_area.Frame) {
 		objectReference := operandStack.PopReferenceValue()
 
 		if objectReference == nil {
-			panic("java.lang.NullPointerException")
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 		}
 
 		objectReference.GetFields().SetDoubleValue(variableIndex, doubleValue)
@@ -74,7 +74,7 @@ func (putField *PutField) Execute(frame *runtime_data_area.Frame) {
 		objectReference := operandStack.PopReferenceValue()
 
 		if objectReference == nil {
-			panic("java.lang.NullPointerException")
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 		}
 
 		objectReference.GetFields().SetReferenceValue(variableIndex, referenceValue)
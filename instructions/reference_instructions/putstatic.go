@@ -28,12 +28,12 @@ func (putStatic *PutStatic) Execute(frame *runtime_data_area.Frame) {
 	}
 
 	if !field.IsStatic() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(heap.NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	if field.IsFinal() {
 		if currentClass != class || currentMethod.GetName() != "<clinit>" {
-			panic("java.lang.IllegalAccessError")
+			panic(heap.NewJavaException("java/lang/IllegalAccessError", ""))
 		}
 	}
 
@@ -3,9 +3,16 @@ package reserved_instructions
 import (
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/native_methods"
+	_ "github.com/Frederick-S/jvmgo/native_methods/java/io"
 	_ "github.com/Frederick-S/jvmgo/native_methods/java/lang"
+	_ "github.com/Frederick-S/jvmgo/native_methods/java/lang/invoke"
+	_ "github.com/Frederick-S/jvmgo/native_methods/java/lang/reflect"
+	_ "github.com/Frederick-S/jvmgo/native_methods/java/security"
+	_ "github.com/Frederick-S/jvmgo/native_methods/java/util/concurrent/atomic"
 	_ "github.com/Frederick-S/jvmgo/native_methods/sun/misc"
+	_ "github.com/Frederick-S/jvmgo/native_methods/sun/reflect"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 type InvokeNative struct {
@@ -16,12 +23,12 @@ func (invokeNative *InvokeNative) Execute(frame *runtime_data_area.Frame) {
 	method := frame.GetMethod()
 	className := method.GetClass().GetName()
 	methodName := method.GetName()
-	methodDescriptor := method.GetDescriptor()
+	methodDescriptor := method.GetDeclaredDescriptor()
 
 	nativeMethod := native_methods.FindNativeMethod(className, methodName, methodDescriptor)
 
 	if nativeMethod == nil {
-		panic("java.lang.UnsatisfiedLinkError: " + className + "." + methodName + methodDescriptor)
+		panic(heap.NewJavaException("java/lang/UnsatisfiedLinkError", method.GetClass().GetJavaName()+"."+methodName+methodDescriptor))
 	}
 
 	nativeMethod(frame)
@@ -1,8 +1,11 @@
 package store_instructions
 
 import (
+	"fmt"
+
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 // aastore
@@ -18,13 +21,17 @@ func (aAStore *AAStore) Execute(frame *runtime_data_area.Frame) {
 	arrayReference := operandStack.PopReferenceValue()
 
 	if arrayReference == nil {
-		panic("java.lang.NullPointerException")
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 	}
 
 	referenceArray := arrayReference.GetReferenceArray()
 
 	if index < 0 || index >= int32(len(referenceArray)) {
-		panic("ArrayIndexOutOfBoundsException")
+		panic(heap.NewJavaException("java/lang/ArrayIndexOutOfBoundsException", fmt.Sprintf("Index %d out of bounds for length %d", index, len(referenceArray))))
+	}
+
+	if referenceValue != nil && !referenceValue.IsInstanceOf(arrayReference.GetClass().GetArrayElementClass()) {
+		panic(heap.NewJavaException("java/lang/ArrayStoreException", referenceValue.GetClass().GetJavaName()))
 	}
 
 	referenceArray[index] = referenceValue
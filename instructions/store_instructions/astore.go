@@ -15,7 +15,8 @@ func (aStore *AStore) Execute(frame *runtime_data_area.Frame) {
 	popReferenceValueAndStore(frame, uint(aStore.Index))
 }
 
+// The whole slot is moved, the value may be the return address a jsr pushed
 func popReferenceValueAndStore(frame *runtime_data_area.Frame, index uint) {
-	value := frame.GetOperandStack().PopReferenceValue()
-	frame.GetLocalVariables().SetReferenceValue(index, value)
+	value := frame.GetOperandStack().PopOperand()
+	frame.GetLocalVariables().SetVariable(index, value)
 }
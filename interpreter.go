@@ -3,13 +3,21 @@ package main
 import (
 	"fmt"
 
+	"github.com/Frederick-S/jvmgo/classpath"
 	"github.com/Frederick-S/jvmgo/instructions"
 	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/options"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
 	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
-func interpret(method *heap.Method, arguments []string) {
+// Instructions a thread runs before letting other threads take the interpreter lock
+const instructionsPerTimeSlice = 1000
+
+func interpret(method *heap.Method, arguments []string) int {
+	runtime_data_area.SetThreadLoop(runThread)
+	runtime_data_area.AcquireInterpreterLock()
+
 	thread := runtime_data_area.NewThread()
 	frame := thread.NewFrame(method)
 	thread.PushFrame(frame)
@@ -18,6 +26,36 @@ func interpret(method *heap.Method, arguments []string) {
 
 	frame.GetLocalVariables().SetReferenceValue(0, javaArguments)
 
+	initializeSystemClass(thread, method.GetClass().GetClassLoader())
+
+	runThread(thread)
+	thread.SetAlive(false)
+
+	// The VM stays up until every non-daemon thread finishes
+	runtime_data_area.ReleaseInterpreterLock()
+	runtime_data_area.WaitForNonDaemonThreads()
+
+	if thread.GetUncaughtException() != nil {
+		return 1
+	}
+
+	return 0
+}
+
+// Runs before main: System.<clinit>, then System.initializeSystemClass which sets up the
+// system properties and System.in, out and err
+func initializeSystemClass(thread *runtime_data_area.Thread, classLoader *heap.ClassLoader) {
+	systemClass := classLoader.LoadClass("java/lang/System")
+	initializeMethod := systemClass.GetStaticMethod("initializeSystemClass", "()V")
+
+	if initializeMethod != nil {
+		thread.PushFrame(thread.NewFrame(initializeMethod))
+	}
+
+	base_instructions.InitializeClass(thread, systemClass)
+}
+
+func runThread(thread *runtime_data_area.Thread) {
 	defer catchError(thread)
 
 	loop(thread)
@@ -46,6 +84,7 @@ func catchError(thread *runtime_data_area.Thread) {
 
 func loop(thread *runtime_data_area.Thread) {
 	bytecodeReader := &base_instructions.BytecodeReader{}
+	instructionsCount := 0
 
 	for {
 		frame := thread.GetCurrentFrame()
@@ -59,13 +98,54 @@ func loop(thread *runtime_data_area.Thread) {
 		instruction.FetchOperands(bytecodeReader)
 		frame.SetNextPC(bytecodeReader.GetPC())
 
-		logInstruction(frame, instruction)
-		instruction.Execute(frame)
+		if options.VerboseInstruction {
+			logInstruction(frame, instruction)
+		}
+
+		execute(frame, instruction)
 
 		if thread.IsJVMStackEmpty() {
 			break
 		}
+
+		instructionsCount++
+
+		if instructionsCount%instructionsPerTimeSlice == 0 {
+			runtime_data_area.YieldInterpreterLock()
+		}
+	}
+}
+
+// Exceptions raised by the VM are thrown into the guest, anything else is a VM bug
+func execute(frame *runtime_data_area.Frame, instruction base_instructions.Instruction) {
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		javaException, ok := r.(*heap.JavaException)
+
+		if !ok {
+			panic(r)
+		}
+
+		base_instructions.ThrowException(frame, javaException.GetClassName(), getGuestMessage(javaException))
+	}()
+
+	instruction.Execute(frame)
+}
+
+// The guest sees which entries were searched for a missing class after its name
+func getGuestMessage(javaException *heap.JavaException) string {
+	classNotFoundError, ok := javaException.GetCause().(*classpath.ClassNotFoundError)
+
+	if ok {
+		return javaException.GetMessage() + "\n" + classNotFoundError.GetSearchReport()
 	}
+
+	return javaException.GetMessage()
 }
 
 func logInstruction(frame *runtime_data_area.Frame, instruction base_instructions.Instruction) {
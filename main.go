@@ -2,38 +2,201 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/Frederick-S/jvmgo/classpath"
+	"github.com/Frederick-S/jvmgo/options"
 	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
+// The newest Java runtime whose class library the VM can start
+const maxRuntimeRelease = 8
+
 func main() {
-	// cmd := parseCmd()
-	cmd := &Cmd{
-		jrePath:   "/Library/Java/JavaVirtualMachines/jdk1.8.0_92.jdk/Contents/Home/jre",
-		className: "./java/ParseIntTest",
+	cmd, err := parseCmd(os.Args[1:])
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, "Error: Could not create the Java Virtual Machine.")
+		os.Exit(1)
 	}
 
 	if cmd.showVersion {
 		fmt.Println("Version 0.0.1")
-	} else if cmd.showHelp || cmd.className == "" {
-		printCmdUsage()
+	} else if cmd.showHelp {
+		printCmdUsage(os.Stdout)
+	} else if cmd.className == "" && cmd.jarPath == "" && cmd.moduleName == "" {
+		printCmdUsage(os.Stderr)
+		os.Exit(1)
 	} else {
-		startJVM(cmd)
+		os.Exit(startJVM(cmd))
 	}
 }
 
-func startJVM(cmd *Cmd) {
-	classFinder := classpath.Parse(cmd.jrePath, cmd.classpath)
+func startJVM(cmd *Cmd) int {
+	applyOptions(cmd)
+
+	classFinder, className, err := parseClasspath(cmd)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		return 1
+	}
+
+	// System.initPhase1 of Java 9 on needs natives the VM does not provide
+	if classpath.GetRuntimeRelease() > maxRuntimeRelease {
+		fmt.Fprintf(os.Stderr, "Error: Java %d runtimes are not supported, use a Java %d runtime with -jre\n", classpath.GetRuntimeRelease(), maxRuntimeRelease)
+
+		return 1
+	}
+
 	classLoader := heap.NewClassLoader(classFinder)
-	className := strings.Replace(cmd.className, ".", "/", -1)
-	mainClass := classLoader.LoadClass(className)
+	mainClass, err := loadMainClass(classLoader, className)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not find or load main class %s\n", className)
+		printLoadMainClassError(err)
+
+		return 1
+	}
+
 	mainMethod := mainClass.GetMainMethod()
 
-	if mainMethod != nil {
-		interpret(mainMethod, cmd.arguments)
-	} else {
-		fmt.Printf("Main method not found in class %s\n", cmd.className)
+	if mainMethod == nil {
+		fmt.Fprintf(os.Stderr, "Error: Main method not found in class %s\n", className)
+
+		return 1
+	}
+
+	return interpret(mainMethod, cmd.arguments)
+}
+
+func parseClasspath(cmd *Cmd) (*classpath.ClassFinder, string, error) {
+	if cmd.moduleName != "" {
+		return parseModulePath(cmd)
+	}
+
+	if cmd.jarPath == "" {
+		classFinder, err := classpath.Parse(cmd.jrePath, cmd.classpath)
+
+		return classFinder, cmd.className, err
+	}
+
+	manifest, err := classpath.ReadJarManifest(cmd.jarPath)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("Error: %v", err)
+	}
+
+	mainClassName := manifest.GetMainClass()
+
+	if mainClassName == "" {
+		return nil, "", fmt.Errorf("no main manifest attribute, in %s", cmd.jarPath)
+	}
+
+	classFinder, err := classpath.ParseJar(cmd.jrePath, cmd.jarPath, manifest)
+
+	return classFinder, mainClassName, err
+}
+
+func parseModulePath(cmd *Cmd) (*classpath.ClassFinder, string, error) {
+	moduleName := cmd.moduleName
+	mainClassName := ""
+	index := strings.Index(moduleName, "/")
+
+	if index >= 0 {
+		moduleName, mainClassName = moduleName[:index], moduleName[index+1:]
+	}
+
+	moduleClasspathEntries, err := classpath.FindModules(cmd.modulePath)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("Error occurred during initialization of boot layer\n%v", err)
+	}
+
+	for _, moduleClasspathEntry := range moduleClasspathEntries {
+		if moduleClasspathEntry.GetModuleName() != moduleName {
+			continue
+		}
+
+		if mainClassName == "" {
+			mainClassName = moduleClasspathEntry.GetMainClassName()
+		}
+
+		if mainClassName == "" {
+			return nil, "", fmt.Errorf("module %s does not have a ModuleMainClass attribute, use -m <module>/<main-class>", moduleName)
+		}
+
+		classFinder, err := classpath.ParseModulePath(cmd.jrePath, cmd.classpath, moduleClasspathEntries)
+
+		return classFinder, mainClassName, err
+	}
+
+	return nil, "", fmt.Errorf("Error occurred during initialization of boot layer\njava.lang.module.FindException: Module %s not found", moduleName)
+}
+
+func applyOptions(cmd *Cmd) {
+	options.VerboseClass = cmd.verboseClass
+	options.VerboseInstruction = cmd.verboseInstruction
+
+	if cmd.threadStackSize > 0 {
+		options.ThreadStackSize = cmd.threadStackSize
+	}
+
+	options.FileSystemRoot = cmd.fileSystemRoot
+	options.FileSystemReadOnly = cmd.fileSystemReadOnly
+	options.FileSystemAllowedPaths = cmd.allowedPaths
+	options.FileSystemDeniedPaths = cmd.deniedPaths
+
+	for name, value := range cmd.systemProperties {
+		options.SystemProperties[name] = value
+	}
+
+	// Same property the JDK uses to select versioned entries of multi-release jars
+	targetRelease, err := strconv.Atoi(cmd.systemProperties["jdk.util.jar.version"])
+
+	if err == nil && targetRelease > 0 {
+		options.TargetRelease = targetRelease
+	}
+}
+
+func loadMainClass(classLoader *heap.ClassLoader, className string) (class *heap.Class, err error) {
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		javaException, ok := r.(*heap.JavaException)
+
+		if ok {
+			err = javaException
+		} else {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	class = classLoader.LoadClass(strings.Replace(className, ".", "/", -1))
+
+	return
+}
+
+func printLoadMainClassError(err error) {
+	fmt.Fprintf(os.Stderr, "Caused by: %v\n", err)
+
+	javaException, ok := err.(*heap.JavaException)
+
+	if !ok {
+		return
+	}
+
+	classNotFoundError, ok := javaException.GetCause().(*classpath.ClassNotFoundError)
+
+	if ok {
+		fmt.Fprintln(os.Stderr, classNotFoundError.GetDiagnostics())
 	}
 }
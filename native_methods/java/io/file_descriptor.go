@@ -0,0 +1,182 @@
+package io
+
+import (
+	"io"
+	"os"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaIOFileDescriptor = "java/io/FileDescriptor"
+
+// Host files behind the values of FileDescriptor.fd, the standard streams are always open
+var openFiles = map[int32]*os.File{
+	0: os.Stdin,
+	1: os.Stdout,
+	2: os.Stderr,
+}
+
+var nextFileDescriptor int32 = 3
+
+func init() {
+	native_methods.RegisterNativeMethod(javaIOFileDescriptor, "initIDs", "()V", initIDs)
+	native_methods.RegisterNativeMethod(javaIOFileDescriptor, "sync", "()V", syncFileDescriptor)
+	native_methods.RegisterNativeMethod(javaIOFileDescriptor, "set", "(I)J", set)
+	native_methods.RegisterNativeMethod(javaIOFileDescriptor, "getHandle", "(I)J", getHandle)
+	native_methods.RegisterNativeMethod(javaIOFileDescriptor, "getAppend", "(I)Z", getAppend)
+	native_methods.RegisterNativeMethod(javaIOFileDescriptor, "close0", "()V", closeFileDescriptor)
+}
+
+// Field ids are looked up by name whenever they are needed
+func initIDs(frame *runtime_data_area.Frame) {
+}
+
+func syncFileDescriptor(frame *runtime_data_area.Frame) {
+	file := getFile(frame.GetLocalVariables().GetThis())
+
+	if err := file.Sync(); err != nil {
+		panic(heap.NewJavaException("java/io/SyncFailedException", "sync failed"))
+	}
+}
+
+// Windows handles, there are none here
+func set(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushLongValue(-1)
+}
+
+func getHandle(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushLongValue(-1)
+}
+
+func getAppend(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(0)
+}
+
+// Java 9+ closes the descriptor itself instead of the stream owning it
+func closeFileDescriptor(frame *runtime_data_area.Frame) {
+	closeFile(frame.GetLocalVariables().GetThis())
+}
+
+// Stores the opened file in the fd field of the stream
+func setFile(object *heap.Object, file *os.File) {
+	fileDescriptor := object.GetReferenceValue("fd", "Ljava/io/FileDescriptor;")
+	fd := nextFileDescriptor
+	nextFileDescriptor++
+	openFiles[fd] = file
+
+	fileDescriptor.SetIntegerValue("fd", "I", fd)
+}
+
+// Closing twice is fine, the standard streams stay open for the host
+func closeFile(object *heap.Object) {
+	fileDescriptor := getFileDescriptor(object)
+
+	if fileDescriptor == nil {
+		return
+	}
+
+	fd := fileDescriptor.GetIntegerValue("fd", "I")
+	file, ok := openFiles[fd]
+
+	fileDescriptor.SetIntegerValue("fd", "I", -1)
+
+	if !ok || fd <= 2 {
+		return
+	}
+
+	delete(openFiles, fd)
+
+	if err := file.Close(); err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+}
+
+func getFileDescriptor(object *heap.Object) *heap.Object {
+	if object.GetClass().GetName() == javaIOFileDescriptor {
+		return object
+	}
+
+	return object.GetReferenceValue("fd", "Ljava/io/FileDescriptor;")
+}
+
+// The open file of a FileInputStream, FileOutputStream or FileDescriptor
+func getFile(object *heap.Object) *os.File {
+	fileDescriptor := getFileDescriptor(object)
+
+	if fileDescriptor == nil {
+		panic(heap.NewJavaException("java/io/IOException", "Stream Closed"))
+	}
+
+	file, ok := openFiles[fileDescriptor.GetIntegerValue("fd", "I")]
+
+	if !ok {
+		panic(heap.NewJavaException("java/io/IOException", "Stream Closed"))
+	}
+
+	return file
+}
+
+// Reads into b[off:off+len], returns -1 at the end of the file
+func readBytesFromFile(file *os.File, bytes *heap.Object, offset, length int32) int32 {
+	checkBounds(bytes, offset, length)
+
+	if length == 0 {
+		return 0
+	}
+
+	data := make([]byte, length)
+	count := readFile(file, data)
+
+	if count == 0 {
+		return -1
+	}
+
+	for i := 0; i < count; i++ {
+		bytes.GetByteArray()[int(offset)+i] = int8(data[i])
+	}
+
+	return int32(count)
+}
+
+func writeBytesToFile(file *os.File, bytes *heap.Object, offset, length int32) {
+	checkBounds(bytes, offset, length)
+
+	data := make([]byte, length)
+
+	for i, value := range bytes.GetByteArray()[offset : offset+length] {
+		data[i] = byte(value)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+}
+
+// Reading may block, e.g. on standard input, so other threads keep running meanwhile
+func readFile(file *os.File, data []byte) int {
+	var count int
+	var err error
+
+	runtime_data_area.RunWithoutInterpreterLock(func() {
+		count, err = file.Read(data)
+	})
+
+	if err != nil && err != io.EOF {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+
+	return count
+}
+
+// Checks the (b, off, len) arguments of readBytes and writeBytes
+func checkBounds(bytes *heap.Object, offset, length int32) {
+	if bytes == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if offset < 0 || length < 0 || length > bytes.GetArrayLength()-offset {
+		panic(heap.NewJavaException("java/lang/IndexOutOfBoundsException", ""))
+	}
+}
@@ -0,0 +1,118 @@
+package io
+
+import (
+	"io"
+	"os"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaIOFileInputStream = "java/io/FileInputStream"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "initIDs", "()V", initIDs)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "open0", "(Ljava/lang/String;)V", openFileInputStream)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "readBytes", "([BII)I", readBytes)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "read0", "()I", read0)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "skip", "(J)J", skip)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "skip0", "(J)J", skip)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "available", "()I", available)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "available0", "()I", available)
+	native_methods.RegisterNativeMethod(javaIOFileInputStream, "close0", "()V", closeStream)
+}
+
+func openFileInputStream(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	path := heap.ConvertJavaStringToGoString(localVariables.GetReferenceValue(1))
+
+	setFile(localVariables.GetThis(), openFileOrThrow(path, os.O_RDONLY))
+}
+
+func readBytes(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+	count := readBytesFromFile(file, localVariables.GetReferenceValue(1), localVariables.GetIntegerValue(2), localVariables.GetIntegerValue(3))
+
+	frame.GetOperandStack().PushIntegerValue(count)
+}
+
+func read0(frame *runtime_data_area.Frame) {
+	file := getFile(frame.GetLocalVariables().GetThis())
+	data := make([]byte, 1)
+	value := int32(-1)
+
+	if readFile(file, data) > 0 {
+		value = int32(data[0])
+	}
+
+	frame.GetOperandStack().PushIntegerValue(value)
+}
+
+// Seeks when possible, otherwise reads and drops the bytes
+func skip(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+	count := localVariables.GetLongValue(1)
+	current, err := file.Seek(0, io.SeekCurrent)
+
+	if err == nil {
+		end, err := file.Seek(count, io.SeekCurrent)
+
+		if err != nil {
+			panic(heap.NewJavaException("java/io/IOException", err.Error()))
+		}
+
+		frame.GetOperandStack().PushLongValue(end - current)
+
+		return
+	}
+
+	skipped := int64(0)
+	data := make([]byte, 8192)
+
+	for skipped < count {
+		length := int64(len(data))
+
+		if count-skipped < length {
+			length = count - skipped
+		}
+
+		readCount := readFile(file, data[:length])
+
+		if readCount == 0 {
+			break
+		}
+
+		skipped += int64(readCount)
+	}
+
+	frame.GetOperandStack().PushLongValue(skipped)
+}
+
+// Bytes left in a regular file, other files such as the terminal report 0
+func available(frame *runtime_data_area.Frame) {
+	file := getFile(frame.GetLocalVariables().GetThis())
+	fileInfo, err := file.Stat()
+
+	if err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+
+	count := int64(0)
+
+	if fileInfo.Mode().IsRegular() {
+		position, err := file.Seek(0, io.SeekCurrent)
+
+		if err == nil && position < fileInfo.Size() {
+			count = fileInfo.Size() - position
+		}
+	}
+
+	if count > 0x7FFFFFFF {
+		count = 0x7FFFFFFF
+	}
+
+	frame.GetOperandStack().PushIntegerValue(int32(count))
+}
@@ -0,0 +1,53 @@
+package io
+
+import (
+	"os"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaIOFileOutputStream = "java/io/FileOutputStream"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaIOFileOutputStream, "initIDs", "()V", initIDs)
+	native_methods.RegisterNativeMethod(javaIOFileOutputStream, "open0", "(Ljava/lang/String;Z)V", openFileOutputStream)
+	native_methods.RegisterNativeMethod(javaIOFileOutputStream, "writeBytes", "([BIIZ)V", writeBytes)
+	native_methods.RegisterNativeMethod(javaIOFileOutputStream, "write", "(IZ)V", write)
+	native_methods.RegisterNativeMethod(javaIOFileOutputStream, "close0", "()V", closeStream)
+}
+
+func openFileOutputStream(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	path := heap.ConvertJavaStringToGoString(localVariables.GetReferenceValue(1))
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+
+	if localVariables.GetIntegerValue(2) != 0 {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	setFile(localVariables.GetThis(), openFileOrThrow(path, flag))
+}
+
+func writeBytes(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+
+	writeBytesToFile(file, localVariables.GetReferenceValue(1), localVariables.GetIntegerValue(2), localVariables.GetIntegerValue(3))
+}
+
+func write(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+	value := localVariables.GetIntegerValue(1)
+
+	if _, err := file.Write([]byte{byte(value)}); err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+}
+
+// Java 8 streams close their descriptor themselves
+func closeStream(frame *runtime_data_area.Frame) {
+	closeFile(frame.GetLocalVariables().GetThis())
+}
@@ -0,0 +1,144 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Frederick-S/jvmgo/options"
+)
+
+// The sandbox options with every path made absolute and canonical, built on first use
+type fileSystemPolicy struct {
+	root         string
+	isReadOnly   bool
+	allowedPaths []string
+	deniedPaths  []string
+}
+
+var policy *fileSystemPolicy
+
+var policyOnce sync.Once
+
+// Threads run on goroutines, the first of them to touch the file system builds the policy
+func getFileSystemPolicy() *fileSystemPolicy {
+	policyOnce.Do(func() {
+		policy = &fileSystemPolicy{
+			isReadOnly:   options.FileSystemReadOnly,
+			allowedPaths: getCanonicalPaths(options.FileSystemAllowedPaths),
+			deniedPaths:  getCanonicalPaths(options.FileSystemDeniedPaths),
+		}
+
+		if options.FileSystemRoot != "" {
+			policy.root = getCanonicalPath(options.FileSystemRoot)
+		}
+	})
+
+	return policy
+}
+
+// Returns the canonical path, or a permission error for paths the options do not give access to.
+// Symbolic links are resolved first so that they cannot point out of the allowed directories, the
+// host file system must be given the canonical path for the same reason
+func checkFileAccess(path string, isWrite bool) (string, error) {
+	return checkAccess(path, getCanonicalPath(path), isWrite)
+}
+
+// Like checkFileAccess for operations on the directory entry itself, deleting a symbolic link
+// deletes the link, so only the directory it is in is made canonical
+func checkEntryAccess(path string, isWrite bool) (string, error) {
+	return checkAccess(path, getCanonicalEntryPath(path), isWrite)
+}
+
+func checkAccess(path, canonicalPath string, isWrite bool) (string, error) {
+	policy := getFileSystemPolicy()
+
+	if isWrite && policy.isReadOnly {
+		return "", &os.PathError{Op: "write", Path: path, Err: os.ErrPermission}
+	}
+
+	if !policy.isAllowed(canonicalPath) {
+		return "", &os.PathError{Op: "access", Path: path, Err: os.ErrPermission}
+	}
+
+	return canonicalPath, nil
+}
+
+func (policy *fileSystemPolicy) isAllowed(canonicalPath string) bool {
+	if policy.root != "" && !isPathBelow(canonicalPath, policy.root) {
+		return false
+	}
+
+	for _, deniedPath := range policy.deniedPaths {
+		if isPathBelow(canonicalPath, deniedPath) {
+			return false
+		}
+	}
+
+	if len(policy.allowedPaths) == 0 {
+		return true
+	}
+
+	for _, allowedPath := range policy.allowedPaths {
+		if isPathBelow(canonicalPath, allowedPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getCanonicalPath(path string) string {
+	absolutePath, err := filepath.Abs(path)
+
+	if err != nil {
+		absolutePath = path
+	}
+
+	return canonicalize(absolutePath)
+}
+
+func getCanonicalEntryPath(path string) string {
+	absolutePath, err := filepath.Abs(path)
+
+	if err != nil {
+		absolutePath = filepath.Clean(path)
+	}
+
+	directory := filepath.Dir(absolutePath)
+
+	if directory == absolutePath {
+		return absolutePath
+	}
+
+	return filepath.Join(canonicalize(directory), filepath.Base(absolutePath))
+}
+
+func getCanonicalPaths(paths []string) []string {
+	canonicalPaths := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		if path != "" {
+			canonicalPaths = append(canonicalPaths, getCanonicalPath(path))
+		}
+	}
+
+	return canonicalPaths
+}
+
+// True for the directory itself and everything inside it
+func isPathBelow(path, directory string) bool {
+	relativePath, err := filepath.Rel(directory, path)
+
+	if err != nil {
+		return false
+	}
+
+	return relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
+
+// Opening with any of these flags can change the file
+func isWriteFlag(flag int) bool {
+	return flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0
+}
@@ -0,0 +1,250 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Replaces the policy the options would build, for the rest of the test
+func setTestFileSystemPolicy(t *testing.T, testPolicy *fileSystemPolicy) {
+	policyOnce.Do(func() {})
+	policy = testPolicy
+
+	t.Cleanup(func() {
+		policy = &fileSystemPolicy{}
+	})
+}
+
+// A sandbox directory and a directory outside it, both canonical
+func newTestDirectories(t *testing.T) (string, string) {
+	directory := getCanonicalPath(t.TempDir())
+	sandboxDirectory := filepath.Join(directory, "sandbox")
+	outsideDirectory := filepath.Join(directory, "outside")
+
+	for _, path := range []string{sandboxDirectory, outsideDirectory} {
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return sandboxDirectory, outsideDirectory
+}
+
+func writeTestFile(t *testing.T, path string) {
+	if err := ioutil.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsPathBelow(t *testing.T) {
+	tests := []struct {
+		path      string
+		directory string
+		isBelow   bool
+	}{
+		{"/a", "/a", true},
+		{"/a/b", "/a", true},
+		{"/a/b/c", "/a", true},
+		{"/a/..b", "/a", true},
+		{"/", "/", true},
+		{"/a", "/", true},
+		{"/ab", "/a", false},
+		{"/", "/a", false},
+		{"/b/a", "/a", false},
+		{"/a", "/a/b", false},
+	}
+
+	for _, test := range tests {
+		isBelow := isPathBelow(test.path, test.directory)
+
+		if isBelow != test.isBelow {
+			t.Errorf("isPathBelow(%q, %q) = %t, expected %t", test.path, test.directory, isBelow, test.isBelow)
+		}
+	}
+}
+
+func TestFileSystemPolicyIsAllowed(t *testing.T) {
+	testPolicy := &fileSystemPolicy{
+		root:         "/sandbox",
+		allowedPaths: []string{"/sandbox/data", "/sandbox/tmp"},
+		deniedPaths:  []string{"/sandbox/data/secret"},
+	}
+
+	tests := []struct {
+		path      string
+		isAllowed bool
+	}{
+		{"/sandbox/data", true},
+		{"/sandbox/data/a.txt", true},
+		{"/sandbox/tmp/b/c.txt", true},
+		{"/sandbox/data/secret", false},
+		{"/sandbox/data/secret/key", false},
+		{"/sandbox/data/secrets", true},
+		{"/sandbox/other", false},
+		{"/sandbox", false},
+		{"/etc/passwd", false},
+	}
+
+	for _, test := range tests {
+		isAllowed := testPolicy.isAllowed(test.path)
+
+		if isAllowed != test.isAllowed {
+			t.Errorf("isAllowed(%q) = %t, expected %t", test.path, isAllowed, test.isAllowed)
+		}
+	}
+
+	// Without a root or allowed paths only the denied paths are off limits
+	testPolicy = &fileSystemPolicy{deniedPaths: []string{"/etc"}}
+
+	if !testPolicy.isAllowed("/home/a.txt") || testPolicy.isAllowed("/etc/passwd") {
+		t.Error("isAllowed without root and allowed paths")
+	}
+}
+
+func TestCheckFileAccessResolvesSymbolicLinks(t *testing.T) {
+	sandboxDirectory, outsideDirectory := newTestDirectories(t)
+	setTestFileSystemPolicy(t, &fileSystemPolicy{root: sandboxDirectory})
+
+	writeTestFile(t, filepath.Join(sandboxDirectory, "inside.txt"))
+	writeTestFile(t, filepath.Join(outsideDirectory, "secret.txt"))
+
+	if err := os.Symlink(outsideDirectory, filepath.Join(sandboxDirectory, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink("inside.txt", filepath.Join(sandboxDirectory, "alias.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(sandboxDirectory, "escape", "secret.txt"),
+		filepath.Join(sandboxDirectory, "escape", "missing", "new.txt"),
+		filepath.Join(sandboxDirectory, "..", "outside", "secret.txt"),
+	} {
+		if _, err := checkFileAccess(path, false); !os.IsPermission(err) {
+			t.Errorf("access to %s: %v", path, err)
+		}
+	}
+
+	canonicalPath, err := checkFileAccess(filepath.Join(sandboxDirectory, "alias.txt"), false)
+
+	if err != nil || canonicalPath != filepath.Join(sandboxDirectory, "inside.txt") {
+		t.Errorf("access to alias.txt: %q, %v", canonicalPath, err)
+	}
+}
+
+func TestCreateHostFileExclusivelyDoesNotFollowDanglingLinks(t *testing.T) {
+	sandboxDirectory, outsideDirectory := newTestDirectories(t)
+	setTestFileSystemPolicy(t, &fileSystemPolicy{root: sandboxDirectory})
+
+	outsidePath := filepath.Join(outsideDirectory, "created.txt")
+
+	if err := os.Symlink(outsidePath, filepath.Join(sandboxDirectory, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := createHostFileExclusively(filepath.Join(sandboxDirectory, "dangling")); !os.IsExist(err) {
+		t.Errorf("creating over a dangling link: %v", err)
+	}
+
+	if _, err := os.Lstat(outsidePath); !os.IsNotExist(err) {
+		t.Errorf("file created outside the sandbox: %v", err)
+	}
+}
+
+func TestReadOnlyFileSystemPolicy(t *testing.T) {
+	sandboxDirectory, _ := newTestDirectories(t)
+	setTestFileSystemPolicy(t, &fileSystemPolicy{root: sandboxDirectory, isReadOnly: true})
+
+	path := filepath.Join(sandboxDirectory, "a.txt")
+	writeTestFile(t, path)
+
+	file, err := openHostFile(path, os.O_RDONLY)
+
+	if err != nil {
+		t.Fatalf("reading: %v", err)
+	}
+
+	file.Close()
+
+	for _, flag := range []int{os.O_WRONLY, os.O_RDWR, os.O_RDONLY | os.O_CREATE, os.O_RDONLY | os.O_TRUNC, os.O_WRONLY | os.O_APPEND} {
+		if _, err := openHostFile(path, flag); !os.IsPermission(err) {
+			t.Errorf("opening with flag %#x: %v", flag, err)
+		}
+	}
+
+	if err := removeHostFile(path); !os.IsPermission(err) {
+		t.Errorf("removing: %v", err)
+	}
+
+	if err := renameHostFile(path, filepath.Join(sandboxDirectory, "b.txt")); !os.IsPermission(err) {
+		t.Errorf("renaming: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("file changed: %v", err)
+	}
+}
+
+func TestRemoveHostFileRemovesTheLink(t *testing.T) {
+	sandboxDirectory, outsideDirectory := newTestDirectories(t)
+	setTestFileSystemPolicy(t, &fileSystemPolicy{root: sandboxDirectory})
+
+	targetPath := filepath.Join(outsideDirectory, "target.txt")
+	linkPath := filepath.Join(sandboxDirectory, "link")
+	writeTestFile(t, targetPath)
+
+	if err := os.Symlink(targetPath, linkPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := removeHostFile(linkPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Lstat(linkPath); !os.IsNotExist(err) {
+		t.Errorf("link still there: %v", err)
+	}
+
+	if _, err := os.Stat(targetPath); err != nil {
+		t.Errorf("target removed: %v", err)
+	}
+
+	if err := removeHostFile(filepath.Join(sandboxDirectory, "..", "outside", "target.txt")); !os.IsPermission(err) {
+		t.Errorf("removing outside the sandbox: %v", err)
+	}
+}
+
+func TestRenameHostFileRenamesTheLink(t *testing.T) {
+	sandboxDirectory, outsideDirectory := newTestDirectories(t)
+	setTestFileSystemPolicy(t, &fileSystemPolicy{root: sandboxDirectory})
+
+	targetPath := filepath.Join(outsideDirectory, "target.txt")
+	oldPath := filepath.Join(sandboxDirectory, "old")
+	newPath := filepath.Join(sandboxDirectory, "new")
+	writeTestFile(t, targetPath)
+
+	if err := os.Symlink(targetPath, oldPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := renameHostFile(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := os.Readlink(newPath)
+
+	if err != nil || target != targetPath {
+		t.Errorf("renamed link points to %q, %v", target, err)
+	}
+
+	if _, err := os.Stat(targetPath); err != nil {
+		t.Errorf("target moved: %v", err)
+	}
+
+	if err := renameHostFile(newPath, filepath.Join(outsideDirectory, "moved")); !os.IsPermission(err) {
+		t.Errorf("renaming out of the sandbox: %v", err)
+	}
+}
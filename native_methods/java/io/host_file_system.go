@@ -0,0 +1,127 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Every host file system access of the java.io natives goes through these functions,
+// which deny what the sandbox options forbid with a permission error
+
+func openHostFile(path string, flag int) (*os.File, error) {
+	canonicalPath, err := checkFileAccess(path, isWriteFlag(flag))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(canonicalPath, flag, 0666)
+}
+
+func statHostFile(path string) (os.FileInfo, error) {
+	canonicalPath, err := checkFileAccess(path, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Stat(canonicalPath)
+}
+
+func listHostDirectory(path string) ([]string, error) {
+	canonicalPath, err := checkFileAccess(path, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfos, err := ioutil.ReadDir(canonicalPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(fileInfos))
+
+	for i, fileInfo := range fileInfos {
+		names[i] = fileInfo.Name()
+	}
+
+	return names, nil
+}
+
+func removeHostFile(path string) error {
+	entryPath, err := checkEntryAccess(path, true)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(entryPath)
+}
+
+// Fails with an error os.IsExist recognizes if the file exists, even as a dangling symbolic link
+func createHostFileExclusively(path string) (*os.File, error) {
+	entryPath, err := checkEntryAccess(path, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(entryPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+}
+
+func renameHostFile(oldPath, newPath string) error {
+	oldEntryPath, err := checkEntryAccess(oldPath, true)
+
+	if err != nil {
+		return err
+	}
+
+	newEntryPath, err := checkEntryAccess(newPath, true)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(oldEntryPath, newEntryPath)
+}
+
+// Throws FileNotFoundException with the message the JDK uses, e.g. "a.txt (No such file or directory)"
+func openFileOrThrow(path string, flag int) *os.File {
+	file, err := openHostFile(path, flag)
+
+	if err != nil {
+		panic(heap.NewJavaException("java/io/FileNotFoundException", path+" ("+getErrorReason(err)+")"))
+	}
+
+	fileInfo, err := file.Stat()
+
+	if err == nil && fileInfo.IsDir() {
+		file.Close()
+
+		panic(heap.NewJavaException("java/io/FileNotFoundException", path+" (Is a directory)"))
+	}
+
+	return file
+}
+
+// no such file or directory -> No such file or directory
+func getErrorReason(err error) string {
+	pathError, ok := err.(*os.PathError)
+
+	if ok {
+		err = pathError.Err
+	}
+
+	reason := err.Error()
+
+	if reason == "" {
+		return reason
+	}
+
+	return strings.ToUpper(reason[:1]) + reason[1:]
+}
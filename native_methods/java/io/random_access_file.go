@@ -0,0 +1,126 @@
+package io
+
+import (
+	"io"
+	"os"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaIORandomAccessFile = "java/io/RandomAccessFile"
+
+// Values of the mode argument of RandomAccessFile.open0
+const (
+	randomAccessFileReadOnly  = 1
+	randomAccessFileReadWrite = 2
+	randomAccessFileSync      = 4
+	randomAccessFileDataSync  = 8
+)
+
+func init() {
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "initIDs", "()V", initIDs)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "open0", "(Ljava/lang/String;I)V", openRandomAccessFile)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "read0", "()I", read0)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "readBytes", "([BII)I", readBytes)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "write0", "(I)V", writeRandomAccessFile)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "writeBytes", "([BII)V", writeBytesRandomAccessFile)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "getFilePointer", "()J", getFilePointer)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "seek0", "(J)V", seek0)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "length", "()J", getRandomAccessFileLength)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "length0", "()J", getRandomAccessFileLength)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "setLength", "(J)V", setRandomAccessFileLength)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "setLength0", "(J)V", setRandomAccessFileLength)
+	native_methods.RegisterNativeMethod(javaIORandomAccessFile, "close0", "()V", closeStream)
+}
+
+func openRandomAccessFile(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	path := heap.ConvertJavaStringToGoString(localVariables.GetReferenceValue(1))
+	mode := localVariables.GetIntegerValue(2)
+	flag := os.O_RDONLY
+
+	if mode&randomAccessFileReadWrite != 0 {
+		flag = os.O_RDWR | os.O_CREATE
+
+		if mode&(randomAccessFileSync|randomAccessFileDataSync) != 0 {
+			flag |= os.O_SYNC
+		}
+	}
+
+	setFile(localVariables.GetThis(), openFileOrThrow(path, flag))
+}
+
+func writeRandomAccessFile(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+	value := localVariables.GetIntegerValue(1)
+
+	if _, err := file.Write([]byte{byte(value)}); err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+}
+
+func writeBytesRandomAccessFile(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+
+	writeBytesToFile(file, localVariables.GetReferenceValue(1), localVariables.GetIntegerValue(2), localVariables.GetIntegerValue(3))
+}
+
+func getFilePointer(frame *runtime_data_area.Frame) {
+	file := getFile(frame.GetLocalVariables().GetThis())
+	position, err := file.Seek(0, io.SeekCurrent)
+
+	if err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+
+	frame.GetOperandStack().PushLongValue(position)
+}
+
+func seek0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+	position := localVariables.GetLongValue(1)
+
+	if position < 0 {
+		panic(heap.NewJavaException("java/io/IOException", "Negative seek offset"))
+	}
+
+	if _, err := file.Seek(position, io.SeekStart); err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+}
+
+func getRandomAccessFileLength(frame *runtime_data_area.Frame) {
+	file := getFile(frame.GetLocalVariables().GetThis())
+	fileInfo, err := file.Stat()
+
+	if err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+
+	frame.GetOperandStack().PushLongValue(fileInfo.Size())
+}
+
+// The file pointer moves back to the new end when the file is truncated before it
+func setRandomAccessFileLength(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	file := getFile(localVariables.GetThis())
+	length := localVariables.GetLongValue(1)
+	position, err := file.Seek(0, io.SeekCurrent)
+
+	if err == nil {
+		err = file.Truncate(length)
+	}
+
+	if err == nil && position > length {
+		_, err = file.Seek(length, io.SeekStart)
+	}
+
+	if err != nil {
+		panic(heap.NewJavaException("java/io/IOException", err.Error()))
+	}
+}
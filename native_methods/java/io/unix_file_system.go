@@ -0,0 +1,169 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaIOUnixFileSystem = "java/io/UnixFileSystem"
+
+// Bits of FileSystem.getBooleanAttributes, BA_HIDDEN is computed in Java from the name
+const (
+	booleanAttributeExists    = 0x01
+	booleanAttributeRegular   = 0x02
+	booleanAttributeDirectory = 0x04
+)
+
+func init() {
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "initIDs", "()V", initIDs)
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "getBooleanAttributes0", "(Ljava/io/File;)I", getBooleanAttributes0)
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "list", "(Ljava/io/File;)[Ljava/lang/String;", list)
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "canonicalize0", "(Ljava/lang/String;)Ljava/lang/String;", canonicalize0)
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "createFileExclusively", "(Ljava/lang/String;)Z", createFileExclusively)
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "delete0", "(Ljava/io/File;)Z", delete0)
+	native_methods.RegisterNativeMethod(javaIOUnixFileSystem, "rename0", "(Ljava/io/File;Ljava/io/File;)Z", rename0)
+}
+
+func getBooleanAttributes0(frame *runtime_data_area.Frame) {
+	path := getFilePath(frame.GetLocalVariables().GetReferenceValue(1))
+	attributes := int32(0)
+	fileInfo, err := statHostFile(path)
+
+	if err == nil {
+		attributes |= booleanAttributeExists
+
+		if fileInfo.Mode().IsRegular() {
+			attributes |= booleanAttributeRegular
+		}
+
+		if fileInfo.IsDir() {
+			attributes |= booleanAttributeDirectory
+		}
+	}
+
+	frame.GetOperandStack().PushIntegerValue(attributes)
+}
+
+// Names of the directory entries, or null if the file is not a readable directory
+func list(frame *runtime_data_area.Frame) {
+	path := getFilePath(frame.GetLocalVariables().GetReferenceValue(1))
+	names, err := listHostDirectory(path)
+
+	if err != nil {
+		frame.GetOperandStack().PushReferenceValue(nil)
+
+		return
+	}
+
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	stringArray := classLoader.LoadClass("java/lang/String").GetArrayClass().NewArray(uint(len(names)))
+	javaNames := stringArray.GetReferenceArray()
+
+	for i, name := range names {
+		javaNames[i] = heap.ConvertGoStringToJavaString(classLoader, name)
+	}
+
+	frame.GetOperandStack().PushReferenceValue(stringArray)
+}
+
+func canonicalize0(frame *runtime_data_area.Frame) {
+	path := heap.ConvertJavaStringToGoString(frame.GetLocalVariables().GetReferenceValue(1))
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	canonicalPath, err := checkFileAccess(path, false)
+
+	// Links outside the sandbox are not followed
+	if err != nil {
+		canonicalPath = filepath.Clean(path)
+	}
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, canonicalPath))
+}
+
+// Resolves symbolic links of the longest existing prefix, the rest is only cleaned. A dangling
+// link is followed to where it points, the same as creating the file would
+func canonicalize(path string) string {
+	return resolveSymbolicLinks(path, maxSymbolicLinksCount)
+}
+
+// Linux gives up with ELOOP after as many
+const maxSymbolicLinksCount = 40
+
+func resolveSymbolicLinks(path string, linksCount int) string {
+	path = filepath.Clean(path)
+	resolvedPath, err := filepath.EvalSymlinks(path)
+
+	if err == nil {
+		return resolvedPath
+	}
+
+	parent := filepath.Dir(path)
+
+	if parent == path {
+		return path
+	}
+
+	resolvedParent := resolveSymbolicLinks(parent, linksCount)
+	resolvedPath = filepath.Join(resolvedParent, filepath.Base(path))
+	target, err := os.Readlink(resolvedPath)
+
+	if err != nil || linksCount == 0 {
+		return resolvedPath
+	}
+
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(resolvedParent, target)
+	}
+
+	return resolveSymbolicLinks(target, linksCount-1)
+}
+
+// False if the file already exists
+func createFileExclusively(frame *runtime_data_area.Frame) {
+	path := heap.ConvertJavaStringToGoString(frame.GetLocalVariables().GetReferenceValue(1))
+	file, err := createHostFileExclusively(path)
+
+	if err != nil {
+		if os.IsExist(err) {
+			frame.GetOperandStack().PushIntegerValue(0)
+
+			return
+		}
+
+		panic(heap.NewJavaException("java/io/IOException", getErrorReason(err)))
+	}
+
+	file.Close()
+
+	frame.GetOperandStack().PushIntegerValue(1)
+}
+
+func delete0(frame *runtime_data_area.Frame) {
+	path := getFilePath(frame.GetLocalVariables().GetReferenceValue(1))
+	isDeleted := int32(0)
+
+	if removeHostFile(path) == nil {
+		isDeleted = 1
+	}
+
+	frame.GetOperandStack().PushIntegerValue(isDeleted)
+}
+
+func rename0(frame *runtime_data_area.Frame) {
+	oldPath := getFilePath(frame.GetLocalVariables().GetReferenceValue(1))
+	newPath := getFilePath(frame.GetLocalVariables().GetReferenceValue(2))
+	isRenamed := int32(0)
+
+	if renameHostFile(oldPath, newPath) == nil {
+		isRenamed = 1
+	}
+
+	frame.GetOperandStack().PushIntegerValue(isRenamed)
+}
+
+func getFilePath(file *heap.Object) string {
+	return heap.ConvertJavaStringToGoString(file.GetReferenceValue("path", "Ljava/lang/String;"))
+}
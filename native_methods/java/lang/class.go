@@ -1,6 +1,9 @@
 package lang
 
 import (
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/native_methods"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
 	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
@@ -8,10 +11,47 @@ import (
 
 const javaLangClass = "java/lang/Class"
 
+// Access flags reflection reports, see JVM_RECOGNIZED_CLASS_MODIFIERS, JVM_RECOGNIZED_FIELD_MODIFIERS
+// and JVM_RECOGNIZED_METHOD_MODIFIERS
+const (
+	recognizedClassModifiers  = 0x7631
+	recognizedFieldModifiers  = 0x50DF
+	recognizedMethodModifiers = 0x1DFF
+)
+
 func init() {
 	native_methods.RegisterNativeMethod(javaLangClass, "getPrimitiveClass", "(Ljava/lang/String;)Ljava/lang/Class;", getPrimitiveClass)
 	native_methods.RegisterNativeMethod(javaLangClass, "getName0", "()Ljava/lang/String;", getName0)
 	native_methods.RegisterNativeMethod(javaLangClass, "desiredAssertionStatus0", "(Ljava/lang/Class;)Z", desiredAssertionStatus0)
+	native_methods.RegisterNativeMethod(javaLangClass, "forName0", "(Ljava/lang/String;ZLjava/lang/ClassLoader;Ljava/lang/Class;)Ljava/lang/Class;", forName0)
+	native_methods.RegisterNativeMethod(javaLangClass, "isInterface", "()Z", isInterface)
+	native_methods.RegisterNativeMethod(javaLangClass, "isPrimitive", "()Z", isPrimitive)
+	native_methods.RegisterNativeMethod(javaLangClass, "getDeclaredFields0", "(Z)[Ljava/lang/reflect/Field;", getDeclaredFields0)
+	native_methods.RegisterNativeMethod(javaLangClass, "getDeclaredMethods0", "(Z)[Ljava/lang/reflect/Method;", getDeclaredMethods0)
+	native_methods.RegisterNativeMethod(javaLangClass, "getDeclaredConstructors0", "(Z)[Ljava/lang/reflect/Constructor;", getDeclaredConstructors0)
+	native_methods.RegisterNativeMethod(javaLangClass, "isArray", "()Z", isArray)
+	native_methods.RegisterNativeMethod(javaLangClass, "isInstance", "(Ljava/lang/Object;)Z", isInstance)
+	native_methods.RegisterNativeMethod(javaLangClass, "isAssignableFrom", "(Ljava/lang/Class;)Z", isAssignableFrom)
+	native_methods.RegisterNativeMethod(javaLangClass, "getModifiers", "()I", getModifiers)
+	native_methods.RegisterNativeMethod(javaLangClass, "getSuperclass", "()Ljava/lang/Class;", getSuperclass)
+	native_methods.RegisterNativeMethod(javaLangClass, "getInterfaces0", "()[Ljava/lang/Class;", getInterfaces0)
+	native_methods.RegisterNativeMethod(javaLangClass, "getComponentType", "()Ljava/lang/Class;", getComponentType)
+	native_methods.RegisterNativeMethod(javaLangClass, "getRawAnnotations", "()[B", getRawAnnotations)
+	native_methods.RegisterNativeMethod(javaLangClass, "getRawTypeAnnotations", "()[B", getRawTypeAnnotations)
+	// Moved to jdk.internal.reflect in Java 9
+	native_methods.RegisterNativeMethod(javaLangClass, "getConstantPool", "()Lsun/reflect/ConstantPool;", getConstantPool)
+	native_methods.RegisterNativeMethod(javaLangClass, "getConstantPool", "()Ljdk/internal/reflect/ConstantPool;", getConstantPool)
+
+	forNameShimMethod := heap.GetForNameShimMethod()
+	native_methods.RegisterNativeMethod(forNameShimMethod.GetClass().GetName(), forNameShimMethod.GetName(), forNameShimMethod.GetDescriptor(), completeForName)
+}
+
+// The loaders of the JDK find their classes on the class path, the same as the VM's own loader
+var systemClassLoaderClassNames = map[string]bool{
+	"sun/misc/Launcher$ExtClassLoader":                     true,
+	"sun/misc/Launcher$AppClassLoader":                     true,
+	"jdk/internal/loader/ClassLoaders$PlatformClassLoader": true,
+	"jdk/internal/loader/ClassLoaders$AppClassLoader":      true,
 }
 
 func getPrimitiveClass(frame *runtime_data_area.Frame) {
@@ -37,6 +77,80 @@ func desiredAssertionStatus0(frame *runtime_data_area.Frame) {
 	frame.GetOperandStack().PushBooleanValue(false)
 }
 
+// Classes of the bootstrap and system loaders are loaded by the VM's own loader, other loaders
+// are asked with loadClass. A frame for the class initializer goes on top when initialization
+// is asked for, then forName0 runs again
+func forName0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	javaName := localVariables.GetReferenceValue(0)
+	shouldInitialize := localVariables.GetIntegerValue(1) != 0
+	javaClassLoader := localVariables.GetReferenceValue(2)
+
+	if javaName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	name := heap.ConvertJavaStringToGoString(javaName)
+
+	// Class.forName takes binary names, java.lang.String rather than java/lang/String
+	if strings.Contains(name, "/") {
+		panic(heap.NewJavaException("java/lang/ClassNotFoundException", name))
+	}
+
+	if javaClassLoader != nil && !systemClassLoaderClassNames[javaClassLoader.GetClass().GetName()] {
+		loadClassWithClassLoader(frame, javaClassLoader, javaName)
+
+		return
+	}
+
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	class := classLoader.FindClass(strings.Replace(name, ".", "/", -1))
+
+	if shouldInitialize && !class.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), class)
+
+		return
+	}
+
+	frame.GetOperandStack().PushReferenceValue(class.GetJavaClass())
+}
+
+// The forName shim goes below the loadClass call and returns its result as the result of forName0
+func loadClassWithClassLoader(frame *runtime_data_area.Frame, javaClassLoader, javaName *heap.Object) {
+	loadClassMethod := heap.LookupMethodInClass(javaClassLoader.GetClass(), "loadClass", "(Ljava/lang/String;)Ljava/lang/Class;")
+	thread := frame.GetThread()
+	shimFrame := thread.NewFrame(heap.GetForNameShimMethod())
+	shimFrame.GetLocalVariables().SetIntegerValue(0, frame.GetLocalVariables().GetIntegerValue(1))
+	shimFrame.GetLocalVariables().SetReferenceValue(1, javaName)
+	shimFrame.GetOperandStack().PushReferenceValue(javaClassLoader)
+	shimFrame.GetOperandStack().PushReferenceValue(javaName)
+
+	thread.PushFrame(shimFrame)
+	base_instructions.InvokeMethod(shimFrame, loadClassMethod)
+}
+
+// Runs in the forName shim once loadClass returned, the class stays on the operand stack
+// while its initializer runs
+func completeForName(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	shouldInitialize := localVariables.GetIntegerValue(0) != 0
+	javaClass := frame.GetOperandStack().GetReferenceValueBelowTop(0)
+
+	if javaClass == nil {
+		name := heap.ConvertJavaStringToGoString(localVariables.GetReferenceValue(1))
+
+		panic(heap.NewJavaException("java/lang/ClassNotFoundException", name))
+	}
+
+	class := javaClass.GetExtraData().(*heap.Class)
+
+	if shouldInitialize && !class.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), class)
+	}
+}
+
 func isInterface(frame *runtime_data_area.Frame) {
 	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
 
@@ -48,3 +162,257 @@ func isPrimitive(frame *runtime_data_area.Frame) {
 
 	frame.GetOperandStack().PushBooleanValue(class.IsPrimitive())
 }
+
+func isArray(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+
+	frame.GetOperandStack().PushBooleanValue(class.IsArray())
+}
+
+func isInstance(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	class := localVariables.GetThis().GetExtraData().(*heap.Class)
+	object := localVariables.GetReferenceValue(1)
+
+	frame.GetOperandStack().PushBooleanValue(object != nil && object.IsInstanceOf(class))
+}
+
+func isAssignableFrom(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	class := localVariables.GetThis().GetExtraData().(*heap.Class)
+	otherClassObject := localVariables.GetReferenceValue(1)
+
+	if otherClassObject == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	frame.GetOperandStack().PushBooleanValue(class.IsAssignableFrom(otherClassObject.GetExtraData().(*heap.Class)))
+}
+
+func getModifiers(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+
+	frame.GetOperandStack().PushIntegerValue(int32(getClassModifiers(class)))
+}
+
+// Arrays are as visible as their element types, see JVM_GetClassModifiers
+func getClassModifiers(class *heap.Class) uint16 {
+	if class.IsArray() {
+		elementModifiers := getClassModifiers(class.GetArrayElementClass())
+
+		return elementModifiers&(heap.ACC_PUBLIC|heap.ACC_PRIVATE|heap.ACC_PROTECTED) | heap.ACC_FINAL | heap.ACC_ABSTRACT
+	}
+
+	if class.IsPrimitive() {
+		return heap.ACC_PUBLIC | heap.ACC_FINAL | heap.ACC_ABSTRACT
+	}
+
+	return class.GetModifiers() & recognizedClassModifiers &^ heap.ACC_SUPER
+}
+
+// Interfaces and primitive types have no super class
+func getSuperclass(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+	superClass := class.GetSuperClass()
+
+	if class.IsInterface() || superClass == nil {
+		frame.GetOperandStack().PushReferenceValue(nil)
+
+		return
+	}
+
+	frame.GetOperandStack().PushReferenceValue(superClass.GetJavaClass())
+}
+
+func getInterfaces0(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+
+	frame.GetOperandStack().PushReferenceValue(newClassArray(classLoader, class.GetInterfaces()))
+}
+
+func getComponentType(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+
+	if !class.IsArray() {
+		frame.GetOperandStack().PushReferenceValue(nil)
+
+		return
+	}
+
+	frame.GetOperandStack().PushReferenceValue(class.GetArrayElementClass().GetJavaClass())
+}
+
+// The RuntimeVisibleAnnotations attribute as is, sun.reflect.annotation.AnnotationParser parses it
+func getRawAnnotations(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoBytesToJavaBytes(classLoader, class.GetAnnotations()))
+}
+
+func getRawTypeAnnotations(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoBytesToJavaBytes(classLoader, class.GetTypeAnnotations()))
+}
+
+// The constant pool the annotation parser reads is the class itself, the descriptor names the
+// ConstantPool class of the running JDK. Arrays and primitive types have no constant pool
+func getConstantPool(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetThis().GetExtraData().(*heap.Class)
+
+	if class.IsArray() || class.IsPrimitive() {
+		frame.GetOperandStack().PushReferenceValue(nil)
+
+		return
+	}
+
+	descriptor := frame.GetMethod().GetDescriptor()
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	constantPoolClass := classLoader.LoadClass(descriptor[3 : len(descriptor)-1])
+
+	if !constantPoolClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), constantPoolClass)
+
+		return
+	}
+
+	constantPoolObject := constantPoolClass.NewObject()
+	constantPoolObject.SetReferenceValue("constantPoolOop", "Ljava/lang/Object;", class.GetJavaClass())
+
+	frame.GetOperandStack().PushReferenceValue(constantPoolObject)
+}
+
+// The slot of a Field is its index in the fields of its class
+func getDeclaredFields0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	class := localVariables.GetThis().GetExtraData().(*heap.Class)
+	isPublicOnly := localVariables.GetIntegerValue(1) != 0
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	fieldClass := classLoader.LoadClass("java/lang/reflect/Field")
+
+	if !fieldClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), fieldClass)
+
+		return
+	}
+
+	fieldObjects := []*heap.Object{}
+
+	for slot, field := range class.GetFields() {
+		if isPublicOnly && !field.IsPublic() {
+			continue
+		}
+
+		fieldObject := fieldClass.NewObject()
+		fieldObject.SetReferenceValue("clazz", "Ljava/lang/Class;", class.GetJavaClass())
+		fieldObject.SetIntegerValue("slot", "I", int32(slot))
+		fieldObject.SetReferenceValue("name", "Ljava/lang/String;", heap.ConvertGoStringToJavaString(classLoader, field.GetName()))
+		fieldObject.SetReferenceValue("type", "Ljava/lang/Class;", field.GetType().GetJavaClass())
+		fieldObject.SetIntegerValue("modifiers", "I", int32(field.GetAccessFlags()&recognizedFieldModifiers))
+		fieldObject.SetReferenceValue("annotations", "[B", heap.ConvertGoBytesToJavaBytes(classLoader, field.GetAnnotations()))
+
+		fieldObjects = append(fieldObjects, fieldObject)
+	}
+
+	frame.GetOperandStack().PushReferenceValue(newObjectArray(fieldClass, fieldObjects))
+}
+
+// The slot of a Method is its index in the methods of its class
+func getDeclaredMethods0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	class := localVariables.GetThis().GetExtraData().(*heap.Class)
+	isPublicOnly := localVariables.GetIntegerValue(1) != 0
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	methodClass := classLoader.LoadClass("java/lang/reflect/Method")
+
+	if !methodClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), methodClass)
+
+		return
+	}
+
+	methodObjects := []*heap.Object{}
+
+	for slot, method := range class.GetMethods() {
+		if method.IsConstructor() || method.IsClassInitializer() || (isPublicOnly && !method.IsPublic()) {
+			continue
+		}
+
+		methodObject := methodClass.NewObject()
+		setExecutableFields(methodObject, method, slot)
+		methodObject.SetReferenceValue("name", "Ljava/lang/String;", heap.ConvertGoStringToJavaString(classLoader, method.GetName()))
+		methodObject.SetReferenceValue("returnType", "Ljava/lang/Class;", method.GetReturnType().GetJavaClass())
+		methodObject.SetReferenceValue("annotationDefault", "[B", heap.ConvertGoBytesToJavaBytes(classLoader, method.GetAnnotationDefault()))
+
+		methodObjects = append(methodObjects, methodObject)
+	}
+
+	frame.GetOperandStack().PushReferenceValue(newObjectArray(methodClass, methodObjects))
+}
+
+func getDeclaredConstructors0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	class := localVariables.GetThis().GetExtraData().(*heap.Class)
+	isPublicOnly := localVariables.GetIntegerValue(1) != 0
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	constructorClass := classLoader.LoadClass("java/lang/reflect/Constructor")
+
+	if !constructorClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), constructorClass)
+
+		return
+	}
+
+	constructorObjects := []*heap.Object{}
+
+	for slot, method := range class.GetMethods() {
+		if !method.IsConstructor() || (isPublicOnly && !method.IsPublic()) {
+			continue
+		}
+
+		constructorObject := constructorClass.NewObject()
+		setExecutableFields(constructorObject, method, slot)
+
+		constructorObjects = append(constructorObjects, constructorObject)
+	}
+
+	frame.GetOperandStack().PushReferenceValue(newObjectArray(constructorClass, constructorObjects))
+}
+
+// Fields Method and Constructor have in common
+func setExecutableFields(executableObject *heap.Object, method *heap.Method, slot int) {
+	classLoader := method.GetClass().GetClassLoader()
+
+	executableObject.SetReferenceValue("clazz", "Ljava/lang/Class;", method.GetClass().GetJavaClass())
+	executableObject.SetIntegerValue("slot", "I", int32(slot))
+	executableObject.SetReferenceValue("parameterTypes", "[Ljava/lang/Class;", newClassArray(classLoader, method.GetParameterTypes()))
+	executableObject.SetReferenceValue("exceptionTypes", "[Ljava/lang/Class;", newClassArray(classLoader, method.GetExceptionTypes()))
+	executableObject.SetIntegerValue("modifiers", "I", int32(method.GetAccessFlags()&recognizedMethodModifiers))
+	executableObject.SetReferenceValue("annotations", "[B", heap.ConvertGoBytesToJavaBytes(classLoader, method.GetAnnotations()))
+	executableObject.SetReferenceValue("parameterAnnotations", "[B", heap.ConvertGoBytesToJavaBytes(classLoader, method.GetParameterAnnotations()))
+}
+
+func newClassArray(classLoader *heap.ClassLoader, classes []*heap.Class) *heap.Object {
+	classObjects := make([]*heap.Object, len(classes))
+
+	for i, class := range classes {
+		classObjects[i] = class.GetJavaClass()
+	}
+
+	return newObjectArray(classLoader.LoadClass("java/lang/Class"), classObjects)
+}
+
+func newObjectArray(elementClass *heap.Class, objects []*heap.Object) *heap.Object {
+	array := elementClass.GetArrayClass().NewArray(uint(len(objects)))
+
+	copy(array.GetReferenceArray(), objects)
+
+	return array
+}
@@ -0,0 +1,44 @@
+package lang
+
+import (
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaLangClassLoader = "java/lang/ClassLoader"
+const javaLangClassLoaderNativeLibrary = "java/lang/ClassLoader$NativeLibrary"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaLangClassLoader, "findBuiltinLib", "(Ljava/lang/String;)Ljava/lang/String;", findBuiltinLib)
+	native_methods.RegisterNativeMethod(javaLangClassLoaderNativeLibrary, "load", "(Ljava/lang/String;)V", loadNativeLibrary)
+	native_methods.RegisterNativeMethod(javaLangClassLoaderNativeLibrary, "load", "(Ljava/lang/String;Z)V", loadNativeLibrary)
+	native_methods.RegisterNativeMethod(javaLangClassLoaderNativeLibrary, "load0", "(Ljava/lang/String;Z)Z", loadNativeLibrary0)
+}
+
+// Every JDK library is built into the VM, libzip.so -> zip
+func findBuiltinLib(frame *runtime_data_area.Frame) {
+	fileName := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if fileName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	libraryName := strings.TrimSuffix(strings.TrimPrefix(heap.ConvertJavaStringToGoString(fileName), "lib"), ".so")
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, libraryName))
+}
+
+// Nothing to load, the natives are registered when the VM starts
+func loadNativeLibrary(frame *runtime_data_area.Frame) {
+	this := frame.GetLocalVariables().GetThis()
+
+	this.SetIntegerValue("loaded", "Z", 1)
+}
+
+func loadNativeLibrary0(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(1)
+}
@@ -0,0 +1,412 @@
+package invoke
+
+import (
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+var wrapperClassNames = map[string]string{
+	"Z": "java/lang/Boolean",
+	"B": "java/lang/Byte",
+	"C": "java/lang/Character",
+	"S": "java/lang/Short",
+	"I": "java/lang/Integer",
+	"J": "java/lang/Long",
+	"F": "java/lang/Float",
+	"D": "java/lang/Double",
+}
+
+// Widening primitive conversions, JLS 5.1.2
+var wideningConversions = map[string]string{
+	"B": "SIJFD",
+	"S": "IJFD",
+	"C": "IJFD",
+	"I": "JFD",
+	"J": "FD",
+	"F": "D",
+}
+
+// The parameter types and return type of the MethodType of a method handle
+func getMethodType(methodHandle *heap.Object) ([]*heap.Class, *heap.Class) {
+	methodType := methodHandle.GetReferenceValue("type", "Ljava/lang/invoke/MethodType;")
+	returnType := methodType.GetReferenceValue("rtype", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	parameterTypeObjects := methodType.GetReferenceValue("ptypes", "[Ljava/lang/Class;").GetReferenceArray()
+	parameterTypes := make([]*heap.Class, len(parameterTypeObjects))
+
+	for i, parameterTypeObject := range parameterTypeObjects {
+		parameterTypes[i] = parameterTypeObject.GetExtraData().(*heap.Class)
+	}
+
+	return parameterTypes, returnType
+}
+
+// The descriptor of a signature polymorphic method is the type of its call site
+func isSameMethodType(method *heap.Method, parameterTypes []*heap.Class, returnType *heap.Class) bool {
+	callSiteParameterTypes := method.GetParameterTypes()
+
+	if len(callSiteParameterTypes) != len(parameterTypes) || method.GetReturnType() != returnType {
+		return false
+	}
+
+	for i, parameterType := range parameterTypes {
+		if callSiteParameterTypes[i] != parameterType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Like MethodType.toString, e.g. (int,java.lang.String)void
+func formatMethodType(parameterTypes []*heap.Class, returnType *heap.Class) string {
+	parameterTypeNames := make([]string, len(parameterTypes))
+
+	for i, parameterType := range parameterTypes {
+		parameterTypeNames[i] = parameterType.GetJavaName()
+	}
+
+	return "(" + strings.Join(parameterTypeNames, ",") + ")" + returnType.GetJavaName()
+}
+
+// The asType shim goes below the method handle with the arguments converted to its type
+func invokeAsType(frame *runtime_data_area.Frame, methodHandle *heap.Object, parameterTypes []*heap.Class, returnType *heap.Class) {
+	method := frame.GetMethod()
+	callSiteParameterTypes := method.GetParameterTypes()
+	callSiteReturnType := method.GetReturnType()
+
+	if !canConvertMethodType(callSiteParameterTypes, callSiteReturnType, parameterTypes, returnType) {
+		panic(heap.NewJavaException("java/lang/invoke/WrongMethodTypeException",
+			"cannot convert MethodHandle"+formatMethodType(parameterTypes, returnType)+" to "+formatMethodType(callSiteParameterTypes, callSiteReturnType)))
+	}
+
+	// Boxed arguments need their wrapper classes initialized
+	for i, parameterType := range parameterTypes {
+		if callSiteParameterTypes[i].IsPrimitive() && !parameterType.IsPrimitive() && !initializeWrapperClass(frame, callSiteParameterTypes[i]) {
+			return
+		}
+	}
+
+	thread := frame.GetThread()
+	shimFrame := thread.NewFrame(heap.GetAsTypeShimMethod())
+	localVariables := frame.GetLocalVariables()
+	shimLocalVariables := shimFrame.GetLocalVariables()
+	shimLocalVariables.SetReferenceValue(0, methodHandle)
+	index := uint(1)
+	shimIndex := uint(1)
+
+	for i, parameterType := range parameterTypes {
+		callSiteParameterType := callSiteParameterTypes[i]
+		value := convertValue(getValue(localVariables, index, callSiteParameterType), callSiteParameterType, parameterType)
+		setValue(shimLocalVariables, shimIndex, parameterType, value)
+		index += getSlotsCount(callSiteParameterType)
+		shimIndex += getSlotsCount(parameterType)
+	}
+
+	thread.PushFrame(shimFrame)
+}
+
+// Runs in the asType shim, first to call the method handle, then once it returned to convert its result
+func completeAsType(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	methodHandle := localVariables.GetReferenceValue(0)
+	parameterTypes, returnType := getMethodType(methodHandle)
+
+	if frame.GetNextPC() != heap.AsTypeShimReturnPC+1 {
+		argumentsCount := uint(1)
+
+		for _, parameterType := range parameterTypes {
+			argumentsCount += getSlotsCount(parameterType)
+		}
+
+		invokeMethodHandle(frame, localVariables[:argumentsCount])
+
+		return
+	}
+
+	callerFrame := frame.GetLowerFrame()
+	callSiteReturnType := callerFrame.GetMethod().GetReturnType()
+
+	if returnType.IsPrimitive() && !callSiteReturnType.IsPrimitive() && !initializeWrapperClass(frame, returnType) {
+		return
+	}
+
+	if returnType.GetName() == "void" {
+		if callSiteReturnType.GetName() != "void" {
+			pushValue(callerFrame.GetOperandStack(), callSiteReturnType, getZeroValue(callSiteReturnType))
+		}
+
+		return
+	}
+
+	value := popValue(frame.GetOperandStack(), returnType)
+
+	if callSiteReturnType.GetName() != "void" {
+		pushValue(callerFrame.GetOperandStack(), callSiteReturnType, convertValue(value, returnType, callSiteReturnType))
+	}
+}
+
+// The conversions MethodHandle.asType allows, the result is dropped or made up when either side is void
+func canConvertMethodType(callSiteParameterTypes []*heap.Class, callSiteReturnType *heap.Class, parameterTypes []*heap.Class, returnType *heap.Class) bool {
+	if len(callSiteParameterTypes) != len(parameterTypes) {
+		return false
+	}
+
+	for i, parameterType := range parameterTypes {
+		if !canConvert(callSiteParameterTypes[i], parameterType) {
+			return false
+		}
+	}
+
+	return returnType.GetName() == "void" || callSiteReturnType.GetName() == "void" || canConvert(returnType, callSiteReturnType)
+}
+
+func canConvert(sourceType, targetType *heap.Class) bool {
+	if sourceType == targetType {
+		return true
+	}
+
+	if sourceType.GetName() == "void" || targetType.GetName() == "void" {
+		return false
+	}
+
+	if sourceType.IsPrimitive() && targetType.IsPrimitive() {
+		return isWideningConversion(sourceType.GetDescriptor(), targetType.GetDescriptor())
+	}
+
+	if sourceType.IsPrimitive() {
+		return targetType.IsAssignableFrom(getWrapperClass(sourceType))
+	}
+
+	if targetType.IsPrimitive() {
+		primitiveType, ok := getUnboxedType(sourceType)
+
+		if ok {
+			return isWideningConversion(primitiveType, targetType.GetDescriptor())
+		}
+
+		return sourceType.IsAssignableFrom(getWrapperClass(targetType))
+	}
+
+	// References are cast when the value is converted
+	return true
+}
+
+func isWideningConversion(sourceType, targetType string) bool {
+	return sourceType == targetType || strings.Contains(wideningConversions[sourceType], targetType)
+}
+
+func getWrapperClass(primitiveType *heap.Class) *heap.Class {
+	return primitiveType.GetClassLoader().LoadClass(wrapperClassNames[primitiveType.GetDescriptor()])
+}
+
+// The descriptor of the primitive type of a wrapper class
+func getUnboxedType(class *heap.Class) (string, bool) {
+	for primitiveType, wrapperClassName := range wrapperClassNames {
+		if wrapperClassName == class.GetName() {
+			return primitiveType, true
+		}
+	}
+
+	return "", false
+}
+
+// Returns false when a frame for the initializer was pushed, the native then runs again
+func initializeWrapperClass(frame *runtime_data_area.Frame, primitiveType *heap.Class) bool {
+	wrapperClass := getWrapperClass(primitiveType)
+
+	if wrapperClass.IsInitializationStarted() {
+		return true
+	}
+
+	frame.RevertNextPC()
+	base_instructions.InitializeClass(frame.GetThread(), wrapperClass)
+
+	return false
+}
+
+// Values are int32, int64, float32, float64 or *heap.Object, as they are stored in slots
+func convertValue(value interface{}, sourceType, targetType *heap.Class) interface{} {
+	if sourceType == targetType {
+		return value
+	}
+
+	if sourceType.IsPrimitive() && targetType.IsPrimitive() {
+		return widenValue(value, targetType.GetDescriptor())
+	}
+
+	if sourceType.IsPrimitive() {
+		return boxValue(value, sourceType)
+	}
+
+	object := value.(*heap.Object)
+
+	if targetType.IsPrimitive() {
+		if object == nil {
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+		}
+
+		primitiveType, ok := getUnboxedType(object.GetClass())
+
+		if !ok || !isWideningConversion(primitiveType, targetType.GetDescriptor()) {
+			panicClassCastException(object, getWrapperClass(targetType))
+		}
+
+		return widenValue(unboxValue(object, primitiveType), targetType.GetDescriptor())
+	}
+
+	if object != nil && !object.IsInstanceOf(targetType) {
+		panicClassCastException(object, targetType)
+	}
+
+	return object
+}
+
+func panicClassCastException(object *heap.Object, class *heap.Class) {
+	panic(heap.NewJavaException("java/lang/ClassCastException", object.GetClass().GetJavaName()+" cannot be cast to "+class.GetJavaName()))
+}
+
+func widenValue(value interface{}, targetType string) interface{} {
+	switch value.(type) {
+	case int32:
+		switch targetType {
+		case "J":
+			return int64(value.(int32))
+		case "F":
+			return float32(value.(int32))
+		case "D":
+			return float64(value.(int32))
+		}
+	case int64:
+		switch targetType {
+		case "F":
+			return float32(value.(int64))
+		case "D":
+			return float64(value.(int64))
+		}
+	case float32:
+		if targetType == "D" {
+			return float64(value.(float32))
+		}
+	}
+
+	return value
+}
+
+// A new wrapper object, like HotSpot boxes values without going through valueOf
+func boxValue(value interface{}, primitiveType *heap.Class) *heap.Object {
+	descriptor := primitiveType.GetDescriptor()
+	wrapperObject := getWrapperClass(primitiveType).NewObject()
+
+	switch value.(type) {
+	case int64:
+		wrapperObject.SetLongValue("value", descriptor, value.(int64))
+	case float32:
+		wrapperObject.SetFloatValue("value", descriptor, value.(float32))
+	case float64:
+		wrapperObject.SetDoubleValue("value", descriptor, value.(float64))
+	default:
+		wrapperObject.SetIntegerValue("value", descriptor, value.(int32))
+	}
+
+	return wrapperObject
+}
+
+func unboxValue(wrapperObject *heap.Object, primitiveType string) interface{} {
+	switch primitiveType {
+	case "J":
+		return wrapperObject.GetLongValue("value", primitiveType)
+	case "F":
+		return wrapperObject.GetFloatValue("value", primitiveType)
+	case "D":
+		return wrapperObject.GetDoubleValue("value", primitiveType)
+	default:
+		return wrapperObject.GetIntegerValue("value", primitiveType)
+	}
+}
+
+func getZeroValue(class *heap.Class) interface{} {
+	switch class.GetDescriptor()[0] {
+	case 'J':
+		return int64(0)
+	case 'F':
+		return float32(0)
+	case 'D':
+		return float64(0)
+	case 'L', '[':
+		return (*heap.Object)(nil)
+	default:
+		return int32(0)
+	}
+}
+
+func getSlotsCount(class *heap.Class) uint {
+	switch class.GetDescriptor()[0] {
+	case 'J', 'D':
+		return 2
+	default:
+		return 1
+	}
+}
+
+func getValue(localVariables runtime_data_area.LocalVariables, index uint, class *heap.Class) interface{} {
+	switch class.GetDescriptor()[0] {
+	case 'J':
+		return localVariables.GetLongValue(index)
+	case 'F':
+		return localVariables.GetFloatValue(index)
+	case 'D':
+		return localVariables.GetDoubleValue(index)
+	case 'L', '[':
+		return localVariables.GetReferenceValue(index)
+	default:
+		return localVariables.GetIntegerValue(index)
+	}
+}
+
+func setValue(localVariables runtime_data_area.LocalVariables, index uint, class *heap.Class, value interface{}) {
+	switch class.GetDescriptor()[0] {
+	case 'J':
+		localVariables.SetLongValue(index, value.(int64))
+	case 'F':
+		localVariables.SetFloatValue(index, value.(float32))
+	case 'D':
+		localVariables.SetDoubleValue(index, value.(float64))
+	case 'L', '[':
+		localVariables.SetReferenceValue(index, value.(*heap.Object))
+	default:
+		localVariables.SetIntegerValue(index, value.(int32))
+	}
+}
+
+func popValue(operandStack *runtime_data_area.OperandStack, class *heap.Class) interface{} {
+	switch class.GetDescriptor()[0] {
+	case 'J':
+		return operandStack.PopLongValue()
+	case 'F':
+		return operandStack.PopFloatValue()
+	case 'D':
+		return operandStack.PopDoubleValue()
+	case 'L', '[':
+		return operandStack.PopReferenceValue()
+	default:
+		return operandStack.PopIntegerValue()
+	}
+}
+
+func pushValue(operandStack *runtime_data_area.OperandStack, class *heap.Class, value interface{}) {
+	switch class.GetDescriptor()[0] {
+	case 'J':
+		operandStack.PushLongValue(value.(int64))
+	case 'F':
+		operandStack.PushFloatValue(value.(float32))
+	case 'D':
+		operandStack.PushDoubleValue(value.(float64))
+	case 'L', '[':
+		operandStack.PushReferenceValue(value.(*heap.Object))
+	default:
+		operandStack.PushIntegerValue(value.(int32))
+	}
+}
@@ -0,0 +1,228 @@
+package invoke
+
+import (
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaLangInvokeMethodHandle = "java/lang/invoke/MethodHandle"
+
+// Declared descriptor of every signature polymorphic method
+const signaturePolymorphicDescriptor = "([Ljava/lang/Object;)Ljava/lang/Object;"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "invokeExact", signaturePolymorphicDescriptor, invokeExact)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "invoke", signaturePolymorphicDescriptor, invoke)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "invokeBasic", signaturePolymorphicDescriptor, invokeBasic)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "linkToStatic", signaturePolymorphicDescriptor, linkTo)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "linkToSpecial", signaturePolymorphicDescriptor, linkTo)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "linkToVirtual", signaturePolymorphicDescriptor, linkTo)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandle, "linkToInterface", signaturePolymorphicDescriptor, linkTo)
+
+	asTypeShimMethod := heap.GetAsTypeShimMethod()
+	native_methods.RegisterNativeMethod(asTypeShimMethod.GetClass().GetName(), asTypeShimMethod.GetName(), asTypeShimMethod.GetDescriptor(), completeAsType)
+}
+
+// The type of the call site must be the type of the method handle
+func invokeExact(frame *runtime_data_area.Frame) {
+	methodHandle := getMethodHandle(frame)
+	method := frame.GetMethod()
+	parameterTypes, returnType := getMethodType(methodHandle)
+
+	if !isSameMethodType(method, parameterTypes, returnType) {
+		panic(heap.NewJavaException("java/lang/invoke/WrongMethodTypeException",
+			"expected "+formatMethodType(parameterTypes, returnType)+" but found "+formatMethodType(method.GetParameterTypes(), method.GetReturnType())))
+	}
+
+	invokeMethodHandle(frame, frame.GetLocalVariables()[:method.GetArgumentsCount()])
+}
+
+// Arguments and result are converted as by asType when the type of the call site differs
+func invoke(frame *runtime_data_area.Frame) {
+	methodHandle := getMethodHandle(frame)
+	method := frame.GetMethod()
+	parameterTypes, returnType := getMethodType(methodHandle)
+
+	if isSameMethodType(method, parameterTypes, returnType) {
+		invokeMethodHandle(frame, frame.GetLocalVariables()[:method.GetArgumentsCount()])
+
+		return
+	}
+
+	invokeAsType(frame, methodHandle, parameterTypes, returnType)
+}
+
+// The entry point of the lambda form takes the method handle followed by the arguments
+func invokeBasic(frame *runtime_data_area.Frame) {
+	getMethodHandle(frame)
+
+	invokeLambdaForm(frame, frame.GetLocalVariables()[:frame.GetMethod().GetArgumentsCount()])
+}
+
+func getMethodHandle(frame *runtime_data_area.Frame) *heap.Object {
+	methodHandle := frame.GetLocalVariables().GetThis()
+
+	if methodHandle == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	return methodHandle
+}
+
+// Direct method handles call their member straight away, others run their lambda form.
+// The method handle is the first of the arguments
+func invokeMethodHandle(frame *runtime_data_area.Frame, arguments runtime_data_area.LocalVariables) {
+	methodHandle := arguments.GetReferenceValue(0)
+
+	if !strings.HasPrefix(methodHandle.GetClass().GetName(), "java/lang/invoke/DirectMethodHandle") {
+		invokeLambdaForm(frame, arguments)
+
+		return
+	}
+
+	memberName := methodHandle.GetReferenceValue("member", "Ljava/lang/invoke/MemberName;")
+
+	invokeMemberName(frame, memberName, arguments[1:])
+}
+
+func invokeLambdaForm(frame *runtime_data_area.Frame, arguments runtime_data_area.LocalVariables) {
+	methodHandle := arguments.GetReferenceValue(0)
+	lambdaForm := methodHandle.GetReferenceValue("form", "Ljava/lang/invoke/LambdaForm;")
+	memberName := lambdaForm.GetReferenceValue("vmentry", "Ljava/lang/invoke/MemberName;")
+
+	invokeMemberName(frame, memberName, arguments)
+}
+
+// The trailing argument of linkTo* is the MemberName to call
+func linkTo(frame *runtime_data_area.Frame) {
+	argumentsCount := frame.GetMethod().GetArgumentsCount()
+	memberName := frame.GetLocalVariables().GetReferenceValue(argumentsCount - 1)
+
+	invokeMemberName(frame, memberName, frame.GetLocalVariables()[:argumentsCount-1])
+}
+
+func invokeMemberName(frame *runtime_data_area.Frame, memberName *heap.Object, arguments runtime_data_area.LocalVariables) {
+	if memberName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	referenceKind := (memberName.GetIntegerValue("flags", "I") >> memberNameReferenceKindShift) & memberNameReferenceKindMask
+
+	switch member := memberName.GetExtraData().(type) {
+	case *heap.Method:
+		invokeMember(frame, member, referenceKind, arguments)
+	case *heap.Field:
+		accessField(frame, member, referenceKind, arguments)
+	default:
+		panic(heap.NewJavaException("java/lang/InternalError", "MemberName is not resolved"))
+	}
+}
+
+func invokeMember(frame *runtime_data_area.Frame, method *heap.Method, referenceKind int32, arguments runtime_data_area.LocalVariables) {
+	thread := frame.GetThread()
+	class := method.GetClass()
+
+	if (referenceKind == heap.REF_invokeStatic || referenceKind == heap.REF_newInvokeSpecial) && !class.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(thread, class)
+
+		return
+	}
+
+	switch referenceKind {
+	case heap.REF_invokeVirtual, heap.REF_invokeInterface:
+		receiver := arguments.GetReferenceValue(0)
+
+		if receiver == nil {
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+		}
+
+		method = lookupMethodForReceiver(receiver, method)
+	case heap.REF_invokeSpecial:
+		if arguments.GetReferenceValue(0) == nil {
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+		}
+	case heap.REF_newInvokeSpecial:
+		// The new object is left on the stack as the result once <init> returns
+		object := class.NewObject()
+		frame.GetOperandStack().PushReferenceValue(object)
+		arguments = append(runtime_data_area.LocalVariables{{}}, arguments...)
+		arguments.SetReferenceValue(0, object)
+	}
+
+	newFrame := thread.NewFrame(method)
+
+	for i, argument := range arguments {
+		newFrame.GetLocalVariables().SetVariable(uint(i), argument)
+	}
+
+	thread.PushFrame(newFrame)
+	newFrame.EnterMethodMonitor()
+}
+
+func lookupMethodForReceiver(receiver *heap.Object, method *heap.Method) *heap.Method {
+	methodToBeInvoked := heap.LookupMethodInClass(receiver.GetClass(), method.GetName(), method.GetDescriptor())
+
+	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
+		methodToBeInvoked = heap.LookupDefaultMethod(receiver.GetClass(), method.GetName(), method.GetDescriptor())
+	}
+
+	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
+		panic(heap.NewJavaException("java/lang/AbstractMethodError", receiver.GetClass().GetJavaName()+"."+method.GetName()+method.GetDescriptor()))
+	}
+
+	return methodToBeInvoked
+}
+
+func accessField(frame *runtime_data_area.Frame, field *heap.Field, referenceKind int32, arguments runtime_data_area.LocalVariables) {
+	class := field.GetClass()
+	var fields heap.Variables
+
+	if field.IsStatic() {
+		if !class.IsInitializationStarted() {
+			frame.RevertNextPC()
+			base_instructions.InitializeClass(frame.GetThread(), class)
+
+			return
+		}
+
+		fields = class.GetStaticVariables()
+	} else {
+		object := arguments.GetReferenceValue(0)
+
+		if object == nil {
+			panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+		}
+
+		fields = object.GetFields()
+		arguments = arguments[1:]
+	}
+
+	index := field.GetVariableIndex()
+	operandStack := frame.GetOperandStack()
+
+	switch referenceKind {
+	case heap.REF_getField, heap.REF_getStatic:
+		switch field.GetDescriptor()[0] {
+		case 'J', 'D':
+			operandStack.PushLongValue(fields.GetLongValue(index))
+		case 'L', '[':
+			operandStack.PushReferenceValue(fields.GetReferenceValue(index))
+		default:
+			operandStack.PushIntegerValue(fields.GetIntegerValue(index))
+		}
+	case heap.REF_putField, heap.REF_putStatic:
+		switch field.GetDescriptor()[0] {
+		case 'J', 'D':
+			fields.SetLongValue(index, arguments.GetLongValue(0))
+		case 'L', '[':
+			fields.SetReferenceValue(index, arguments.GetReferenceValue(0))
+		default:
+			fields.SetIntegerValue(index, arguments.GetIntegerValue(0))
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package invoke
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaLangInvokeMethodHandleNatives = "java/lang/invoke/MethodHandleNatives"
+
+// MemberName flags, see MethodHandleNatives.Constants
+const (
+	memberNameIsMethod           = 0x00010000
+	memberNameIsConstructor      = 0x00020000
+	memberNameIsField            = 0x00040000
+	memberNameReferenceKindShift = 24
+	memberNameReferenceKindMask  = 0x0F
+)
+
+func init() {
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "getConstant", "(I)I", getConstant)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "init", "(Ljava/lang/invoke/MemberName;Ljava/lang/Object;)V", initMemberName)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "expand", "(Ljava/lang/invoke/MemberName;)V", expand)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "resolve",
+		"(Ljava/lang/invoke/MemberName;Ljava/lang/Class;)Ljava/lang/invoke/MemberName;", resolve)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "resolve",
+		"(Ljava/lang/invoke/MemberName;Ljava/lang/Class;Z)Ljava/lang/invoke/MemberName;", resolve)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "objectFieldOffset", "(Ljava/lang/invoke/MemberName;)J", fieldOffset)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "staticFieldOffset", "(Ljava/lang/invoke/MemberName;)J", fieldOffset)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "staticFieldBase", "(Ljava/lang/invoke/MemberName;)Ljava/lang/Object;", staticFieldBase)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "setCallSiteTargetNormal",
+		"(Ljava/lang/invoke/CallSite;Ljava/lang/invoke/MethodHandle;)V", setCallSiteTarget)
+	native_methods.RegisterNativeMethod(javaLangInvokeMethodHandleNatives, "setCallSiteTargetVolatile",
+		"(Ljava/lang/invoke/CallSite;Ljava/lang/invoke/MethodHandle;)V", setCallSiteTarget)
+
+	constantShimMethod := heap.GetConstantShimMethod()
+	native_methods.RegisterNativeMethod(constantShimMethod.GetClass().GetName(), constantShimMethod.GetName(), constantShimMethod.GetDescriptor(), keepConstant)
+}
+
+// Runs in the constant shim once the factory returned. The ldc that called it is in the frame
+// below; when another thread resolved the same constant first, its object is returned instead
+func keepConstant(frame *runtime_data_area.Frame) {
+	operandStack := frame.GetOperandStack()
+	index := uint(frame.GetLocalVariables().GetIntegerValue(0))
+	constant := frame.GetLowerFrame().GetMethod().GetClass().GetConstantPool().GetConstant(index)
+
+	switch constant.(type) {
+	case *heap.MethodTypeReference:
+		methodTypeReference := constant.(*heap.MethodTypeReference)
+
+		if methodTypeReference.GetMethodType() == nil {
+			methodTypeReference.SetMethodType(operandStack.GetReferenceValueBelowTop(0))
+		}
+
+		operandStack.PopReferenceValue()
+		operandStack.PushReferenceValue(methodTypeReference.GetMethodType())
+	case *heap.MethodHandleReference:
+		methodHandleReference := constant.(*heap.MethodHandleReference)
+
+		if methodHandleReference.GetMethodHandle() == nil {
+			methodHandleReference.SetMethodHandle(operandStack.GetReferenceValueBelowTop(0))
+		}
+
+		operandStack.PopReferenceValue()
+		operandStack.PushReferenceValue(methodHandleReference.GetMethodHandle())
+	}
+}
+
+// None of the optional VM features behind these constants are supported
+func getConstant(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(0)
+}
+
+// Fill in a MemberName from a java.lang.reflect.Method, Constructor or Field
+func initMemberName(frame *runtime_data_area.Frame) {
+	memberName := frame.GetLocalVariables().GetReferenceValue(0)
+	reflectionObject := frame.GetLocalVariables().GetReferenceValue(1)
+
+	if memberName == nil || reflectionObject == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	class := reflectionObject.GetReferenceValue("clazz", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	slot := reflectionObject.GetIntegerValue("slot", "I")
+
+	// The slot of a reflection object is the member's index in its class
+	switch reflectionObject.GetClass().GetName() {
+	case "java/lang/reflect/Method", "java/lang/reflect/Constructor":
+		setMemberNameMethod(memberName, class.GetMethods()[slot])
+	case "java/lang/reflect/Field":
+		setMemberNameField(memberName, class.GetFields()[slot], false)
+	default:
+		panic(heap.NewJavaException("java/lang/InternalError", "Unsupported member: "+reflectionObject.GetClass().GetJavaName()))
+	}
+
+	expandMemberName(memberName)
+}
+
+func expand(frame *runtime_data_area.Frame) {
+	memberName := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if memberName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	expandMemberName(memberName)
+}
+
+// Look up the member a MemberName describes, the resolved member is kept in its extra data
+func resolve(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	memberName := localVariables.GetReferenceValue(0)
+	isSpeculative := frame.GetMethod().GetArgumentsCount() > 2 && localVariables.GetIntegerValue(2) != 0
+
+	if memberName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	class := memberName.GetReferenceValue("clazz", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	name := heap.ConvertJavaStringToGoString(memberName.GetReferenceValue("name", "Ljava/lang/String;"))
+	descriptor := getMemberNameDescriptor(memberName)
+	flags := memberName.GetIntegerValue("flags", "I")
+	referenceKind := (flags >> memberNameReferenceKindShift) & memberNameReferenceKindMask
+
+	if flags&memberNameIsField != 0 {
+		field := heap.LookupField(class, name, descriptor)
+
+		if field != nil {
+			setMemberNameField(memberName, field, referenceKind == heap.REF_putField || referenceKind == heap.REF_putStatic)
+			frame.GetOperandStack().PushReferenceValue(memberName)
+		} else if isSpeculative {
+			frame.GetOperandStack().PushReferenceValue(nil)
+		} else {
+			panic(heap.NewJavaException("java/lang/NoSuchFieldError", name))
+		}
+
+		return
+	}
+
+	method := heap.LookupMethod(class, name, descriptor)
+
+	if method == nil && class.IsInterface() {
+		method = heap.LookupDefaultMethod(class, name, descriptor)
+	}
+
+	if method != nil {
+		setMemberNameMethod(memberName, method)
+
+		// Keep the kind the caller asked for, such as invokeSpecial for super calls
+		if referenceKind != 0 {
+			setMemberNameReferenceKind(memberName, referenceKind)
+		}
+
+		frame.GetOperandStack().PushReferenceValue(memberName)
+	} else if isSpeculative {
+		frame.GetOperandStack().PushReferenceValue(nil)
+	} else {
+		panic(heap.NewJavaException("java/lang/NoSuchMethodError", class.GetJavaName()+"."+name+descriptor))
+	}
+}
+
+func fieldOffset(frame *runtime_data_area.Frame) {
+	field := getMemberNameField(frame.GetLocalVariables().GetReferenceValue(0))
+
+	frame.GetOperandStack().PushLongValue(field.GetOffset())
+}
+
+// Static fields are addressed relative to their class object
+func staticFieldBase(frame *runtime_data_area.Frame) {
+	field := getMemberNameField(frame.GetLocalVariables().GetReferenceValue(0))
+
+	frame.GetOperandStack().PushReferenceValue(field.GetClass().GetJavaClass())
+}
+
+func setCallSiteTarget(frame *runtime_data_area.Frame) {
+	callSite := frame.GetLocalVariables().GetReferenceValue(0)
+	target := frame.GetLocalVariables().GetReferenceValue(1)
+
+	callSite.SetReferenceValue("target", "Ljava/lang/invoke/MethodHandle;", target)
+}
+
+func setMemberNameMethod(memberName *heap.Object, method *heap.Method) {
+	flags := int32(method.GetAccessFlags())
+	referenceKind := int32(heap.REF_invokeVirtual)
+
+	if method.GetName() == "<init>" {
+		flags |= memberNameIsConstructor
+		referenceKind = heap.REF_newInvokeSpecial
+	} else {
+		flags |= memberNameIsMethod
+
+		if method.IsStatic() {
+			referenceKind = heap.REF_invokeStatic
+		} else if method.IsPrivate() {
+			referenceKind = heap.REF_invokeSpecial
+		} else if method.GetClass().IsInterface() {
+			referenceKind = heap.REF_invokeInterface
+		}
+	}
+
+	memberName.SetReferenceValue("clazz", "Ljava/lang/Class;", method.GetClass().GetJavaClass())
+	memberName.SetIntegerValue("flags", "I", flags|referenceKind<<memberNameReferenceKindShift)
+	memberName.SetExtraData(method)
+}
+
+func setMemberNameField(memberName *heap.Object, field *heap.Field, isSetter bool) {
+	referenceKind := int32(heap.REF_getField)
+
+	if field.IsStatic() {
+		referenceKind = heap.REF_getStatic
+	}
+
+	if isSetter {
+		// putField and putStatic follow their getters
+		referenceKind += 2
+	}
+
+	flags := int32(field.GetAccessFlags()) | memberNameIsField
+
+	memberName.SetReferenceValue("clazz", "Ljava/lang/Class;", field.GetClass().GetJavaClass())
+	memberName.SetIntegerValue("flags", "I", flags|referenceKind<<memberNameReferenceKindShift)
+	memberName.SetExtraData(field)
+}
+
+func setMemberNameReferenceKind(memberName *heap.Object, referenceKind int32) {
+	flags := memberName.GetIntegerValue("flags", "I") &^ (memberNameReferenceKindMask << memberNameReferenceKindShift)
+
+	memberName.SetIntegerValue("flags", "I", flags|referenceKind<<memberNameReferenceKindShift)
+}
+
+// Fill in the name and type of a MemberName initialized from a reflection object
+func expandMemberName(memberName *heap.Object) {
+	var name, descriptor string
+
+	switch member := memberName.GetExtraData().(type) {
+	case *heap.Method:
+		name, descriptor = member.GetName(), member.GetDescriptor()
+	case *heap.Field:
+		name, descriptor = member.GetName(), member.GetDescriptor()
+	default:
+		return
+	}
+
+	classLoader := memberName.GetClass().GetClassLoader()
+
+	if memberName.GetReferenceValue("name", "Ljava/lang/String;") == nil {
+		memberName.SetReferenceValue("name", "Ljava/lang/String;", heap.ConvertGoStringToJavaString(classLoader, name))
+	}
+
+	// MemberName turns a descriptor string into a MethodType or Class when asked for its type
+	if memberName.GetReferenceValue("type", "Ljava/lang/Object;") == nil {
+		memberName.SetReferenceValue("type", "Ljava/lang/Object;", heap.ConvertGoStringToJavaString(classLoader, descriptor))
+	}
+}
+
+// The type of a MemberName is a descriptor string, a Class for fields or a MethodType for methods
+func getMemberNameDescriptor(memberName *heap.Object) string {
+	memberType := memberName.GetReferenceValue("type", "Ljava/lang/Object;")
+
+	if memberType == nil {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "MemberName has no type"))
+	}
+
+	switch memberType.GetClass().GetName() {
+	case "java/lang/String":
+		return heap.ConvertJavaStringToGoString(memberType)
+	case "java/lang/Class":
+		return memberType.GetExtraData().(*heap.Class).GetDescriptor()
+	}
+
+	returnType := memberType.GetReferenceValue("rtype", "Ljava/lang/Class;")
+	parameterTypes := memberType.GetReferenceValue("ptypes", "[Ljava/lang/Class;")
+	descriptor := "("
+
+	for _, parameterType := range parameterTypes.GetReferenceArray() {
+		descriptor += parameterType.GetExtraData().(*heap.Class).GetDescriptor()
+	}
+
+	return descriptor + ")" + returnType.GetExtraData().(*heap.Class).GetDescriptor()
+}
+
+func getMemberNameField(memberName *heap.Object) *heap.Field {
+	if memberName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	field, ok := memberName.GetExtraData().(*heap.Field)
+
+	if !ok {
+		panic(heap.NewJavaException("java/lang/InternalError", "MemberName is not a resolved field"))
+	}
+
+	return field
+}
@@ -1,10 +1,12 @@
 package lang
 
 import (
+	"time"
 	"unsafe"
 
 	"github.com/Frederick-S/jvmgo/native_methods"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
 )
 
 const javaLangObject = "java/lang/Object"
@@ -13,6 +15,9 @@ func init() {
 	native_methods.RegisterNativeMethod(javaLangObject, "getClass", "()Ljava/lang/Class;", getClass)
 	native_methods.RegisterNativeMethod(javaLangObject, "hashCode", "()I", getHashCode)
 	native_methods.RegisterNativeMethod(javaLangObject, "clone", "()Ljava/lang/Object;", clone)
+	native_methods.RegisterNativeMethod(javaLangObject, "wait", "(J)V", wait)
+	native_methods.RegisterNativeMethod(javaLangObject, "notify", "()V", notify)
+	native_methods.RegisterNativeMethod(javaLangObject, "notifyAll", "()V", notifyAll)
 }
 
 func getClass(frame *runtime_data_area.Frame) {
@@ -35,8 +40,27 @@ func clone(frame *runtime_data_area.Frame) {
 	cloneable := frame.GetLocalVariables().GetThis().GetClass().GetClassLoader().LoadClass("java/lang/Cloneable")
 
 	if !this.GetClass().IsImplementsFrom(cloneable) {
-		panic("java.lang.CloneNotSupportedException")
+		panic(heap.NewJavaException("java/lang/CloneNotSupportedException", this.GetClass().GetJavaName()))
 	}
 
 	frame.GetOperandStack().PushReferenceValue(this.Clone())
 }
+
+func wait(frame *runtime_data_area.Frame) {
+	this := frame.GetLocalVariables().GetThis()
+	milliseconds := frame.GetLocalVariables().GetLongValue(1)
+
+	if milliseconds < 0 {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "timeout value is negative"))
+	}
+
+	frame.GetThread().Wait(this, time.Duration(milliseconds)*time.Millisecond)
+}
+
+func notify(frame *runtime_data_area.Frame) {
+	frame.GetThread().Notify(frame.GetLocalVariables().GetThis())
+}
+
+func notifyAll(frame *runtime_data_area.Frame) {
+	frame.GetThread().NotifyAll(frame.GetLocalVariables().GetThis())
+}
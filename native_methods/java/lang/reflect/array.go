@@ -0,0 +1,50 @@
+package reflect
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaLangReflectArray = "java/lang/reflect/Array"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaLangReflectArray, "getLength", "(Ljava/lang/Object;)I", getLength)
+	native_methods.RegisterNativeMethod(javaLangReflectArray, "newArray", "(Ljava/lang/Class;I)Ljava/lang/Object;", newArray)
+}
+
+func getLength(frame *runtime_data_area.Frame) {
+	array := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if array == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if !array.GetClass().IsArray() {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "Argument is not an array"))
+	}
+
+	frame.GetOperandStack().PushIntegerValue(array.GetArrayLength())
+}
+
+func newArray(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	componentTypeObject := localVariables.GetReferenceValue(0)
+	length := localVariables.GetIntegerValue(1)
+
+	if componentTypeObject == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	componentType := componentTypeObject.GetExtraData().(*heap.Class)
+
+	if componentType.GetName() == "void" {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", ""))
+	}
+
+	if length < 0 {
+		panic(heap.NewJavaException("java/lang/NegativeArraySizeException", ""))
+	}
+
+	frame.GetOperandStack().PushReferenceValue(componentType.GetArrayClass().NewArray(uint(length)))
+}
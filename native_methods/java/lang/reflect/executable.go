@@ -0,0 +1,29 @@
+package reflect
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+func init() {
+	native_methods.RegisterNativeMethod("java/lang/reflect/Executable", "getTypeAnnotationBytes0", "()[B", getExecutableTypeAnnotationBytes0)
+	native_methods.RegisterNativeMethod("java/lang/reflect/Field", "getTypeAnnotationBytes0", "()[B", getFieldTypeAnnotationBytes0)
+}
+
+// Methods, constructors and fields are found by the slot Class.getDeclared*0 gave them
+func getExecutableTypeAnnotationBytes0(frame *runtime_data_area.Frame) {
+	this := frame.GetLocalVariables().GetThis()
+	class := this.GetReferenceValue("clazz", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	method := class.GetMethods()[this.GetIntegerValue("slot", "I")]
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoBytesToJavaBytes(class.GetClassLoader(), method.GetTypeAnnotations()))
+}
+
+func getFieldTypeAnnotationBytes0(frame *runtime_data_area.Frame) {
+	this := frame.GetLocalVariables().GetThis()
+	class := this.GetReferenceValue("clazz", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	field := class.GetFields()[this.GetIntegerValue("slot", "I")]
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoBytesToJavaBytes(class.GetClassLoader(), field.GetTypeAnnotations()))
+}
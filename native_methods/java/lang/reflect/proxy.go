@@ -0,0 +1,44 @@
+package reflect
+
+import (
+	"strings"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Only the JDK 8 Proxy native is registered, later runtimes define proxies through
+// Lookup.defineClass and are refused at startup
+func init() {
+	native_methods.RegisterNativeMethod("java/lang/reflect/Proxy", "defineClass0", "(Ljava/lang/ClassLoader;Ljava/lang/String;[BII)Ljava/lang/Class;", defineClass0)
+}
+
+// The proxy class goes to the VM's own loader like every other class, whatever loader is passed in
+func defineClass0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	javaName := localVariables.GetReferenceValue(1)
+	classBytes := localVariables.GetReferenceValue(2)
+	offset := localVariables.GetIntegerValue(3)
+	length := localVariables.GetIntegerValue(4)
+
+	if javaName == nil || classBytes == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if offset < 0 || length < 0 || length > classBytes.GetArrayLength()-offset {
+		panic(heap.NewJavaException("java/lang/ArrayIndexOutOfBoundsException", ""))
+	}
+
+	classData := make([]byte, length)
+
+	for i, value := range classBytes.GetByteArray()[offset : offset+length] {
+		classData[i] = byte(value)
+	}
+
+	className := strings.Replace(heap.ConvertJavaStringToGoString(javaName), ".", "/", -1)
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	class := classLoader.DefineGeneratedClass(className, classData)
+
+	frame.GetOperandStack().PushReferenceValue(class.GetJavaClass())
+}
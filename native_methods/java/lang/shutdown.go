@@ -0,0 +1,20 @@
+package lang
+
+import (
+	"os"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+)
+
+const javaLangShutdown = "java/lang/Shutdown"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaLangShutdown, "halt0", "(I)V", halt0)
+}
+
+func halt0(frame *runtime_data_area.Frame) {
+	status := frame.GetLocalVariables().GetIntegerValue(0)
+
+	os.Exit(int(status))
+}
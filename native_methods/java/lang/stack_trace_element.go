@@ -9,6 +9,6 @@ type StackTraceElement struct {
 	lineNumber int
 }
 
-func (stackTraceElement *StackTraceElement) ToString() string {
+func (stackTraceElement *StackTraceElement) String() string {
 	return fmt.Sprintf("%s.%s(%s:%d)", stackTraceElement.className, stackTraceElement.methodName, stackTraceElement.fileName, stackTraceElement.lineNumber)
 }
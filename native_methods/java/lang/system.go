@@ -1,6 +1,11 @@
 package lang
 
 import (
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
 	"github.com/Frederick-S/jvmgo/native_methods"
 	"github.com/Frederick-S/jvmgo/runtime_data_area"
 	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
@@ -10,8 +15,19 @@ const javaLangSystem = "java/lang/System"
 
 func init() {
 	native_methods.RegisterNativeMethod(javaLangSystem, "arraycopy", "(Ljava/lang/Object;ILjava/lang/Object;II)V", arraycopy)
+	native_methods.RegisterNativeMethod(javaLangSystem, "initProperties", "(Ljava/util/Properties;)Ljava/util/Properties;", initProperties)
+	native_methods.RegisterNativeMethod(javaLangSystem, "setIn0", "(Ljava/io/InputStream;)V", setIn0)
+	native_methods.RegisterNativeMethod(javaLangSystem, "setOut0", "(Ljava/io/PrintStream;)V", setOut0)
+	native_methods.RegisterNativeMethod(javaLangSystem, "setErr0", "(Ljava/io/PrintStream;)V", setErr0)
+	native_methods.RegisterNativeMethod(javaLangSystem, "mapLibraryName", "(Ljava/lang/String;)Ljava/lang/String;", mapLibraryName)
+	native_methods.RegisterNativeMethod(javaLangSystem, "currentTimeMillis", "()J", currentTimeMillis)
+	native_methods.RegisterNativeMethod(javaLangSystem, "nanoTime", "()J", nanoTime)
+	native_methods.RegisterNativeMethod(javaLangSystem, "identityHashCode", "(Ljava/lang/Object;)I", identityHashCode)
 }
 
+// Reference point for nanoTime, which only has to be monotonic
+var startTime = time.Now()
+
 func arraycopy(frame *runtime_data_area.Frame) {
 	localVariables := frame.GetLocalVariables()
 	sourceArray := localVariables.GetReferenceValue(0)
@@ -21,17 +37,17 @@ func arraycopy(frame *runtime_data_area.Frame) {
 	length := localVariables.GetIntegerValue(4)
 
 	if sourceArray == nil || targetArray == nil {
-		panic("java.lang.NullPointerException")
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
 	}
 
 	if !checkArrayCopy(sourceArray, targetArray) {
-		panic("java.lang.ArrayStoreException")
+		panic(heap.NewJavaException("java/lang/ArrayStoreException", "arraycopy: type mismatch"))
 	}
 
 	if sourceArrayPosition < 0 || targetArrayPosition < 0 || length < 0 ||
 		sourceArrayPosition+length > sourceArray.GetArrayLength() ||
 		targetArrayPosition+length > targetArray.GetArrayLength() {
-		panic("java.lang.IndexOutOfBoundsException")
+		panic(heap.NewJavaException("java/lang/ArrayIndexOutOfBoundsException", "arraycopy: index out of bounds"))
 	}
 
 	heap.CopyArray(sourceArray, targetArray, sourceArrayPosition, targetArrayPosition, length)
@@ -52,3 +68,81 @@ func checkArrayCopy(sourceArray, targetArray *heap.Object) bool {
 
 	return true
 }
+
+// Calls props.setProperty for every property, the native then returns props
+func initProperties(frame *runtime_data_area.Frame) {
+	properties := frame.GetLocalVariables().GetReferenceValue(0)
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	operandStack := frame.GetOperandStack()
+
+	operandStack.PushReferenceValue(properties)
+
+	setPropertyMethod := heap.LookupMethodInClass(properties.GetClass(), "setProperty", "(Ljava/lang/String;Ljava/lang/String;)Ljava/lang/Object;")
+	systemProperties := getSystemProperties(classLoader.GetClassFinder())
+	names := make([]string, 0, len(systemProperties))
+
+	for name := range systemProperties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		operandStack.PushReferenceValue(properties)
+		operandStack.PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, name))
+		operandStack.PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, systemProperties[name]))
+
+		base_instructions.InvokeMethodDiscardingResult(frame, setPropertyMethod)
+	}
+}
+
+// System.in, out and err are final, so they can only be set from native code
+func setIn0(frame *runtime_data_area.Frame) {
+	inputStream := frame.GetLocalVariables().GetReferenceValue(0)
+
+	frame.GetMethod().GetClass().SetReferenceVariable("in", "Ljava/io/InputStream;", inputStream)
+}
+
+func setOut0(frame *runtime_data_area.Frame) {
+	printStream := frame.GetLocalVariables().GetReferenceValue(0)
+
+	frame.GetMethod().GetClass().SetReferenceVariable("out", "Ljava/io/PrintStream;", printStream)
+}
+
+func setErr0(frame *runtime_data_area.Frame) {
+	printStream := frame.GetLocalVariables().GetReferenceValue(0)
+
+	frame.GetMethod().GetClass().SetReferenceVariable("err", "Ljava/io/PrintStream;", printStream)
+}
+
+// zip -> libzip.so
+func mapLibraryName(frame *runtime_data_area.Frame) {
+	libraryName := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if libraryName == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+	fileName := "lib" + heap.ConvertJavaStringToGoString(libraryName) + ".so"
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, fileName))
+}
+
+func currentTimeMillis(frame *runtime_data_area.Frame) {
+	milliseconds := time.Now().UnixNano() / int64(time.Millisecond)
+
+	frame.GetOperandStack().PushLongValue(milliseconds)
+}
+
+func nanoTime(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushLongValue(int64(time.Since(startTime)))
+}
+
+// Same value Object.hashCode returns, whether or not the class overrides it
+func identityHashCode(frame *runtime_data_area.Frame) {
+	object := frame.GetLocalVariables().GetReferenceValue(0)
+	hashCode := int32(uintptr(unsafe.Pointer(object)))
+
+	frame.GetOperandStack().PushIntegerValue(hashCode)
+}
@@ -0,0 +1,123 @@
+package lang
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/Frederick-S/jvmgo/classpath"
+	"github.com/Frederick-S/jvmgo/options"
+)
+
+// Properties System.initProperties fills in, -D options take precedence
+func getSystemProperties(classFinder *classpath.ClassFinder) map[string]string {
+	javaHome := classFinder.GetJavaHome()
+	release := classpath.GetRuntimeRelease()
+	specificationVersion := strconv.Itoa(release)
+
+	if release <= 8 {
+		specificationVersion = "1." + specificationVersion
+	}
+
+	workingDirectory, _ := os.Getwd()
+	userName, userHome := getUserNameAndHome()
+	lineSeparator := "\n"
+
+	if runtime.GOOS == "windows" {
+		lineSeparator = "\r\n"
+	}
+
+	properties := map[string]string{
+		"java.version":                  getJavaVersion(release),
+		"java.vendor":                   "jvmgo",
+		"java.vendor.url":               "https://github.com/Frederick-S/jvmgo",
+		"java.home":                     javaHome,
+		"java.class.version":            strconv.Itoa(44+release) + ".0",
+		"java.class.path":               classFinder.GetClasspath(),
+		"java.library.path":             "",
+		"java.ext.dirs":                 filepath.Join(javaHome, "lib", "ext"),
+		"java.io.tmpdir":                os.TempDir(),
+		"java.specification.name":       "Java Platform API Specification",
+		"java.specification.vendor":     "Oracle Corporation",
+		"java.specification.version":    specificationVersion,
+		"java.vm.name":                  "jvmgo",
+		"java.vm.vendor":                "jvmgo",
+		"java.vm.version":               "0.0.1",
+		"java.vm.info":                  "interpreted mode",
+		"java.vm.specification.name":    "Java Virtual Machine Specification",
+		"java.vm.specification.vendor":  "Oracle Corporation",
+		"java.vm.specification.version": specificationVersion,
+		"sun.boot.library.path":         filepath.Join(javaHome, "lib"),
+		"sun.arch.data.model":           strconv.Itoa(strconv.IntSize),
+		"sun.cpu.endian":                "little",
+		"sun.io.unicode.encoding":       "UnicodeLittle",
+		"sun.jnu.encoding":              "UTF-8",
+		"file.encoding":                 "UTF-8",
+		"file.separator":                string(os.PathSeparator),
+		"path.separator":                string(os.PathListSeparator),
+		"line.separator":                lineSeparator,
+		"os.name":                       getOSName(),
+		"os.arch":                       getOSArch(),
+		"os.version":                    "",
+		"user.name":                     userName,
+		"user.home":                     userHome,
+		"user.dir":                      workingDirectory,
+		"user.language":                 "en",
+	}
+
+	for name, value := range options.SystemProperties {
+		properties[name] = value
+	}
+
+	return properties
+}
+
+// 1.8.0 for Java 8, the bare release number from Java 9 on
+func getJavaVersion(release int) string {
+	if release <= 8 {
+		return "1." + strconv.Itoa(release) + ".0"
+	}
+
+	return strconv.Itoa(release)
+}
+
+func getUserNameAndHome() (string, string) {
+	currentUser, err := user.Current()
+
+	if err == nil {
+		return currentUser.Username, currentUser.HomeDir
+	}
+
+	userHome, _ := os.UserHomeDir()
+
+	return os.Getenv("USER"), userHome
+}
+
+// Names the JDK reports for the platforms Go runs on
+func getOSName() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "Linux"
+	case "darwin":
+		return "Mac OS X"
+	case "windows":
+		return "Windows"
+	case "freebsd":
+		return "FreeBSD"
+	default:
+		return runtime.GOOS
+	}
+}
+
+func getOSArch() string {
+	switch runtime.GOARCH {
+	case "386":
+		return "x86"
+	case "arm64":
+		return "aarch64"
+	default:
+		return runtime.GOARCH
+	}
+}
@@ -0,0 +1,154 @@
+package lang
+
+import (
+	"time"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaLangThread = "java/lang/Thread"
+
+// Values of Thread.threadStatus, see sun.misc.VM.toThreadState
+const (
+	threadStatusRunnable   = 0x0005
+	threadStatusTerminated = 0x0002
+)
+
+func init() {
+	native_methods.RegisterNativeMethod(javaLangThread, "currentThread", "()Ljava/lang/Thread;", currentThread)
+	native_methods.RegisterNativeMethod(javaLangThread, "start0", "()V", start0)
+	native_methods.RegisterNativeMethod(javaLangThread, "isAlive", "()Z", isAlive)
+	native_methods.RegisterNativeMethod(javaLangThread, "setPriority0", "(I)V", setPriority0)
+	native_methods.RegisterNativeMethod(javaLangThread, "yield", "()V", yield)
+	native_methods.RegisterNativeMethod(javaLangThread, "sleep", "(J)V", sleep)
+	native_methods.RegisterNativeMethod(javaLangThread, "holdsLock", "(Ljava/lang/Object;)Z", holdsLock)
+	native_methods.RegisterNativeMethod(javaLangThread, "interrupt0", "()V", interrupt0)
+	native_methods.RegisterNativeMethod(javaLangThread, "isInterrupted", "(Z)Z", isInterrupted)
+}
+
+func currentThread(frame *runtime_data_area.Frame) {
+	thread := frame.GetThread()
+
+	if thread.GetJavaThread() == nil {
+		thread.SetJavaThread(createMainThread(frame.GetMethod().GetClass().GetClassLoader()))
+	}
+
+	frame.GetOperandStack().PushReferenceValue(thread.GetJavaThread())
+}
+
+// The main thread is not started from Java, so its Thread object is filled in directly
+func createMainThread(classLoader *heap.ClassLoader) *heap.Object {
+	name := heap.ConvertGoStringToJavaString(classLoader, "main")
+
+	threadGroup := classLoader.LoadClass("java/lang/ThreadGroup").NewObject()
+	threadGroup.SetReferenceValue("name", "Ljava/lang/String;", name)
+	threadGroup.SetIntegerValue("maxPriority", "I", 10)
+
+	javaThread := classLoader.LoadClass(javaLangThread).NewObject()
+	javaThread.SetReferenceValue("group", "Ljava/lang/ThreadGroup;", threadGroup)
+	javaThread.SetIntegerValue("priority", "I", 5)
+	javaThread.SetIntegerValue("threadStatus", "I", threadStatusRunnable)
+
+	// A char array before Java 9
+	if javaThread.GetClass().GetField("name", "[C", false) != nil {
+		javaThread.SetReferenceValue("name", "[C", name.GetReferenceValue("value", "[C"))
+	} else {
+		javaThread.SetReferenceValue("name", "Ljava/lang/String;", name)
+	}
+
+	return javaThread
+}
+
+// Run Thread.run on a new thread, followed by Thread.exit to clean up
+func start0(frame *runtime_data_area.Frame) {
+	javaThread := frame.GetLocalVariables().GetThis()
+
+	if javaThread.GetExtraData() != nil {
+		panic(heap.NewJavaException("java/lang/IllegalThreadStateException", ""))
+	}
+
+	thread := runtime_data_area.NewThread()
+	thread.SetJavaThread(javaThread)
+	thread.SetPriority(javaThread.GetIntegerValue("priority", "I"))
+
+	exitMethod := javaThread.GetClass().GetMethod("exit", "()V", false)
+
+	if exitMethod != nil {
+		exitFrame := thread.NewFrame(exitMethod)
+		exitFrame.GetLocalVariables().SetReferenceValue(0, javaThread)
+		thread.PushFrame(exitFrame)
+	}
+
+	runMethod := heap.LookupMethodInClass(javaThread.GetClass(), "run", "()V")
+	runFrame := thread.NewFrame(runMethod)
+	runFrame.GetLocalVariables().SetReferenceValue(0, javaThread)
+	thread.PushFrame(runFrame)
+
+	javaThread.SetIntegerValue("threadStatus", "I", threadStatusRunnable)
+	isDaemon := javaThread.GetIntegerValue("daemon", "Z") != 0
+
+	thread.Start(isDaemon, func() {
+		javaThread.SetIntegerValue("threadStatus", "I", threadStatusTerminated)
+	})
+}
+
+func isAlive(frame *runtime_data_area.Frame) {
+	thread, ok := frame.GetLocalVariables().GetThis().GetExtraData().(*runtime_data_area.Thread)
+
+	frame.GetOperandStack().PushBooleanValue(ok && thread.IsAlive())
+}
+
+// Priorities are recorded but goroutines are scheduled by the Go runtime
+func setPriority0(frame *runtime_data_area.Frame) {
+	thread, ok := frame.GetLocalVariables().GetThis().GetExtraData().(*runtime_data_area.Thread)
+
+	if ok {
+		thread.SetPriority(frame.GetLocalVariables().GetIntegerValue(1))
+	}
+}
+
+func yield(frame *runtime_data_area.Frame) {
+	runtime_data_area.YieldInterpreterLock()
+}
+
+func sleep(frame *runtime_data_area.Frame) {
+	milliseconds := frame.GetLocalVariables().GetLongValue(0)
+
+	if milliseconds < 0 {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "timeout value is negative"))
+	}
+
+	if milliseconds == 0 {
+		runtime_data_area.YieldInterpreterLock()
+	} else {
+		frame.GetThread().Sleep(time.Duration(milliseconds) * time.Millisecond)
+	}
+}
+
+func holdsLock(frame *runtime_data_area.Frame) {
+	object := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if object == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	frame.GetOperandStack().PushBooleanValue(frame.GetThread().HoldsMonitor(object))
+}
+
+// Unstarted threads have nothing to interrupt
+func interrupt0(frame *runtime_data_area.Frame) {
+	thread, ok := frame.GetLocalVariables().GetThis().GetExtraData().(*runtime_data_area.Thread)
+
+	if ok {
+		thread.Interrupt()
+	}
+}
+
+func isInterrupted(frame *runtime_data_area.Frame) {
+	isClearInterrupted := frame.GetLocalVariables().GetIntegerValue(1) != 0
+	thread, ok := frame.GetLocalVariables().GetThis().GetExtraData().(*runtime_data_area.Thread)
+
+	frame.GetOperandStack().PushBooleanValue(ok && thread.IsInterrupted(isClearInterrupted))
+}
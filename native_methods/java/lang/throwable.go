@@ -24,10 +24,12 @@ func fillInStackTrace(frame *runtime_data_area.Frame) {
 func createStackTraceElements(object *heap.Object, thread *runtime_data_area.Thread) []*StackTraceElement {
 	skip := distanceToObject(object.GetClass()) + 2
 	frames := thread.GetFrames()[skip:]
-	stackTraceElements := make([]*StackTraceElement, len(frames))
+	stackTraceElements := make([]*StackTraceElement, 0, len(frames))
 
-	for i, frame := range frames {
-		stackTraceElements[i] = createStackTraceElement(frame)
+	for _, frame := range frames {
+		if !frame.GetMethod().IsShim() {
+			stackTraceElements = append(stackTraceElements, createStackTraceElement(frame))
+		}
 	}
 
 	return stackTraceElements
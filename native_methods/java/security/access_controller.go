@@ -0,0 +1,92 @@
+package security
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const javaSecurityAccessController = "java/security/AccessController"
+
+func init() {
+	native_methods.RegisterNativeMethod(javaSecurityAccessController, "getStackAccessControlContext", "()Ljava/security/AccessControlContext;", getStackAccessControlContext)
+	native_methods.RegisterNativeMethod(javaSecurityAccessController, "getInheritedAccessControlContext", "()Ljava/security/AccessControlContext;", getInheritedAccessControlContext)
+	native_methods.RegisterNativeMethod(javaSecurityAccessController, "doPrivileged", "(Ljava/security/PrivilegedAction;)Ljava/lang/Object;", doPrivileged)
+	native_methods.RegisterNativeMethod(javaSecurityAccessController, "doPrivileged", "(Ljava/security/PrivilegedAction;Ljava/security/AccessControlContext;)Ljava/lang/Object;", doPrivileged)
+	native_methods.RegisterNativeMethod(javaSecurityAccessController, "doPrivileged", "(Ljava/security/PrivilegedExceptionAction;)Ljava/lang/Object;", doPrivilegedWithException)
+	native_methods.RegisterNativeMethod(javaSecurityAccessController, "doPrivileged", "(Ljava/security/PrivilegedExceptionAction;Ljava/security/AccessControlContext;)Ljava/lang/Object;", doPrivilegedWithException)
+
+	privilegedShimMethod := heap.GetPrivilegedShimMethod()
+	native_methods.RegisterNativeMethod(privilegedShimMethod.GetClass().GetName(), privilegedShimMethod.GetName(), privilegedShimMethod.GetDescriptor(), wrapCheckedException)
+}
+
+// Runs action.run(), its result becomes the result of doPrivileged
+func doPrivileged(frame *runtime_data_area.Frame) {
+	action := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if action == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	runMethod := heap.LookupMethodInClass(action.GetClass(), "run", "()Ljava/lang/Object;")
+
+	frame.GetOperandStack().PushReferenceValue(action)
+
+	base_instructions.InvokeMethod(frame, runMethod)
+}
+
+// The privileged shim goes below action.run() to catch the checked exceptions it throws
+func doPrivilegedWithException(frame *runtime_data_area.Frame) {
+	action := frame.GetLocalVariables().GetReferenceValue(0)
+
+	if action == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	runMethod := heap.LookupMethodInClass(action.GetClass(), "run", "()Ljava/lang/Object;")
+	thread := frame.GetThread()
+	shimFrame := thread.NewFrame(heap.GetPrivilegedShimMethod())
+	shimFrame.SetNextPC(heap.PrivilegedShimReturnPC)
+	shimFrame.GetOperandStack().PushReferenceValue(action)
+
+	thread.PushFrame(shimFrame)
+	base_instructions.InvokeMethod(shimFrame, runMethod)
+}
+
+// Runs in the privileged shim once action.run() threw, unchecked exceptions are thrown on as is
+func wrapCheckedException(frame *runtime_data_area.Frame) {
+	thread := frame.GetThread()
+	operandStack := frame.GetOperandStack()
+	exception := operandStack.GetReferenceValueBelowTop(0)
+	classLoader := exception.GetClass().GetClassLoader()
+
+	if !exception.IsInstanceOf(classLoader.LoadClass("java/lang/Exception")) || exception.IsInstanceOf(classLoader.LoadClass("java/lang/RuntimeException")) {
+		return
+	}
+
+	operandStack.PopReferenceValue()
+
+	privilegedActionExceptionClass := classLoader.LoadClass("java/security/PrivilegedActionException")
+	privilegedActionException := privilegedActionExceptionClass.NewObject()
+
+	operandStack.PushReferenceValue(privilegedActionException)
+
+	constructorFrame := thread.NewFrame(privilegedActionExceptionClass.GetConstructor("(Ljava/lang/Exception;)V"))
+	constructorFrame.GetLocalVariables().SetReferenceValue(0, privilegedActionException)
+	constructorFrame.GetLocalVariables().SetReferenceValue(1, exception)
+	thread.PushFrame(constructorFrame)
+
+	if !privilegedActionExceptionClass.IsInitializationStarted() {
+		base_instructions.InitializeClass(thread, privilegedActionExceptionClass)
+	}
+}
+
+// There are no protection domains, so every caller is fully privileged
+func getStackAccessControlContext(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushReferenceValue(nil)
+}
+
+func getInheritedAccessControlContext(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushReferenceValue(nil)
+}
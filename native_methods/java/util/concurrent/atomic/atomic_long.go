@@ -0,0 +1,15 @@
+package atomic
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+)
+
+func init() {
+	native_methods.RegisterNativeMethod("java/util/concurrent/atomic/AtomicLong", "VMSupportsCS8", "()Z", vmSupportsCS8)
+}
+
+// Unsafe.compareAndSwapLong works on longs directly, AtomicLong need not fall back to locks
+func vmSupportsCS8(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushBooleanValue(true)
+}
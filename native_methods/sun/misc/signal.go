@@ -0,0 +1,36 @@
+package misc
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Signals Java code installs handlers for, e.g. java.lang.Terminator
+var signalNumbers = map[string]int32{
+	"HUP":  1,
+	"INT":  2,
+	"TERM": 15,
+}
+
+func init() {
+	native_methods.RegisterNativeMethod("sun/misc/Signal", "findSignal", "(Ljava/lang/String;)I", findSignal)
+	native_methods.RegisterNativeMethod("sun/misc/Signal", "handle0", "(IJ)J", handle0)
+}
+
+func findSignal(frame *runtime_data_area.Frame) {
+	name := heap.ConvertJavaStringToGoString(frame.GetLocalVariables().GetReferenceValue(0))
+	number, ok := signalNumbers[name]
+
+	if !ok {
+		number = -1
+	}
+
+	frame.GetOperandStack().PushIntegerValue(number)
+}
+
+// Handlers are accepted but never dispatched, signals keep their host behavior.
+// Returns 0, the previous handler was the default one
+func handle0(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushLongValue(0)
+}
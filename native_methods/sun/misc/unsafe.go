@@ -0,0 +1,486 @@
+package misc
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+const sunMiscUnsafe = "sun/misc/Unsafe"
+
+// Accessors of the values that fit in one slot, with the descriptor used in their signatures
+var unsafeIntegerTypes = map[string]byte{
+	"Boolean": 'Z',
+	"Byte":    'B',
+	"Char":    'C',
+	"Short":   'S',
+	"Int":     'I',
+	"Float":   'F',
+}
+
+var unsafeLongTypes = map[string]byte{
+	"Long":   'J',
+	"Double": 'D',
+}
+
+func init() {
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "arrayBaseOffset", "(Ljava/lang/Class;)I", arrayBaseOffset)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "arrayIndexScale", "(Ljava/lang/Class;)I", arrayIndexScale)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "addressSize", "()I", addressSize)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "pageSize", "()I", pageSize)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "objectFieldOffset", "(Ljava/lang/reflect/Field;)J", fieldOffset)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "staticFieldOffset", "(Ljava/lang/reflect/Field;)J", fieldOffset)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "staticFieldBase", "(Ljava/lang/reflect/Field;)Ljava/lang/Object;", staticFieldBase)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "allocateInstance", "(Ljava/lang/Class;)Ljava/lang/Object;", allocateInstance)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "ensureClassInitialized", "(Ljava/lang/Class;)V", ensureClassInitialized)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "shouldBeInitialized", "(Ljava/lang/Class;)Z", shouldBeInitialized)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "throwException", "(Ljava/lang/Throwable;)V", throwException)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "compareAndSwapInt", "(Ljava/lang/Object;JII)Z", compareAndSwapInt)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "compareAndSwapLong", "(Ljava/lang/Object;JJJ)Z", compareAndSwapLong)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "compareAndSwapObject",
+		"(Ljava/lang/Object;JLjava/lang/Object;Ljava/lang/Object;)Z", compareAndSwapObject)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "loadFence", "()V", fence)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "storeFence", "()V", fence)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "fullFence", "()V", fence)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "park", "(ZJ)V", park)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "unpark", "(Ljava/lang/Object;)V", unpark)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "monitorEnter", "(Ljava/lang/Object;)V", monitorEnter)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "monitorExit", "(Ljava/lang/Object;)V", monitorExit)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "tryMonitorEnter", "(Ljava/lang/Object;)Z", tryMonitorEnter)
+
+	// Only one thread runs at a time, so volatile and ordered accesses need no extra fences
+	for name, descriptor := range unsafeIntegerTypes {
+		for _, suffix := range []string{"", "Volatile"} {
+			native_methods.RegisterNativeMethod(sunMiscUnsafe, "get"+name+suffix, "(Ljava/lang/Object;J)"+string(descriptor), newGetIntegerValue(descriptor, true))
+			native_methods.RegisterNativeMethod(sunMiscUnsafe, "put"+name+suffix, "(Ljava/lang/Object;J"+string(descriptor)+")V", newPutIntegerValue(descriptor, true))
+		}
+
+		native_methods.RegisterNativeMethod(sunMiscUnsafe, "get"+name, "(J)"+string(descriptor), newGetIntegerValue(descriptor, false))
+		native_methods.RegisterNativeMethod(sunMiscUnsafe, "put"+name, "(J"+string(descriptor)+")V", newPutIntegerValue(descriptor, false))
+	}
+
+	for name, descriptor := range unsafeLongTypes {
+		for _, suffix := range []string{"", "Volatile"} {
+			native_methods.RegisterNativeMethod(sunMiscUnsafe, "get"+name+suffix, "(Ljava/lang/Object;J)"+string(descriptor), newGetLongValue(true))
+			native_methods.RegisterNativeMethod(sunMiscUnsafe, "put"+name+suffix, "(Ljava/lang/Object;J"+string(descriptor)+")V", newPutLongValue(true))
+		}
+
+		native_methods.RegisterNativeMethod(sunMiscUnsafe, "get"+name, "(J)"+string(descriptor), newGetLongValue(false))
+		native_methods.RegisterNativeMethod(sunMiscUnsafe, "put"+name, "(J"+string(descriptor)+")V", newPutLongValue(false))
+	}
+
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "getAddress", "(J)J", newGetLongValue(false))
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "putAddress", "(JJ)V", newPutLongValue(false))
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "putOrderedInt", "(Ljava/lang/Object;JI)V", newPutIntegerValue('I', true))
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "putOrderedLong", "(Ljava/lang/Object;JJ)V", newPutLongValue(true))
+
+	for _, suffix := range []string{"", "Volatile"} {
+		native_methods.RegisterNativeMethod(sunMiscUnsafe, "getObject"+suffix, "(Ljava/lang/Object;J)Ljava/lang/Object;", getObject)
+		native_methods.RegisterNativeMethod(sunMiscUnsafe, "putObject"+suffix, "(Ljava/lang/Object;JLjava/lang/Object;)V", putObject)
+	}
+
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "putOrderedObject", "(Ljava/lang/Object;JLjava/lang/Object;)V", putObject)
+}
+
+// Array elements are addressed by byte offsets, like fields of a C struct
+func arrayBaseOffset(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(0)
+}
+
+func arrayIndexScale(frame *runtime_data_area.Frame) {
+	class := getClass(frame)
+
+	if !class.IsArray() {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", class.GetJavaName()))
+	}
+
+	frame.GetOperandStack().PushIntegerValue(int32(getArrayIndexScale(class.GetName()[1])))
+}
+
+func addressSize(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(8)
+}
+
+func pageSize(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(4096)
+}
+
+func fieldOffset(frame *runtime_data_area.Frame) {
+	field := getField(frame.GetLocalVariables().GetReferenceValue(1))
+
+	frame.GetOperandStack().PushLongValue(field.GetOffset())
+}
+
+func staticFieldBase(frame *runtime_data_area.Frame) {
+	field := getField(frame.GetLocalVariables().GetReferenceValue(1))
+
+	frame.GetOperandStack().PushReferenceValue(field.GetClass().GetJavaClass())
+}
+
+// The heap field behind a java.lang.reflect.Field
+func getField(fieldObject *heap.Object) *heap.Field {
+	if fieldObject == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	class := fieldObject.GetReferenceValue("clazz", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	name := heap.ConvertJavaStringToGoString(fieldObject.GetReferenceValue("name", "Ljava/lang/String;"))
+	descriptor := fieldObject.GetReferenceValue("type", "Ljava/lang/Class;").GetExtraData().(*heap.Class).GetDescriptor()
+
+	for _, field := range class.GetFields() {
+		if field.GetName() == name && field.GetDescriptor() == descriptor {
+			return field
+		}
+	}
+
+	panic(heap.NewJavaException("java/lang/NoSuchFieldError", name))
+}
+
+func allocateInstance(frame *runtime_data_area.Frame) {
+	class := getClass(frame)
+
+	if !class.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), class)
+
+		return
+	}
+
+	if class.IsInterface() || class.IsAbstract() || class.IsArray() || class.IsPrimitive() {
+		panic(heap.NewJavaException("java/lang/InstantiationException", class.GetJavaName()))
+	}
+
+	frame.GetOperandStack().PushReferenceValue(class.NewObject())
+}
+
+func ensureClassInitialized(frame *runtime_data_area.Frame) {
+	class := getClass(frame)
+
+	if !class.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), class)
+	}
+}
+
+func shouldBeInitialized(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushBooleanValue(!getClass(frame).IsInitializationStarted())
+}
+
+func getClass(frame *runtime_data_area.Frame) *heap.Class {
+	javaClass := frame.GetLocalVariables().GetReferenceValue(1)
+
+	if javaClass == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	return javaClass.GetExtraData().(*heap.Class)
+}
+
+// Rethrow the exception from a shim frame, as if by athrow
+func throwException(frame *runtime_data_area.Frame) {
+	exception := frame.GetLocalVariables().GetReferenceValue(1)
+	thread := frame.GetThread()
+
+	shimFrame := thread.NewFrame(heap.GetAThrowShimMethod())
+	shimFrame.GetOperandStack().PushReferenceValue(exception)
+	thread.PushFrame(shimFrame)
+}
+
+func compareAndSwapInt(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	object := localVariables.GetReferenceValue(1)
+	offset := localVariables.GetLongValue(2)
+	expectedValue := localVariables.GetIntegerValue(4)
+	newValue := localVariables.GetIntegerValue(5)
+	isSwapped := getIntegerValue(object, offset, 'I') == expectedValue
+
+	if isSwapped {
+		setIntegerValue(object, offset, 'I', newValue)
+	}
+
+	frame.GetOperandStack().PushBooleanValue(isSwapped)
+}
+
+func compareAndSwapLong(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	object := localVariables.GetReferenceValue(1)
+	offset := localVariables.GetLongValue(2)
+	expectedValue := localVariables.GetLongValue(4)
+	newValue := localVariables.GetLongValue(6)
+	isSwapped := getLongValue(object, offset) == expectedValue
+
+	if isSwapped {
+		setLongValue(object, offset, newValue)
+	}
+
+	frame.GetOperandStack().PushBooleanValue(isSwapped)
+}
+
+func compareAndSwapObject(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	object := localVariables.GetReferenceValue(1)
+	offset := localVariables.GetLongValue(2)
+	expectedValue := localVariables.GetReferenceValue(4)
+	newValue := localVariables.GetReferenceValue(5)
+	isSwapped := getReferenceValue(object, offset) == expectedValue
+
+	if isSwapped {
+		setReferenceValue(object, offset, newValue)
+	}
+
+	frame.GetOperandStack().PushBooleanValue(isSwapped)
+}
+
+func fence(frame *runtime_data_area.Frame) {
+}
+
+// time is nanoseconds from now, or a deadline in milliseconds since the epoch if isAbsolute
+func park(frame *runtime_data_area.Frame) {
+	isAbsolute := frame.GetLocalVariables().GetIntegerValue(1) != 0
+	parkTime := frame.GetLocalVariables().GetLongValue(2)
+	timeout := time.Duration(0)
+
+	if isAbsolute {
+		timeout = time.Unix(0, parkTime*int64(time.Millisecond)).Sub(time.Now())
+
+		if timeout <= 0 {
+			return
+		}
+	} else if parkTime < 0 {
+		return
+	} else {
+		timeout = time.Duration(parkTime)
+	}
+
+	frame.GetThread().Park(timeout)
+}
+
+// Unparking a thread which has not started yet has no effect
+func unpark(frame *runtime_data_area.Frame) {
+	javaThread := frame.GetLocalVariables().GetReferenceValue(1)
+
+	if javaThread == nil {
+		return
+	}
+
+	thread, ok := javaThread.GetExtraData().(*runtime_data_area.Thread)
+
+	if ok {
+		thread.Unpark()
+	}
+}
+
+func monitorEnter(frame *runtime_data_area.Frame) {
+	frame.GetThread().EnterMonitor(getMonitorObject(frame))
+}
+
+func monitorExit(frame *runtime_data_area.Frame) {
+	if !frame.GetThread().ExitMonitor(getMonitorObject(frame)) {
+		panic(heap.NewJavaException("java/lang/IllegalMonitorStateException", "current thread is not owner"))
+	}
+}
+
+func tryMonitorEnter(frame *runtime_data_area.Frame) {
+	object := getMonitorObject(frame)
+
+	frame.GetOperandStack().PushBooleanValue(object.GetMonitor().TryEnter(frame.GetThread(), 1))
+}
+
+func getMonitorObject(frame *runtime_data_area.Frame) *heap.Object {
+	object := frame.GetLocalVariables().GetReferenceValue(1)
+
+	if object == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	return object
+}
+
+// Accessors taking a base object and an offset, or an absolute memory address
+func newGetIntegerValue(descriptor byte, hasBaseObject bool) native_methods.NativeMethod {
+	return func(frame *runtime_data_area.Frame) {
+		object, offset, _ := getAddress(frame, hasBaseObject)
+
+		frame.GetOperandStack().PushIntegerValue(getIntegerValue(object, offset, descriptor))
+	}
+}
+
+func newPutIntegerValue(descriptor byte, hasBaseObject bool) native_methods.NativeMethod {
+	return func(frame *runtime_data_area.Frame) {
+		object, offset, valueIndex := getAddress(frame, hasBaseObject)
+
+		setIntegerValue(object, offset, descriptor, frame.GetLocalVariables().GetIntegerValue(valueIndex))
+	}
+}
+
+func newGetLongValue(hasBaseObject bool) native_methods.NativeMethod {
+	return func(frame *runtime_data_area.Frame) {
+		object, offset, _ := getAddress(frame, hasBaseObject)
+
+		frame.GetOperandStack().PushLongValue(getLongValue(object, offset))
+	}
+}
+
+func newPutLongValue(hasBaseObject bool) native_methods.NativeMethod {
+	return func(frame *runtime_data_area.Frame) {
+		object, offset, valueIndex := getAddress(frame, hasBaseObject)
+
+		setLongValue(object, offset, frame.GetLocalVariables().GetLongValue(valueIndex))
+	}
+}
+
+func getObject(frame *runtime_data_area.Frame) {
+	object, offset, _ := getAddress(frame, true)
+
+	frame.GetOperandStack().PushReferenceValue(getReferenceValue(object, offset))
+}
+
+func putObject(frame *runtime_data_area.Frame) {
+	object, offset, valueIndex := getAddress(frame, true)
+
+	setReferenceValue(object, offset, frame.GetLocalVariables().GetReferenceValue(valueIndex))
+}
+
+// Returns the base object, the offset and the index of the value to store
+func getAddress(frame *runtime_data_area.Frame, hasBaseObject bool) (*heap.Object, int64, uint) {
+	localVariables := frame.GetLocalVariables()
+
+	if hasBaseObject {
+		return localVariables.GetReferenceValue(1), localVariables.GetLongValue(2), 4
+	}
+
+	return nil, localVariables.GetLongValue(1), 3
+}
+
+// Without a base object the offset is a memory address
+func getIntegerValue(object *heap.Object, offset int64, descriptor byte) int32 {
+	if object == nil || object.GetClass().IsArray() {
+		value := readValue(object, offset, getMemorySize(descriptor))
+
+		switch descriptor {
+		case 'Z':
+			if value != 0 {
+				return 1
+			}
+
+			return 0
+		case 'B':
+			return int32(int8(value))
+		case 'C':
+			return int32(uint16(value))
+		case 'S':
+			return int32(int16(value))
+		default:
+			return int32(uint32(value))
+		}
+	}
+
+	variables, index := getVariables(object, offset, 1)
+
+	return variables.GetIntegerValue(index)
+}
+
+func setIntegerValue(object *heap.Object, offset int64, descriptor byte, value int32) {
+	if object == nil || object.GetClass().IsArray() {
+		writeValue(object, offset, getMemorySize(descriptor), uint64(uint32(value)))
+
+		return
+	}
+
+	variables, index := getVariables(object, offset, 1)
+	variables.SetIntegerValue(index, value)
+}
+
+func getLongValue(object *heap.Object, offset int64) int64 {
+	if object == nil || object.GetClass().IsArray() {
+		return int64(readValue(object, offset, 8))
+	}
+
+	variables, index := getVariables(object, offset, 2)
+
+	return variables.GetLongValue(index)
+}
+
+func setLongValue(object *heap.Object, offset int64, value int64) {
+	if object == nil || object.GetClass().IsArray() {
+		writeValue(object, offset, 8, uint64(value))
+
+		return
+	}
+
+	variables, index := getVariables(object, offset, 2)
+	variables.SetLongValue(index, value)
+}
+
+func getReferenceValue(object *heap.Object, offset int64) *heap.Object {
+	if object == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if object.GetClass().IsArray() {
+		return object.GetReferenceArray()[getReferenceArrayIndex(object, offset)]
+	}
+
+	variables, index := getVariables(object, offset, 1)
+
+	return variables.GetReferenceValue(index)
+}
+
+func setReferenceValue(object *heap.Object, offset int64, value *heap.Object) {
+	if object == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if object.GetClass().IsArray() {
+		object.GetReferenceArray()[getReferenceArrayIndex(object, offset)] = value
+
+		return
+	}
+
+	variables, index := getVariables(object, offset, 1)
+	variables.SetReferenceValue(index, value)
+}
+
+// References are not stored as bytes, so only whole elements can be accessed
+func getReferenceArrayIndex(array *heap.Object, offset int64) int64 {
+	if !isReferenceArray(array) {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "Not a reference array"))
+	}
+
+	if offset < 0 || offset%referenceSize != 0 || offset/referenceSize >= int64(array.GetArrayLength()) {
+		panic(heap.NewJavaException("java/lang/ArrayIndexOutOfBoundsException", strconv.FormatInt(offset, 10)))
+	}
+
+	return offset / referenceSize
+}
+
+// Static field offsets are based on the java.lang.Class object of their class. Offsets that are
+// not of a field of the object fail like HotSpot fails a faulting access
+func getVariables(object *heap.Object, offset int64, slotsCount int64) (heap.Variables, uint) {
+	variables := object.GetFields()
+
+	if offset&heap.StaticFieldOffsetFlag != 0 {
+		class, ok := object.GetExtraData().(*heap.Class)
+
+		if !ok {
+			panic(heap.NewJavaException("java/lang/IllegalArgumentException", "Static field offset with a base that is not a class"))
+		}
+
+		variables = class.GetStaticVariables()
+		offset &^= heap.StaticFieldOffsetFlag
+	}
+
+	if offset < 0 || offset+slotsCount > int64(len(variables)) {
+		panic(heap.NewJavaException("java/lang/InternalError", "Field offset out of range: "+strconv.FormatInt(offset, 10)))
+	}
+
+	return variables, uint(offset)
+}
+
+func getArrayElementDescriptor(array *heap.Object) byte {
+	return array.GetClass().GetName()[1]
+}
@@ -0,0 +1,294 @@
+package misc
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Blocks from allocateMemory by address, only used while holding the interpreter lock
+var memoryBlocks = map[int64][]byte{}
+
+// Addresses of the blocks in ascending order, to find the block an address falls in
+var memoryBlockAddresses []int64
+
+// Leave address 0 free, it stands for null
+var nextMemoryAddress int64 = 0x10000
+
+func init() {
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "allocateMemory", "(J)J", allocateMemory)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "reallocateMemory", "(JJ)J", reallocateMemory)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "freeMemory", "(J)V", freeMemory)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "setMemory", "(Ljava/lang/Object;JJB)V", setMemory)
+	native_methods.RegisterNativeMethod(sunMiscUnsafe, "copyMemory", "(Ljava/lang/Object;JLjava/lang/Object;JJ)V", copyMemory)
+}
+
+func allocateMemory(frame *runtime_data_area.Frame) {
+	size := frame.GetLocalVariables().GetLongValue(1)
+
+	frame.GetOperandStack().PushLongValue(allocate(size))
+}
+
+func reallocateMemory(frame *runtime_data_area.Frame) {
+	address := frame.GetLocalVariables().GetLongValue(1)
+	size := frame.GetLocalVariables().GetLongValue(3)
+	newAddress := allocate(size)
+
+	if address != 0 {
+		copy(memoryBlocks[newAddress], memoryBlocks[address])
+		release(address)
+	}
+
+	frame.GetOperandStack().PushLongValue(newAddress)
+}
+
+func freeMemory(frame *runtime_data_area.Frame) {
+	release(frame.GetLocalVariables().GetLongValue(1))
+}
+
+func setMemory(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	object := localVariables.GetReferenceValue(1)
+	offset := localVariables.GetLongValue(2)
+	size := localVariables.GetLongValue(4)
+	value := localVariables.GetIntegerValue(6)
+
+	checkRange(object, offset, size)
+
+	for i := int64(0); i < size; i++ {
+		writeValue(object, offset+i, 1, uint64(value))
+	}
+}
+
+// Byte by byte, between memory and primitive arrays, the ranges may overlap
+func copyMemory(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	sourceObject := localVariables.GetReferenceValue(1)
+	sourceOffset := localVariables.GetLongValue(2)
+	targetObject := localVariables.GetReferenceValue(4)
+	targetOffset := localVariables.GetLongValue(5)
+	size := localVariables.GetLongValue(7)
+
+	checkRange(sourceObject, sourceOffset, size)
+	checkRange(targetObject, targetOffset, size)
+
+	bytes := make([]byte, size)
+
+	for i := range bytes {
+		bytes[i] = byte(readValue(sourceObject, sourceOffset+int64(i), 1))
+	}
+
+	for i, value := range bytes {
+		writeValue(targetObject, targetOffset+int64(i), 1, uint64(value))
+	}
+}
+
+func allocate(size int64) int64 {
+	if size < 0 {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", ""))
+	}
+
+	address := nextMemoryAddress
+	memoryBlocks[address] = make([]byte, size)
+	memoryBlockAddresses = append(memoryBlockAddresses, address)
+
+	// Keep blocks 8-byte aligned, with a gap so that empty blocks get distinct addresses
+	nextMemoryAddress += (size+7)&^7 + 8
+
+	return address
+}
+
+func release(address int64) {
+	if _, ok := memoryBlocks[address]; !ok {
+		return
+	}
+
+	i := sort.Search(len(memoryBlockAddresses), func(i int) bool {
+		return memoryBlockAddresses[i] >= address
+	})
+
+	memoryBlockAddresses = append(memoryBlockAddresses[:i], memoryBlockAddresses[i+1:]...)
+	delete(memoryBlocks, address)
+}
+
+func getMemorySize(descriptor byte) int64 {
+	switch descriptor {
+	case 'Z', 'B':
+		return 1
+	case 'C', 'S':
+		return 2
+	case 'J', 'D':
+		return 8
+	default:
+		return 4
+	}
+}
+
+// Values are stored little-endian, java.nio.Bits finds the byte order by reading them back
+func readMemory(address, size int64) uint64 {
+	memory := getMemory(address, size)
+	value := uint64(0)
+
+	for i := size - 1; i >= 0; i-- {
+		value = value<<8 | uint64(memory[i])
+	}
+
+	return value
+}
+
+func writeMemory(address, size int64, value uint64) {
+	memory := getMemory(address, size)
+
+	for i := int64(0); i < size; i++ {
+		memory[i] = byte(value >> uint(i*8))
+	}
+}
+
+func getMemory(address, size int64) []byte {
+	// The last block starting at or before the address
+	i := sort.Search(len(memoryBlockAddresses), func(i int) bool {
+		return memoryBlockAddresses[i] > address
+	}) - 1
+
+	if i >= 0 {
+		blockAddress := memoryBlockAddresses[i]
+		block := memoryBlocks[blockAddress]
+
+		if size >= 0 && address-blockAddress <= int64(len(block))-size {
+			return block[address-blockAddress : address-blockAddress+size]
+		}
+	}
+
+	panic(heap.NewJavaException("java/lang/InternalError", "Invalid memory access"))
+}
+
+// Without a base object the offset is a memory address, otherwise a byte offset in a primitive array
+func readValue(object *heap.Object, offset, size int64) uint64 {
+	if object == nil {
+		return readMemory(offset, size)
+	}
+
+	checkArrayRange(object, offset, size)
+
+	value := uint64(0)
+
+	for i := size - 1; i >= 0; i-- {
+		value = value<<8 | uint64(getArrayByte(object, offset+i))
+	}
+
+	return value
+}
+
+func writeValue(object *heap.Object, offset, size int64, value uint64) {
+	if object == nil {
+		writeMemory(offset, size, value)
+
+		return
+	}
+
+	checkArrayRange(object, offset, size)
+
+	for i := int64(0); i < size; i++ {
+		setArrayByte(object, offset+i, byte(value>>uint(i*8)))
+	}
+}
+
+func checkRange(object *heap.Object, offset, size int64) {
+	if size < 0 {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", ""))
+	}
+
+	if object == nil {
+		getMemory(offset, size)
+	} else {
+		checkArrayRange(object, offset, size)
+	}
+}
+
+func checkArrayRange(array *heap.Object, offset, size int64) {
+	if isReferenceArray(array) {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "Not a primitive array"))
+	}
+
+	arraySize := int64(array.GetArrayLength()) * getMemorySize(getArrayElementDescriptor(array))
+
+	if offset < 0 || offset > arraySize-size {
+		panic(heap.NewJavaException("java/lang/ArrayIndexOutOfBoundsException", strconv.FormatInt(offset, 10)))
+	}
+}
+
+// Elements are stored little-endian, the same as memory
+func getArrayByte(array *heap.Object, offset int64) byte {
+	elementSize := getMemorySize(getArrayElementDescriptor(array))
+	element := getArrayElement(array, offset/elementSize)
+
+	return byte(element >> uint(offset%elementSize*8))
+}
+
+func setArrayByte(array *heap.Object, offset int64, value byte) {
+	elementSize := getMemorySize(getArrayElementDescriptor(array))
+	index := offset / elementSize
+	shift := uint(offset % elementSize * 8)
+	element := getArrayElement(array, index)&^(0xff<<shift) | uint64(value)<<shift
+
+	setArrayElement(array, index, element)
+}
+
+func getArrayElement(array *heap.Object, index int64) uint64 {
+	switch getArrayElementDescriptor(array) {
+	case 'Z', 'B':
+		return uint64(uint8(array.GetByteArray()[index]))
+	case 'C':
+		return uint64(array.GetCharArray()[index])
+	case 'S':
+		return uint64(uint16(array.GetShortArray()[index]))
+	case 'I':
+		return uint64(uint32(array.GetIntArray()[index]))
+	case 'F':
+		return uint64(math.Float32bits(array.GetFloatArray()[index]))
+	case 'J':
+		return uint64(array.GetLongArray()[index])
+	default:
+		return math.Float64bits(array.GetDoubleArray()[index])
+	}
+}
+
+func setArrayElement(array *heap.Object, index int64, element uint64) {
+	switch getArrayElementDescriptor(array) {
+	case 'Z', 'B':
+		array.GetByteArray()[index] = int8(element)
+	case 'C':
+		array.GetCharArray()[index] = uint16(element)
+	case 'S':
+		array.GetShortArray()[index] = int16(element)
+	case 'I':
+		array.GetIntArray()[index] = int32(element)
+	case 'F':
+		array.GetFloatArray()[index] = math.Float32frombits(uint32(element))
+	case 'J':
+		array.GetLongArray()[index] = int64(element)
+	default:
+		array.GetDoubleArray()[index] = math.Float64frombits(element)
+	}
+}
+
+// Reported as the scale of reference arrays, as with compressed references
+const referenceSize = 4
+
+func getArrayIndexScale(descriptor byte) int64 {
+	if descriptor == 'L' || descriptor == '[' {
+		return referenceSize
+	}
+
+	return getMemorySize(descriptor)
+}
+
+func isReferenceArray(array *heap.Object) bool {
+	descriptor := getArrayElementDescriptor(array)
+
+	return descriptor == 'L' || descriptor == '['
+}
@@ -0,0 +1,129 @@
+package reflect
+
+import (
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+var wrapperClassNames = map[string]string{
+	"boolean": "java/lang/Boolean",
+	"byte":    "java/lang/Byte",
+	"char":    "java/lang/Character",
+	"short":   "java/lang/Short",
+	"int":     "java/lang/Integer",
+	"long":    "java/lang/Long",
+	"float":   "java/lang/Float",
+	"double":  "java/lang/Double",
+}
+
+var primitiveTypeDescriptors = map[string]string{
+	"boolean": "Z",
+	"byte":    "B",
+	"char":    "C",
+	"short":   "S",
+	"int":     "I",
+	"long":    "J",
+	"float":   "F",
+	"double":  "D",
+}
+
+// Widening primitive conversions allowed when unboxing arguments, JLS 5.1.2
+var wideningConversions = map[string][]string{
+	"byte":  {"short", "int", "long", "float", "double"},
+	"short": {"int", "long", "float", "double"},
+	"char":  {"int", "long", "float", "double"},
+	"int":   {"long", "float", "double"},
+	"long":  {"float", "double"},
+	"float": {"double"},
+}
+
+func isWideningConversion(sourceType, targetType string) bool {
+	if sourceType == targetType {
+		return true
+	}
+
+	for _, wideningType := range wideningConversions[sourceType] {
+		if wideningType == targetType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getPrimitiveType(wrapperClass *heap.Class) (string, bool) {
+	for primitiveType, wrapperClassName := range wrapperClassNames {
+		if wrapperClassName == wrapperClass.GetName() {
+			return primitiveType, true
+		}
+	}
+
+	return "", false
+}
+
+// Pushes the value of a wrapper object as the primitive type of a parameter
+func pushUnboxedValue(operandStack *runtime_data_area.OperandStack, primitiveType string, argument *heap.Object) {
+	if argument == nil {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "argument type mismatch"))
+	}
+
+	sourceType, ok := getPrimitiveType(argument.GetClass())
+
+	if !ok || !isWideningConversion(sourceType, primitiveType) {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "argument type mismatch"))
+	}
+
+	var integerValue int64
+	var floatingValue float64
+
+	switch sourceType {
+	case "long":
+		integerValue = argument.GetLongValue("value", "J")
+	case "float":
+		floatingValue = float64(argument.GetFloatValue("value", "F"))
+	case "double":
+		floatingValue = argument.GetDoubleValue("value", "D")
+	default:
+		integerValue = int64(argument.GetIntegerValue("value", primitiveTypeDescriptors[sourceType]))
+	}
+
+	isFloating := sourceType == "float" || sourceType == "double"
+
+	switch primitiveType {
+	case "long":
+		operandStack.PushLongValue(integerValue)
+	case "float":
+		if isFloating {
+			operandStack.PushFloatValue(float32(floatingValue))
+		} else {
+			operandStack.PushFloatValue(float32(integerValue))
+		}
+	case "double":
+		if isFloating {
+			operandStack.PushDoubleValue(floatingValue)
+		} else {
+			operandStack.PushDoubleValue(float64(integerValue))
+		}
+	default:
+		operandStack.PushIntegerValue(int32(integerValue))
+	}
+}
+
+// A new wrapper object, like HotSpot boxes results without going through valueOf
+func popBoxedValue(operandStack *runtime_data_area.OperandStack, wrapperClass *heap.Class, primitiveType string) *heap.Object {
+	wrapperObject := wrapperClass.NewObject()
+	descriptor := primitiveTypeDescriptors[primitiveType]
+
+	switch primitiveType {
+	case "long":
+		wrapperObject.SetLongValue("value", descriptor, operandStack.PopLongValue())
+	case "float":
+		wrapperObject.SetFloatValue("value", descriptor, operandStack.PopFloatValue())
+	case "double":
+		wrapperObject.SetDoubleValue("value", descriptor, operandStack.PopDoubleValue())
+	default:
+		wrapperObject.SetIntegerValue("value", descriptor, operandStack.PopIntegerValue())
+	}
+
+	return wrapperObject
+}
@@ -0,0 +1,125 @@
+package reflect
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+func init() {
+	// Moved to jdk.internal.reflect in Java 9
+	for _, className := range []string{"sun/reflect/ConstantPool", "jdk/internal/reflect/ConstantPool"} {
+		native_methods.RegisterNativeMethod(className, "getSize0", "(Ljava/lang/Object;)I", getSize0)
+		native_methods.RegisterNativeMethod(className, "getClassAt0", "(Ljava/lang/Object;I)Ljava/lang/Class;", getClassAt0)
+		native_methods.RegisterNativeMethod(className, "getIntAt0", "(Ljava/lang/Object;I)I", getIntAt0)
+		native_methods.RegisterNativeMethod(className, "getLongAt0", "(Ljava/lang/Object;I)J", getLongAt0)
+		native_methods.RegisterNativeMethod(className, "getFloatAt0", "(Ljava/lang/Object;I)F", getFloatAt0)
+		native_methods.RegisterNativeMethod(className, "getDoubleAt0", "(Ljava/lang/Object;I)D", getDoubleAt0)
+		native_methods.RegisterNativeMethod(className, "getStringAt0", "(Ljava/lang/Object;I)Ljava/lang/String;", getStringAt0)
+		native_methods.RegisterNativeMethod(className, "getUTF8At0", "(Ljava/lang/Object;I)Ljava/lang/String;", getUTF8At0)
+	}
+}
+
+// The constantPoolOop of a ConstantPool is the java.lang.Class object of the class it belongs to
+func getConstantPoolOf(frame *runtime_data_area.Frame) *heap.ConstantPool {
+	constantPoolOop := frame.GetLocalVariables().GetReferenceValue(1)
+
+	return constantPoolOop.GetExtraData().(*heap.Class).GetConstantPool()
+}
+
+func getConstantIndex(frame *runtime_data_area.Frame) int {
+	index := int(frame.GetLocalVariables().GetIntegerValue(2))
+
+	if index < 0 || index >= getConstantPoolOf(frame).GetSize() {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "Constant pool index out of bounds"))
+	}
+
+	return index
+}
+
+func getConstantAt(frame *runtime_data_area.Frame) heap.Constant {
+	return getConstantPoolOf(frame).LookupConstant(getConstantIndex(frame))
+}
+
+func panicWrongConstantType() {
+	panic(heap.NewJavaException("java/lang/IllegalArgumentException", "Wrong type at constant pool index"))
+}
+
+func getSize0(frame *runtime_data_area.Frame) {
+	frame.GetOperandStack().PushIntegerValue(int32(getConstantPoolOf(frame).GetSize()))
+}
+
+func getClassAt0(frame *runtime_data_area.Frame) {
+	classReference, ok := getConstantAt(frame).(*heap.ClassReference)
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	frame.GetOperandStack().PushReferenceValue(classReference.GetResolvedClass().GetJavaClass())
+}
+
+func getIntAt0(frame *runtime_data_area.Frame) {
+	value, ok := getConstantAt(frame).(int32)
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	frame.GetOperandStack().PushIntegerValue(value)
+}
+
+func getLongAt0(frame *runtime_data_area.Frame) {
+	value, ok := getConstantAt(frame).(int64)
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	frame.GetOperandStack().PushLongValue(value)
+}
+
+func getFloatAt0(frame *runtime_data_area.Frame) {
+	value, ok := getConstantAt(frame).(float32)
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	frame.GetOperandStack().PushFloatValue(value)
+}
+
+func getDoubleAt0(frame *runtime_data_area.Frame) {
+	value, ok := getConstantAt(frame).(float64)
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	frame.GetOperandStack().PushDoubleValue(value)
+}
+
+func getStringAt0(frame *runtime_data_area.Frame) {
+	value, ok := getConstantAt(frame).(string)
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, value))
+}
+
+// Annotations name their types, member names and string values by Utf8 constants
+func getUTF8At0(frame *runtime_data_area.Frame) {
+	value, ok := getConstantPoolOf(frame).LookupUtf8String(getConstantIndex(frame))
+
+	if !ok {
+		panicWrongConstantType()
+	}
+
+	classLoader := frame.GetMethod().GetClass().GetClassLoader()
+
+	frame.GetOperandStack().PushReferenceValue(heap.ConvertGoStringToJavaString(classLoader, value))
+}
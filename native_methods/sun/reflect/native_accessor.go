@@ -0,0 +1,214 @@
+package reflect
+
+import (
+	"github.com/Frederick-S/jvmgo/instructions/base_instructions"
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+func init() {
+	// Moved to jdk.internal.reflect in Java 9
+	for _, packageName := range []string{"sun/reflect/", "jdk/internal/reflect/"} {
+		native_methods.RegisterNativeMethod(packageName+"NativeMethodAccessorImpl", "invoke0", "(Ljava/lang/reflect/Method;Ljava/lang/Object;[Ljava/lang/Object;)Ljava/lang/Object;", invoke0)
+		native_methods.RegisterNativeMethod(packageName+"NativeConstructorAccessorImpl", "newInstance0", "(Ljava/lang/reflect/Constructor;[Ljava/lang/Object;)Ljava/lang/Object;", newInstance0)
+	}
+
+	reflectionShimMethod := heap.GetReflectionShimMethod()
+	native_methods.RegisterNativeMethod(reflectionShimMethod.GetClass().GetName(), reflectionShimMethod.GetName(), reflectionShimMethod.GetDescriptor(), completeReflectiveCall)
+}
+
+func invoke0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	methodObject := localVariables.GetReferenceValue(0)
+	this := localVariables.GetReferenceValue(1)
+	arguments := localVariables.GetReferenceValue(2)
+	method := getMethod(methodObject)
+	class := method.GetClass()
+
+	if method.IsStatic() {
+		if !class.IsInitializationStarted() {
+			frame.RevertNextPC()
+			base_instructions.InitializeClass(frame.GetThread(), class)
+
+			return
+		}
+
+		invokeReflectively(frame, method, methodObject, nil, arguments, nil)
+
+		return
+	}
+
+	if this == nil {
+		panic(heap.NewJavaException("java/lang/NullPointerException", ""))
+	}
+
+	if !this.IsInstanceOf(class) {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "object is not an instance of declaring class"))
+	}
+
+	invokeReflectively(frame, lookupVirtualMethod(this, method), methodObject, this, arguments, nil)
+}
+
+func newInstance0(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	constructorObject := localVariables.GetReferenceValue(0)
+	arguments := localVariables.GetReferenceValue(1)
+	constructor := getMethod(constructorObject)
+	class := constructor.GetClass()
+
+	if class.IsAbstract() {
+		panic(heap.NewJavaException("java/lang/InstantiationException", class.GetJavaName()))
+	}
+
+	if !class.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), class)
+
+		return
+	}
+
+	object := class.NewObject()
+
+	invokeReflectively(frame, constructor, constructorObject, object, arguments, object)
+}
+
+// Method and Constructor objects find their method by slot, see Class.getDeclaredMethods0
+func getMethod(executableObject *heap.Object) *heap.Method {
+	class := executableObject.GetReferenceValue("clazz", "Ljava/lang/Class;").GetExtraData().(*heap.Class)
+	slot := executableObject.GetIntegerValue("slot", "I")
+
+	return class.GetMethods()[slot]
+}
+
+// Instance methods other than private ones are dispatched on the class of the receiver
+func lookupVirtualMethod(this *heap.Object, method *heap.Method) *heap.Method {
+	if method.IsPrivate() {
+		return method
+	}
+
+	methodToBeInvoked := heap.LookupMethodInClass(this.GetClass(), method.GetName(), method.GetDescriptor())
+
+	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
+		methodToBeInvoked = heap.LookupDefaultMethod(this.GetClass(), method.GetName(), method.GetDescriptor())
+	}
+
+	if methodToBeInvoked == nil || methodToBeInvoked.IsAbstract() {
+		panic(heap.NewJavaException("java/lang/AbstractMethodError", this.GetClass().GetJavaName()+"."+method.GetName()+method.GetDescriptor()))
+	}
+
+	return methodToBeInvoked
+}
+
+// The reflection shim goes below the method, the method returns its result to the shim,
+// which boxes it and returns it as the result of the native
+func invokeReflectively(frame *runtime_data_area.Frame, method *heap.Method, executableObject, this, arguments, newObject *heap.Object) {
+	thread := frame.GetThread()
+	shimFrame := thread.NewFrame(heap.GetReflectionShimMethod())
+	shimFrame.SetNextPC(heap.ReflectionShimReturnPC)
+	shimFrame.GetLocalVariables().SetReferenceValue(0, executableObject)
+	shimFrame.GetLocalVariables().SetReferenceValue(1, newObject)
+
+	if this != nil {
+		shimFrame.GetOperandStack().PushReferenceValue(this)
+	}
+
+	pushArguments(shimFrame.GetOperandStack(), method, arguments)
+
+	thread.PushFrame(shimFrame)
+	base_instructions.InvokeMethod(shimFrame, method)
+}
+
+func pushArguments(operandStack *runtime_data_area.OperandStack, method *heap.Method, arguments *heap.Object) {
+	parameterTypes := method.GetParameterTypes()
+	argumentObjects := []*heap.Object{}
+
+	if arguments != nil {
+		argumentObjects = arguments.GetReferenceArray()
+	}
+
+	if len(argumentObjects) != len(parameterTypes) {
+		panic(heap.NewJavaException("java/lang/IllegalArgumentException", "wrong number of arguments"))
+	}
+
+	for i, parameterType := range parameterTypes {
+		argument := argumentObjects[i]
+
+		if parameterType.IsPrimitive() {
+			pushUnboxedValue(operandStack, parameterType.GetName(), argument)
+
+			continue
+		}
+
+		if argument != nil && !argument.IsInstanceOf(parameterType) {
+			panic(heap.NewJavaException("java/lang/IllegalArgumentException", "argument type mismatch"))
+		}
+
+		operandStack.PushReferenceValue(argument)
+	}
+}
+
+// Runs in the reflection shim, once the method returned or threw an exception
+func completeReflectiveCall(frame *runtime_data_area.Frame) {
+	if frame.GetNextPC() == heap.ReflectionShimHandlerPC+1 {
+		wrapException(frame)
+	} else {
+		boxResult(frame)
+	}
+}
+
+func boxResult(frame *runtime_data_area.Frame) {
+	localVariables := frame.GetLocalVariables()
+	method := getMethod(localVariables.GetReferenceValue(0))
+	operandStack := frame.GetOperandStack()
+
+	if method.IsConstructor() {
+		operandStack.PushReferenceValue(localVariables.GetReferenceValue(1))
+
+		return
+	}
+
+	returnType := method.GetReturnType()
+
+	if !returnType.IsPrimitive() {
+		return
+	}
+
+	if returnType.GetName() == "void" {
+		operandStack.PushReferenceValue(nil)
+
+		return
+	}
+
+	wrapperClass := returnType.GetClassLoader().LoadClass(wrapperClassNames[returnType.GetName()])
+
+	if !wrapperClass.IsInitializationStarted() {
+		frame.RevertNextPC()
+		base_instructions.InitializeClass(frame.GetThread(), wrapperClass)
+
+		return
+	}
+
+	operandStack.PushReferenceValue(popBoxedValue(operandStack, wrapperClass, returnType.GetName()))
+}
+
+// The exception thrown by the method becomes the cause of an InvocationTargetException
+func wrapException(frame *runtime_data_area.Frame) {
+	thread := frame.GetThread()
+	operandStack := frame.GetOperandStack()
+	exception := operandStack.PopReferenceValue()
+	classLoader := exception.GetClass().GetClassLoader()
+	invocationTargetExceptionClass := classLoader.LoadClass("java/lang/reflect/InvocationTargetException")
+	invocationTargetException := invocationTargetExceptionClass.NewObject()
+
+	operandStack.PushReferenceValue(invocationTargetException)
+
+	constructorFrame := thread.NewFrame(invocationTargetExceptionClass.GetConstructor("(Ljava/lang/Throwable;)V"))
+	constructorFrame.GetLocalVariables().SetReferenceValue(0, invocationTargetException)
+	constructorFrame.GetLocalVariables().SetReferenceValue(1, exception)
+	thread.PushFrame(constructorFrame)
+
+	if !invocationTargetExceptionClass.IsInitializationStarted() {
+		base_instructions.InitializeClass(thread, invocationTargetExceptionClass)
+	}
+}
@@ -0,0 +1,40 @@
+package reflect
+
+import (
+	"github.com/Frederick-S/jvmgo/native_methods"
+	"github.com/Frederick-S/jvmgo/runtime_data_area"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+func init() {
+	// Moved to jdk.internal.reflect in Java 9
+	for _, className := range []string{"sun/reflect/Reflection", "jdk/internal/reflect/Reflection"} {
+		native_methods.RegisterNativeMethod(className, "getCallerClass", "()Ljava/lang/Class;", getCallerClass)
+		native_methods.RegisterNativeMethod(className, "getClassAccessFlags", "(Ljava/lang/Class;)I", getClassAccessFlags)
+	}
+}
+
+// The frames are getCallerClass itself, the caller sensitive method, then its caller
+func getCallerClass(frame *runtime_data_area.Frame) {
+	callerFrames := []*runtime_data_area.Frame{}
+
+	for _, callerFrame := range frame.GetThread().GetFrames() {
+		if !callerFrame.GetMethod().IsShim() {
+			callerFrames = append(callerFrames, callerFrame)
+		}
+	}
+
+	if len(callerFrames) < 3 {
+		frame.GetOperandStack().PushReferenceValue(nil)
+
+		return
+	}
+
+	frame.GetOperandStack().PushReferenceValue(callerFrames[2].GetMethod().GetClass().GetJavaClass())
+}
+
+func getClassAccessFlags(frame *runtime_data_area.Frame) {
+	class := frame.GetLocalVariables().GetReferenceValue(0).GetExtraData().(*heap.Class)
+
+	frame.GetOperandStack().PushIntegerValue(int32(class.GetAccessFlags()))
+}
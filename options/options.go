@@ -0,0 +1,18 @@
+package options
+
+// Launcher options shared by the class loader, the interpreter and native methods
+var (
+	VerboseClass       bool
+	VerboseInstruction bool
+	ThreadStackSize    uint64 = 1024 * 1024
+	TargetRelease      int
+	SystemProperties   = map[string]string{}
+)
+
+// Restrictions on the files guest code can access through the java.io natives
+var (
+	FileSystemRoot         string
+	FileSystemReadOnly     bool
+	FileSystemAllowedPaths []string
+	FileSystemDeniedPaths  []string
+)
@@ -9,6 +9,8 @@ type Frame struct {
 	thread         *Thread
 	method         *heap.Method
 	nextPC         int
+	// Object locked for the duration of a synchronized method
+	monitorObject *heap.Object
 }
 
 func newFrame(thread *Thread, method *heap.Method) *Frame {
@@ -36,6 +38,11 @@ func (frame *Frame) GetThread() *Thread {
 	return frame.thread
 }
 
+// The frame of the caller, nil at the bottom of the stack
+func (frame *Frame) GetLowerFrame() *Frame {
+	return frame.lower
+}
+
 func (frame *Frame) GetNextPC() int {
 	return frame.nextPC
 }
@@ -47,3 +54,20 @@ func (frame *Frame) SetNextPC(nextPC int) {
 func (frame *Frame) RevertNextPC() {
 	frame.nextPC = frame.thread.pc
 }
+
+// Synchronized methods lock their class or receiver until the frame is popped
+func (frame *Frame) EnterMethodMonitor() {
+	method := frame.method
+
+	if !method.IsSynchronized() {
+		return
+	}
+
+	if method.IsStatic() {
+		frame.monitorObject = method.GetClass().GetJavaClass()
+	} else {
+		frame.monitorObject = frame.localVariables.GetThis()
+	}
+
+	frame.thread.EnterMonitor(frame.monitorObject)
+}
@@ -17,22 +17,22 @@ func (class *Class) NewArray(length uint) *Object {
 
 	switch class.name {
 	case "[Z":
-		return &Object{class, make([]int8, length), nil}
+		return &Object{class, make([]int8, length), nil, nil}
 	case "[B":
-		return &Object{class, make([]int8, length), nil}
+		return &Object{class, make([]int8, length), nil, nil}
 	case "[C":
-		return &Object{class, make([]uint16, length), nil}
+		return &Object{class, make([]uint16, length), nil, nil}
 	case "[S":
-		return &Object{class, make([]int16, length), nil}
+		return &Object{class, make([]int16, length), nil, nil}
 	case "[I":
-		return &Object{class, make([]int32, length), nil}
+		return &Object{class, make([]int32, length), nil, nil}
 	case "[J":
-		return &Object{class, make([]int64, length), nil}
+		return &Object{class, make([]int64, length), nil, nil}
 	case "[F":
-		return &Object{class, make([]float32, length), nil}
+		return &Object{class, make([]float32, length), nil, nil}
 	case "[D":
-		return &Object{class, make([]float64, length), nil}
+		return &Object{class, make([]float64, length), nil, nil}
 	default:
-		return &Object{class, make([]*Object, length), nil}
+		return &Object{class, make([]*Object, length), nil, nil}
 	}
 }
@@ -77,3 +77,18 @@ func CopyArray(sourceArray, targetArray *Object, sourceArrayPosition, targetArra
 		panic("Not array!")
 	}
 }
+
+// Nil stays nil, like an attribute the class file does not have
+func ConvertGoBytesToJavaBytes(classLoader *ClassLoader, goBytes []byte) *Object {
+	if goBytes == nil {
+		return nil
+	}
+
+	javaBytes := classLoader.LoadClass("[B").NewArray(uint(len(goBytes)))
+
+	for i, value := range goBytes {
+		javaBytes.GetByteArray()[i] = int8(value)
+	}
+
+	return javaBytes
+}
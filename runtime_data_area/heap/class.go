@@ -7,6 +7,7 @@ import (
 )
 
 type Class struct {
+	majorVersion            uint16
 	accessFlags             uint16
 	name                    string
 	superClassName          string
@@ -15,6 +16,11 @@ type Class struct {
 	fields                  []*Field
 	methods                 []*Method
 	sourceFileName          string
+	bootstrapMethods        []*classfile.BootstrapMethod
+	annotations             []byte
+	typeAnnotations         []byte
+	isInnerClass            bool
+	innerClassAccessFlags   uint16
 	classLoader             *ClassLoader
 	superClass              *Class
 	interfaces              []*Class
@@ -22,11 +28,13 @@ type Class struct {
 	staticVariablesCount    uint
 	staticVariables         Variables
 	isInitializationStarted bool
+	isTrusted               bool
 	javaClass               *Object
 }
 
 func newClass(classFile *classfile.ClassFile) *Class {
 	class := &Class{}
+	class.majorVersion = classFile.GetMajorVersion()
 	class.accessFlags = classFile.GetAccessFlags()
 	class.name = classFile.GetClassName()
 	class.superClassName = classFile.GetSuperClassName()
@@ -35,6 +43,10 @@ func newClass(classFile *classfile.ClassFile) *Class {
 	class.fields = newFields(class, classFile.GetFields())
 	class.methods = newMethods(class, classFile.GetMethods())
 	class.sourceFileName = getSourceFileName(classFile)
+	class.bootstrapMethods = getBootstrapMethods(classFile)
+	class.annotations = getAnnotations(classFile)
+	class.typeAnnotations = getTypeAnnotations(classFile)
+	class.isInnerClass, class.innerClassAccessFlags = getInnerClassAccessFlags(classFile)
 
 	return class
 }
@@ -49,10 +61,83 @@ func getSourceFileName(classFile *classfile.ClassFile) string {
 	return "Unknown"
 }
 
+func getBootstrapMethods(classFile *classfile.ClassFile) []*classfile.BootstrapMethod {
+	bootstrapMethodsAttribute := classFile.GetBootstrapMethodsAttribute()
+
+	if bootstrapMethodsAttribute != nil {
+		return bootstrapMethodsAttribute.GetBootstrapMethods()
+	}
+
+	return nil
+}
+
+// Raw RuntimeVisibleAnnotations, reflection parses them in Java
+func getAnnotations(classFile *classfile.ClassFile) []byte {
+	annotationsAttribute := classFile.GetRuntimeVisibleAnnotationsAttribute()
+
+	if annotationsAttribute != nil {
+		return annotationsAttribute.GetData()
+	}
+
+	return nil
+}
+
+func getTypeAnnotations(classFile *classfile.ClassFile) []byte {
+	typeAnnotationsAttribute := classFile.GetRuntimeVisibleTypeAnnotationsAttribute()
+
+	if typeAnnotationsAttribute != nil {
+		return typeAnnotationsAttribute.GetData()
+	}
+
+	return nil
+}
+
+// Nested classes are private, protected or static in the InnerClasses entry naming them
+func getInnerClassAccessFlags(classFile *classfile.ClassFile) (bool, uint16) {
+	innerClassesAttribute := classFile.GetInnerClassesAttribute()
+
+	if innerClassesAttribute == nil {
+		return false, 0
+	}
+
+	for _, innerClassEntry := range innerClassesAttribute.GetClasses() {
+		if classFile.GetConstantPool().GetClassName(innerClassEntry.GetInnerClassInfoIndex()) == classFile.GetClassName() {
+			return true, innerClassEntry.GetInnerClassAccessFlags()
+		}
+	}
+
+	return false, 0
+}
+
 func (class *Class) GetName() string {
 	return class.name
 }
 
+func (class *Class) GetAccessFlags() uint16 {
+	return class.accessFlags
+}
+
+// The access flags of the class as declared in the source, see JVM_GetClassModifiers
+func (class *Class) GetModifiers() uint16 {
+	if class.isInnerClass {
+		return class.innerClassAccessFlags
+	}
+
+	return class.accessFlags
+}
+
+func (class *Class) GetDescriptor() string {
+	return convertClassNameToDescriptor(class.name)
+}
+
+func (class *Class) GetFields() []*Field {
+	return class.fields
+}
+
+func (class *Class) GetMethods() []*Method {
+	return class.methods
+}
+
 func (class *Class) GetConstantPool() *ConstantPool {
 	return class.constantPool
 }
@@ -61,6 +146,18 @@ func (class *Class) GetSuperClass() *Class {
 	return class.superClass
 }
 
+func (class *Class) GetInterfaces() []*Class {
+	return class.interfaces
+}
+
+func (class *Class) GetAnnotations() []byte {
+	return class.annotations
+}
+
+func (class *Class) GetTypeAnnotations() []byte {
+	return class.typeAnnotations
+}
+
 func (class *Class) GetSourceFileName() string {
 	return class.sourceFileName
 }
@@ -236,6 +333,16 @@ func (class *Class) GetStaticMethod(methodName, descriptor string) *Method {
 	return nil
 }
 
+func (class *Class) GetConstructor(descriptor string) *Method {
+	for _, method := range class.methods {
+		if !method.IsStatic() && method.name == "<init>" && method.descriptor == descriptor {
+			return method
+		}
+	}
+
+	return nil
+}
+
 func (class *Class) GetInstanceMethod(methodName, methodDescriptor string) *Method {
 	return class.GetMethod(methodName, methodDescriptor, false)
 }
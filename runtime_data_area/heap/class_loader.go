@@ -2,9 +2,11 @@ package heap
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Frederick-S/jvmgo/classfile"
 	"github.com/Frederick-S/jvmgo/classpath"
+	"github.com/Frederick-S/jvmgo/options"
 )
 
 type ClassLoader struct {
@@ -24,6 +26,10 @@ func NewClassLoader(classFinder *classpath.ClassFinder) *ClassLoader {
 	return classLoader
 }
 
+func (classLoader *ClassLoader) GetClassFinder() *classpath.ClassFinder {
+	return classLoader.classFinder
+}
+
 func (classLoader *ClassLoader) loadBasicClasses() {
 	javaClassClass := classLoader.LoadClass("java/lang/Class")
 
@@ -68,14 +74,86 @@ func (classLoader *ClassLoader) LoadClass(className string) *Class {
 		class = classLoader.LoadNonArrayClass(className)
 	}
 
+	classLoader.createJavaClass(class)
+
+	return class
+}
+
+// Load a class the way Class.forName does: a class missing from the class path is a
+// ClassNotFoundException, instead of the NoClassDefFoundError of resolving a reference
+func (classLoader *ClassLoader) FindClass(className string) *Class {
+	if !isValidClassName(className) {
+		panic(NewJavaException("java/lang/ClassNotFoundException", strings.Replace(className, "/", ".", -1)))
+	}
+
+	// Array classes are created without loading their element class
+	elementClassName := strings.TrimLeft(className, "[")
+
+	if elementClassName != className && elementClassName[0] == 'L' {
+		classLoader.FindClass(elementClassName[1 : len(elementClassName)-1])
+	}
+
+	defer func() {
+		r := recover()
+
+		if r != nil && isClassNotFound(r, className) {
+			javaException := NewJavaException("java/lang/ClassNotFoundException", strings.Replace(className, "/", ".", -1))
+			javaException.SetCause(r.(*JavaException).cause)
+
+			panic(javaException)
+		}
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	return classLoader.LoadClass(className)
+}
+
+// Primitive types have classes but no names to look them up by
+func isValidClassName(className string) bool {
+	if className == "" {
+		return false
+	}
+
+	if _, ok := primitiveTypes[className]; ok {
+		return false
+	}
+
+	elementClassName := strings.TrimLeft(className, "[")
+
+	if elementClassName == className {
+		return true
+	}
+
+	if elementClassName[0] == 'L' {
+		return len(elementClassName) > 2 && strings.HasSuffix(elementClassName, ";")
+	}
+
+	return len(elementClassName) == 1 && elementClassName != "V" && strings.Contains("ZBCSIJFD", elementClassName)
+}
+
+// Only the class itself being missing counts, not one of its super classes
+func isClassNotFound(r interface{}, className string) bool {
+	javaException, ok := r.(*JavaException)
+
+	if !ok || javaException.className != "java/lang/NoClassDefFoundError" {
+		return false
+	}
+
+	classNotFoundError, ok := javaException.cause.(*classpath.ClassNotFoundError)
+
+	return ok && classNotFoundError.GetClassName() == className
+}
+
+func (classLoader *ClassLoader) createJavaClass(class *Class) {
 	javaClassClass, ok := classLoader.loadedClasses["java/lang/Class"]
 
 	if ok {
 		class.javaClass = javaClassClass.NewObject()
 		class.javaClass.extraData = class
 	}
-
-	return class
 }
 
 func (classLoader *ClassLoader) LoadArrayClass(className string) *Class {
@@ -99,10 +177,13 @@ func (classLoader *ClassLoader) LoadArrayClass(className string) *Class {
 func (classLoader *ClassLoader) LoadNonArrayClass(className string) *Class {
 	classData, classpathEntry := classLoader.ReadClass(className)
 	class := classLoader.DefineClass(classData)
+	class.isTrusted = classLoader.classFinder.IsSystemClasspathEntry(classpathEntry)
 
-	linkClass(class)
+	classLoader.linkClass(class)
 
-	fmt.Printf("[Loaded class %s from %s]\n", className, classpathEntry)
+	if options.VerboseClass {
+		fmt.Printf("[Loaded %s from %s]\n", class.GetJavaName(), classpathEntry.ToString())
+	}
 
 	return class
 }
@@ -111,7 +192,10 @@ func (classLoader *ClassLoader) ReadClass(className string) ([]byte, classpath.C
 	classData, classpathEntry, err := classLoader.classFinder.ReadClass(className)
 
 	if err != nil {
-		panic("java.lang.ClassNotFoundException: " + className)
+		javaException := NewJavaException("java/lang/NoClassDefFoundError", className)
+		javaException.SetCause(err)
+
+		panic(javaException)
 	}
 
 	return classData, classpathEntry
@@ -119,20 +203,49 @@ func (classLoader *ClassLoader) ReadClass(className string) ([]byte, classpath.C
 
 func (classLoader *ClassLoader) DefineClass(classData []byte) *Class {
 	class := parseClassData(classData)
+	classLoader.addClass(class)
+
+	return class
+}
+
+// Classes generated at run time, like the proxy classes of java.lang.reflect.Proxy
+func (classLoader *ClassLoader) DefineGeneratedClass(className string, classData []byte) *Class {
+	class := parseClassData(classData)
+
+	if class.name != className {
+		panic(NewJavaException("java/lang/NoClassDefFoundError", fmt.Sprintf("%s (wrong name: %s)", className, class.name)))
+	}
+
+	if _, ok := classLoader.loadedClasses[className]; ok {
+		panic(NewJavaException("java/lang/LinkageError", "duplicate class definition: "+className))
+	}
+
+	classLoader.addClass(class)
+	classLoader.linkClass(class)
+	classLoader.createJavaClass(class)
+
+	return class
+}
+
+func (classLoader *ClassLoader) addClass(class *Class) {
 	class.classLoader = classLoader
 
 	resolveSuperClass(class)
 	resolveInterfaces(class)
 
 	classLoader.loadedClasses[class.name] = class
-
-	return class
 }
 
 func parseClassData(classData []byte) *Class {
 	classFile, err := classfile.Parse(classData)
 
 	if err != nil {
+		formatError, ok := err.(*classfile.FormatError)
+
+		if ok {
+			panic(NewJavaException(formatError.GetExceptionClassName(), formatError.Error()))
+		}
+
 		panic(err)
 	}
 
@@ -157,12 +270,80 @@ func resolveInterfaces(class *Class) {
 	}
 }
 
-func linkClass(class *Class) {
-	verifyClass(class)
-	prepareClass(class)
+// A class that fails verification must not stay loaded, or loading it again would hand out the
+// unverified class. Neither may the classes loaded while verifying it which extend it
+func (classLoader *ClassLoader) linkClass(class *Class) {
+	if class.isTrusted {
+		linkClass(class)
+
+		return
+	}
+
+	previouslyLoadedClasses := make(map[string]bool, len(classLoader.loadedClasses))
+
+	for className := range classLoader.loadedClasses {
+		previouslyLoadedClasses[className] = true
+	}
+
+	defer func() {
+		r := recover()
+
+		if r != nil {
+			delete(classLoader.loadedClasses, class.name)
+			classLoader.unloadDependentClasses(class, previouslyLoadedClasses)
+
+			panic(r)
+		}
+	}()
+
+	linkClass(class)
+}
+
+func (classLoader *ClassLoader) unloadDependentClasses(unloadedClass *Class, previouslyLoadedClasses map[string]bool) {
+	unloadedClasses := map[*Class]bool{unloadedClass: true}
+	isUnloading := true
+
+	for isUnloading {
+		isUnloading = false
+
+		for className, class := range classLoader.loadedClasses {
+			if !previouslyLoadedClasses[className] && dependsOnAny(class, unloadedClasses) {
+				delete(classLoader.loadedClasses, className)
+				unloadedClasses[class] = true
+				isUnloading = true
+			}
+		}
+	}
+}
+
+func dependsOnAny(class *Class, classes map[*Class]bool) bool {
+	if classes[class.superClass] {
+		return true
+	}
+
+	for _, interfaceClass := range class.interfaces {
+		if classes[interfaceClass] {
+			return true
+		}
+	}
+
+	if class.IsArray() {
+		elementClassName := strings.TrimLeft(class.name, "[")
+
+		for otherClass := range classes {
+			if elementClassName == "L"+otherClass.name+";" {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
-func verifyClass(class *Class) {
+// Fields are laid out first, verifying may load subclasses whose layout builds on this one
+func linkClass(class *Class) {
+	prepareClass(class)
+	verifyClass(class)
 }
 
 func prepareClass(class *Class) {
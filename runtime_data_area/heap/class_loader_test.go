@@ -0,0 +1,244 @@
+package heap
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Frederick-S/jvmgo/classpath"
+)
+
+// Assembles the few class files the tests need, with a single Code attribute per method
+type testClassFile struct {
+	name            string
+	superClassName  string
+	constants       [][]byte
+	utf8Indices     map[string]uint16
+	fields          [][]byte
+	methods         [][]byte
+	majorVersion    uint16
+	thisClassIndex  uint16
+	superClassIndex uint16
+}
+
+func newTestClassFile(name, superClassName string) *testClassFile {
+	classFile := &testClassFile{
+		name:           name,
+		superClassName: superClassName,
+		utf8Indices:    map[string]uint16{},
+		majorVersion:   52,
+	}
+
+	classFile.thisClassIndex = classFile.addClass(name)
+	classFile.superClassIndex = classFile.addClass(superClassName)
+
+	return classFile
+}
+
+func (classFile *testClassFile) addConstant(constant []byte) uint16 {
+	classFile.constants = append(classFile.constants, constant)
+
+	return uint16(len(classFile.constants))
+}
+
+func (classFile *testClassFile) addUtf8(value string) uint16 {
+	index, ok := classFile.utf8Indices[value]
+
+	if !ok {
+		constant := append([]byte{1}, uint16Bytes(uint16(len(value)))...)
+		index = classFile.addConstant(append(constant, value...))
+		classFile.utf8Indices[value] = index
+	}
+
+	return index
+}
+
+func (classFile *testClassFile) addClass(name string) uint16 {
+	return classFile.addConstant(append([]byte{7}, uint16Bytes(classFile.addUtf8(name))...))
+}
+
+func (classFile *testClassFile) addMethodReference(className, name, descriptor string) uint16 {
+	classIndex := classFile.addClass(className)
+	nameAndTypeIndex := classFile.addConstant(concatBytes([]byte{12}, uint16Bytes(classFile.addUtf8(name)), uint16Bytes(classFile.addUtf8(descriptor))))
+
+	return classFile.addConstant(concatBytes([]byte{10}, uint16Bytes(classIndex), uint16Bytes(nameAndTypeIndex)))
+}
+
+func (classFile *testClassFile) addField(accessFlags uint16, name, descriptor string) {
+	classFile.fields = append(classFile.fields, concatBytes(uint16Bytes(accessFlags),
+		uint16Bytes(classFile.addUtf8(name)), uint16Bytes(classFile.addUtf8(descriptor)), uint16Bytes(0)))
+}
+
+func (classFile *testClassFile) addMethod(accessFlags uint16, name, descriptor string, maxStack, maxLocals uint16, code ...byte) {
+	codeAttribute := concatBytes(uint16Bytes(maxStack), uint16Bytes(maxLocals), uint32Bytes(uint32(len(code))), code, uint16Bytes(0), uint16Bytes(0))
+
+	classFile.methods = append(classFile.methods, concatBytes(uint16Bytes(accessFlags),
+		uint16Bytes(classFile.addUtf8(name)), uint16Bytes(classFile.addUtf8(descriptor)), uint16Bytes(1),
+		uint16Bytes(classFile.addUtf8("Code")), uint32Bytes(uint32(len(codeAttribute))), codeAttribute))
+}
+
+func (classFile *testClassFile) toBytes() []byte {
+	data := concatBytes(uint32Bytes(0xCAFEBABE), uint16Bytes(0), uint16Bytes(classFile.majorVersion), uint16Bytes(uint16(len(classFile.constants)+1)))
+
+	for _, constant := range classFile.constants {
+		data = append(data, constant...)
+	}
+
+	data = concatBytes(data, uint16Bytes(ACC_PUBLIC|ACC_SUPER), uint16Bytes(classFile.thisClassIndex), uint16Bytes(classFile.superClassIndex), uint16Bytes(0))
+	data = append(data, uint16Bytes(uint16(len(classFile.fields)))...)
+
+	for _, field := range classFile.fields {
+		data = append(data, field...)
+	}
+
+	data = append(data, uint16Bytes(uint16(len(classFile.methods)))...)
+
+	for _, method := range classFile.methods {
+		data = append(data, method...)
+	}
+
+	return append(data, uint16Bytes(0)...)
+}
+
+func uint16Bytes(value uint16) []byte {
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, value)
+
+	return data
+}
+
+func uint32Bytes(value uint32) []byte {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, value)
+
+	return data
+}
+
+func concatBytes(parts ...[]byte) []byte {
+	data := []byte{}
+
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+
+	return data
+}
+
+// A loader reading the class files from a temporary user class path, java.lang.Object is a stub
+func newTestClassLoader(t *testing.T, classFiles ...*testClassFile) *ClassLoader {
+	directory := t.TempDir()
+
+	jreDirectory := filepath.Join(directory, "jre")
+	classesDirectory := filepath.Join(directory, "classes")
+
+	for _, path := range []string{filepath.Join(jreDirectory, "lib"), classesDirectory} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, classFile := range classFiles {
+		if err := ioutil.WriteFile(filepath.Join(classesDirectory, classFile.name+".class"), classFile.toBytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	classFinder, err := classpath.Parse(jreDirectory, classesDirectory)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	classLoader := &ClassLoader{
+		classFinder:   classFinder,
+		loadedClasses: map[string]*Class{},
+	}
+
+	classLoader.loadedClasses["java/lang/Object"] = &Class{
+		accessFlags:             ACC_PUBLIC,
+		name:                    "java/lang/Object",
+		classLoader:             classLoader,
+		isInitializationStarted: true,
+	}
+
+	return classLoader
+}
+
+// Fails the test unless loading panics with a Java exception of the class
+func loadClassExpectingException(t *testing.T, classLoader *ClassLoader, className, exceptionClassName string) {
+	defer func() {
+		r := recover()
+		javaException, ok := r.(*JavaException)
+
+		if !ok || javaException.GetClassName() != exceptionClassName {
+			t.Fatalf("loading %s: expected %s, got %v", className, exceptionClassName, r)
+		}
+	}()
+
+	classLoader.LoadClass(className)
+}
+
+// A's make method returns a B, so verifying A loads its subclass B, whose fields go after A's
+func newParentAndChildClassFiles() (*testClassFile, *testClassFile) {
+	parentClassFile := newTestClassFile("A", "java/lang/Object")
+	parentClassFile.addField(ACC_PRIVATE, "a", "I")
+
+	constructorIndex := parentClassFile.addMethodReference("B", "<init>", "()V")
+	childClassIndex := parentClassFile.addClass("B")
+
+	// new B, dup, invokespecial B.<init>, areturn
+	parentClassFile.addMethod(ACC_PUBLIC|ACC_STATIC, "make", "()LA;", 2, 0,
+		0xbb, byte(childClassIndex>>8), byte(childClassIndex),
+		0x59,
+		0xb7, byte(constructorIndex>>8), byte(constructorIndex),
+		0xb0)
+
+	childClassFile := newTestClassFile("B", "A")
+	childClassFile.addField(ACC_PRIVATE, "b", "I")
+
+	return parentClassFile, childClassFile
+}
+
+func TestLinkClassLaysOutFieldsBeforeVerifying(t *testing.T) {
+	parentClassFile, childClassFile := newParentAndChildClassFiles()
+	classLoader := newTestClassLoader(t, parentClassFile, childClassFile)
+
+	parentClass := classLoader.LoadClass("A")
+	childClass, ok := classLoader.loadedClasses["B"]
+
+	if !ok {
+		t.Fatal("verifying A did not load B")
+	}
+
+	parentField := parentClass.GetField("a", "I", false)
+	childField := childClass.GetField("b", "I", false)
+
+	if parentField.GetVariableIndex() == childField.GetVariableIndex() {
+		t.Errorf("fields of A and B share variable index %d", childField.GetVariableIndex())
+	}
+
+	if childClass.instanceVariablesCount != 2 {
+		t.Errorf("B has %d instance variables, expected 2", childClass.instanceVariablesCount)
+	}
+}
+
+func TestLinkClassUnloadsClassesLoadedForAFailedVerification(t *testing.T) {
+	parentClassFile, childClassFile := newParentAndChildClassFiles()
+
+	// aconst_null, ireturn
+	parentClassFile.addMethod(ACC_PUBLIC|ACC_STATIC, "broken", "()I", 1, 0, 0x01, 0xac)
+
+	classLoader := newTestClassLoader(t, parentClassFile, childClassFile)
+
+	loadClassExpectingException(t, classLoader, "A", "java/lang/VerifyError")
+
+	for _, className := range []string{"A", "B"} {
+		if _, ok := classLoader.loadedClasses[className]; ok {
+			t.Errorf("%s stayed loaded after A failed verification", className)
+		}
+	}
+
+	loadClassExpectingException(t, classLoader, "A", "java/lang/VerifyError")
+}
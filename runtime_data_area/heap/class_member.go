@@ -7,12 +7,27 @@ type ClassMember struct {
 	name        string
 	descriptor  string
 	class       *Class
+	// Raw RuntimeVisibleAnnotations and RuntimeVisibleTypeAnnotations, reflection parses them in Java
+	annotations     []byte
+	typeAnnotations []byte
 }
 
 func (classMember *ClassMember) copyMemberInfo(memberInfo *classfile.MemberInfo) {
 	classMember.accessFlags = memberInfo.GetAccessFlags()
 	classMember.name = memberInfo.GetName()
 	classMember.descriptor = memberInfo.GetDescriptor()
+
+	annotationsAttribute := memberInfo.GetRuntimeVisibleAnnotationsAttribute()
+
+	if annotationsAttribute != nil {
+		classMember.annotations = annotationsAttribute.GetData()
+	}
+
+	typeAnnotationsAttribute := memberInfo.GetRuntimeVisibleTypeAnnotationsAttribute()
+
+	if typeAnnotationsAttribute != nil {
+		classMember.typeAnnotations = typeAnnotationsAttribute.GetData()
+	}
 }
 
 func (classMember *ClassMember) IsPublic() bool {
@@ -39,6 +54,10 @@ func (classMember *ClassMember) IsSynthetic() bool {
 	return classMember.accessFlags&ACC_SYNTHETIC != 0
 }
 
+func (classMember *ClassMember) GetAccessFlags() uint16 {
+	return classMember.accessFlags
+}
+
 func (classMember *ClassMember) GetName() string {
 	return classMember.name
 }
@@ -47,6 +66,14 @@ func (classMember *ClassMember) GetDescriptor() string {
 	return classMember.descriptor
 }
 
+func (classMember *ClassMember) GetAnnotations() []byte {
+	return classMember.annotations
+}
+
+func (classMember *ClassMember) GetTypeAnnotations() []byte {
+	return classMember.typeAnnotations
+}
+
 func (classMember *ClassMember) GetClass() *Class {
 	return classMember.class
 }
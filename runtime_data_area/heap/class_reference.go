@@ -13,3 +13,13 @@ func newClassReference(constantPool *ConstantPool, constantClassInfo *classfile.
 
 	return classReference
 }
+
+// Already resolved reference, for constant pools the VM builds itself
+func newResolvedClassReference(constantPool *ConstantPool, class *Class) *ClassReference {
+	classReference := &ClassReference{}
+	classReference.constantPool = constantPool
+	classReference.className = class.name
+	classReference.class = class
+
+	return classReference
+}
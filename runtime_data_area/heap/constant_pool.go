@@ -3,6 +3,9 @@ package heap
 import "github.com/Frederick-S/jvmgo/classfile"
 import "fmt"
 
+// Utf8 entries are only read by reflection, a type of their own keeps ldc from taking them for strings
+type utf8Constant string
+
 type ConstantPool struct {
 	class     *Class
 	constants []Constant
@@ -30,6 +33,8 @@ func newConstantPool(class *Class, classFileConstantPool classfile.ConstantPool)
 			constants[i] = constantInfo.(*classfile.ConstantDoubleInfo).GetValue()
 		case *classfile.ConstantStringReferenceInfo:
 			constants[i] = constantInfo.(*classfile.ConstantStringReferenceInfo).GetString()
+		case *classfile.ConstantUtf8StringInfo:
+			constants[i] = utf8Constant(constantInfo.(*classfile.ConstantUtf8StringInfo).GetValue())
 		case *classfile.ConstantClassInfo:
 			constants[i] = newClassReference(constantPool, constantInfo.(*classfile.ConstantClassInfo))
 		case *classfile.ConstantFieldReferenceInfo:
@@ -38,6 +43,14 @@ func newConstantPool(class *Class, classFileConstantPool classfile.ConstantPool)
 			constants[i] = newMethodReference(constantPool, constantInfo.(*classfile.ConstantMethodReferenceInfo))
 		case *classfile.ConstantInterfaceMethodReferenceInfo:
 			constants[i] = newInterfaceMethodReference(constantPool, constantInfo.(*classfile.ConstantInterfaceMethodReferenceInfo))
+		case *classfile.ConstantMethodHandleInfo:
+			constants[i] = newMethodHandleReference(constantPool, constantInfo.(*classfile.ConstantMethodHandleInfo))
+		case *classfile.ConstantMethodTypeInfo:
+			constants[i] = newMethodTypeReference(constantInfo.(*classfile.ConstantMethodTypeInfo))
+		case *classfile.ConstantDynamicInfo:
+			constants[i] = newDynamicConstantReference(constantPool, constantInfo.(*classfile.ConstantDynamicInfo))
+		case *classfile.ConstantInvokeDynamicInfo:
+			constants[i] = newInvokeDynamicReference(constantPool, constantInfo.(*classfile.ConstantInvokeDynamicInfo))
 		default:
 			// pass
 		}
@@ -55,3 +68,22 @@ func (constantPool *ConstantPool) GetConstant(index uint) Constant {
 
 	panic(fmt.Sprintf("No constant at index %d", index))
 }
+
+func (constantPool *ConstantPool) GetSize() int {
+	return len(constantPool.constants)
+}
+
+// Nil for indices out of range and the entries that hold no constant
+func (constantPool *ConstantPool) LookupConstant(index int) Constant {
+	if index < 0 || index >= len(constantPool.constants) {
+		return nil
+	}
+
+	return constantPool.constants[index]
+}
+
+func (constantPool *ConstantPool) LookupUtf8String(index int) (string, bool) {
+	utf8String, ok := constantPool.LookupConstant(index).(utf8Constant)
+
+	return string(utf8String), ok
+}
@@ -0,0 +1,36 @@
+package heap
+
+import "github.com/Frederick-S/jvmgo/classfile"
+
+type DynamicConstantReference struct {
+	constantPool         *ConstantPool
+	bootstrapMethodIndex uint
+	name                 string
+	descriptor           string
+}
+
+func newDynamicConstantReference(constantPool *ConstantPool, constantDynamicInfo *classfile.ConstantDynamicInfo) *DynamicConstantReference {
+	dynamicConstantReference := &DynamicConstantReference{}
+	dynamicConstantReference.constantPool = constantPool
+	dynamicConstantReference.bootstrapMethodIndex = uint(constantDynamicInfo.GetBootstrapMethodAttributeIndex())
+	dynamicConstantReference.name, dynamicConstantReference.descriptor = constantDynamicInfo.GetNameAndTypeDescriptor()
+
+	return dynamicConstantReference
+}
+
+func (dynamicConstantReference *DynamicConstantReference) GetName() string {
+	return dynamicConstantReference.name
+}
+
+func (dynamicConstantReference *DynamicConstantReference) GetDescriptor() string {
+	return dynamicConstantReference.descriptor
+}
+
+// Their bootstrap methods live in java.lang.invoke.ConstantBootstraps, which the supported
+// runtimes do not have, so loading a dynamic constant fails the way an unsupported call site does
+func (dynamicConstantReference *DynamicConstantReference) ResolveConstant() {
+	class := dynamicConstantReference.constantPool.class
+
+	panic(NewJavaException("java/lang/BootstrapMethodError", "Unsupported dynamic constant "+
+		dynamicConstantReference.name+":"+dynamicConstantReference.descriptor+" in "+class.GetJavaName()))
+}
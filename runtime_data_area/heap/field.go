@@ -2,6 +2,10 @@ package heap
 
 import "github.com/Frederick-S/jvmgo/classfile"
 
+// Fields are addressed by offset from a base object, static fields are based on their
+// java.lang.Class object so the flag tells them apart from the fields of that object
+const StaticFieldOffsetFlag = 1 << 32
+
 type Field struct {
 	ClassMember
 	constantValueIndex uint
@@ -52,3 +56,15 @@ func (field *Field) GetVariableIndex() uint {
 func (field *Field) IsLongOrDouble() bool {
 	return field.descriptor == "J" || field.descriptor == "D"
 }
+
+func (field *Field) GetOffset() int64 {
+	if field.IsStatic() {
+		return int64(field.variableIndex) | StaticFieldOffsetFlag
+	}
+
+	return int64(field.variableIndex)
+}
+
+func (field *Field) GetType() *Class {
+	return field.class.classLoader.LoadClass(convertDescriptorToClassName(field.descriptor))
+}
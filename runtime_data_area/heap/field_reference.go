@@ -15,6 +15,18 @@ func newFieldReference(constantPool *ConstantPool, constantFieldReferenceInfo *c
 	return fieldReference
 }
 
+func newResolvedFieldReference(constantPool *ConstantPool, field *Field) *FieldReference {
+	fieldReference := &FieldReference{}
+	fieldReference.constantPool = constantPool
+	fieldReference.className = field.class.name
+	fieldReference.class = field.class
+	fieldReference.name = field.name
+	fieldReference.descriptor = field.descriptor
+	fieldReference.field = field
+
+	return fieldReference
+}
+
 func (fieldReference *FieldReference) GetResolvedField() *Field {
 	if fieldReference.field == nil {
 		fieldReference.ResolveFieldReference()
@@ -26,20 +38,20 @@ func (fieldReference *FieldReference) GetResolvedField() *Field {
 func (fieldReference *FieldReference) ResolveFieldReference() {
 	class := fieldReference.constantPool.class
 	resolvedClass := fieldReference.GetResolvedClass()
-	field := lookupField(resolvedClass, fieldReference.name, fieldReference.descriptor)
+	field := LookupField(resolvedClass, fieldReference.name, fieldReference.descriptor)
 
 	if field == nil {
-		panic("java.lang.NoSuchFieldError")
+		panic(NewJavaException("java/lang/NoSuchFieldError", fieldReference.name))
 	}
 
 	if !field.IsAccessibleTo(class) {
-		panic("java.lang.IllegalAccessError")
+		panic(NewJavaException("java/lang/IllegalAccessError", ""))
 	}
 
 	fieldReference.field = field
 }
 
-func lookupField(class *Class, name, descriptor string) *Field {
+func LookupField(class *Class, name, descriptor string) *Field {
 	for _, field := range class.fields {
 		if field.name == name && field.descriptor == descriptor {
 			return field
@@ -47,7 +59,7 @@ func lookupField(class *Class, name, descriptor string) *Field {
 	}
 
 	for _, interfaceMember := range class.interfaces {
-		field := lookupField(interfaceMember, name, descriptor)
+		field := LookupField(interfaceMember, name, descriptor)
 
 		if field != nil {
 			return field
@@ -55,7 +67,7 @@ func lookupField(class *Class, name, descriptor string) *Field {
 	}
 
 	if class.superClass != nil {
-		return lookupField(class.superClass, name, descriptor)
+		return LookupField(class.superClass, name, descriptor)
 	}
 
 	return nil
@@ -15,6 +15,18 @@ func newInterfaceMethodReference(constantPool *ConstantPool, constantInterfaceMe
 	return interfaceMethodReference
 }
 
+func newResolvedInterfaceMethodReference(constantPool *ConstantPool, method *Method) *InterfaceMethodReference {
+	interfaceMethodReference := &InterfaceMethodReference{}
+	interfaceMethodReference.constantPool = constantPool
+	interfaceMethodReference.className = method.class.name
+	interfaceMethodReference.class = method.class
+	interfaceMethodReference.name = method.name
+	interfaceMethodReference.descriptor = method.descriptor
+	interfaceMethodReference.method = method
+
+	return interfaceMethodReference
+}
+
 func (interfaceMethodReference *InterfaceMethodReference) GetResolvedInterfaceMethod() *Method {
 	if interfaceMethodReference.method == nil {
 		interfaceMethodReference.ResolveInterfaceMethodReference()
@@ -28,17 +40,17 @@ func (interfaceMethodReference *InterfaceMethodReference) ResolveInterfaceMethod
 	resolvedClass := interfaceMethodReference.GetResolvedClass()
 
 	if !resolvedClass.IsInterface() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
 	method := lookupInterfaceMethod(resolvedClass, interfaceMethodReference.name, interfaceMethodReference.descriptor)
 
 	if method == nil {
-		panic("java.lang.NoSuchMethodError")
+		panic(NewJavaException("java/lang/NoSuchMethodError", resolvedClass.GetJavaName()+"."+interfaceMethodReference.name+interfaceMethodReference.descriptor))
 	}
 
 	if !method.IsAccessibleTo(class) {
-		panic("java.lang.IllegalAccessError")
+		panic(NewJavaException("java/lang/IllegalAccessError", ""))
 	}
 
 	interfaceMethodReference.method = method
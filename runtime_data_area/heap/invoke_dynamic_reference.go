@@ -0,0 +1,75 @@
+package heap
+
+import "github.com/Frederick-S/jvmgo/classfile"
+
+type InvokeDynamicReference struct {
+	constantPool         *ConstantPool
+	bootstrapMethodIndex uint
+	name                 string
+	descriptor           string
+	callSiteMethod       *Method
+}
+
+func newInvokeDynamicReference(constantPool *ConstantPool, constantInvokeDynamicInfo *classfile.ConstantInvokeDynamicInfo) *InvokeDynamicReference {
+	invokeDynamicReference := &InvokeDynamicReference{}
+	invokeDynamicReference.constantPool = constantPool
+	invokeDynamicReference.bootstrapMethodIndex = uint(constantInvokeDynamicInfo.GetBootstrapMethodAttributeIndex())
+	invokeDynamicReference.name, invokeDynamicReference.descriptor = constantInvokeDynamicInfo.GetNameAndTypeDescriptor()
+
+	return invokeDynamicReference
+}
+
+func (invokeDynamicReference *InvokeDynamicReference) GetName() string {
+	return invokeDynamicReference.name
+}
+
+func (invokeDynamicReference *InvokeDynamicReference) GetDescriptor() string {
+	return invokeDynamicReference.descriptor
+}
+
+// A static method with the call site's descriptor, invoking it runs the linked target
+func (invokeDynamicReference *InvokeDynamicReference) GetResolvedCallSiteMethod() *Method {
+	if invokeDynamicReference.callSiteMethod == nil {
+		invokeDynamicReference.ResolveCallSite()
+	}
+
+	return invokeDynamicReference.callSiteMethod
+}
+
+// Only the LambdaMetafactory and StringConcatFactory bootstrap methods are supported, any other
+// bootstrap method fails with BootstrapMethodError instead of being run
+func (invokeDynamicReference *InvokeDynamicReference) ResolveCallSite() {
+	class := invokeDynamicReference.constantPool.class
+
+	if invokeDynamicReference.bootstrapMethodIndex >= uint(len(class.bootstrapMethods)) {
+		panic(NewJavaException("java/lang/BootstrapMethodError", "Missing bootstrap method in "+class.GetJavaName()))
+	}
+
+	bootstrapMethod := class.bootstrapMethods[invokeDynamicReference.bootstrapMethodIndex]
+	methodHandleReference := class.constantPool.GetConstant(uint(bootstrapMethod.GetBootstrapMethodReference())).(*MethodHandleReference)
+	bootstrapMethodReference := methodHandleReference.GetReference().(*MethodReference)
+	bootstrapArguments := make([]Constant, len(bootstrapMethod.GetBootstrapArguments()))
+
+	for i, bootstrapArgumentIndex := range bootstrapMethod.GetBootstrapArguments() {
+		bootstrapArguments[i] = class.constantPool.GetConstant(uint(bootstrapArgumentIndex))
+	}
+
+	name := invokeDynamicReference.name
+	descriptor := invokeDynamicReference.descriptor
+
+	// Bootstrap methods are not run in the guest, the VM links the call sites they would produce
+	switch bootstrapMethodReference.className + "." + bootstrapMethodReference.name {
+	case "java/lang/invoke/LambdaMetafactory.metafactory":
+		invokeDynamicReference.callSiteMethod = linkLambdaCallSite(class, name, descriptor, bootstrapArguments, false)
+	case "java/lang/invoke/LambdaMetafactory.altMetafactory":
+		invokeDynamicReference.callSiteMethod = linkLambdaCallSite(class, name, descriptor, bootstrapArguments, true)
+	case "java/lang/invoke/StringConcatFactory.makeConcat":
+		invokeDynamicReference.callSiteMethod = linkStringConcatCallSite(class, descriptor, getStringConcatRecipe(descriptor), nil)
+	case "java/lang/invoke/StringConcatFactory.makeConcatWithConstants":
+		recipe := bootstrapArguments[0].(string)
+		invokeDynamicReference.callSiteMethod = linkStringConcatCallSite(class, descriptor, recipe, bootstrapArguments[1:])
+	default:
+		panic(NewJavaException("java/lang/BootstrapMethodError", "Unsupported bootstrap method: "+
+			bootstrapMethodReference.className+"."+bootstrapMethodReference.name+bootstrapMethodReference.descriptor))
+	}
+}
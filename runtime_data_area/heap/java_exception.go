@@ -0,0 +1,43 @@
+package heap
+
+import "strings"
+
+// A Java exception raised by the VM itself, the interpreter throws it into the guest
+type JavaException struct {
+	className string
+	message   string
+	cause     error
+}
+
+func NewJavaException(className, message string) *JavaException {
+	return &JavaException{
+		className: className,
+		message:   message,
+	}
+}
+
+func (javaException *JavaException) Error() string {
+	javaName := strings.Replace(javaException.className, "/", ".", -1)
+
+	if javaException.message == "" {
+		return javaName
+	}
+
+	return javaName + ": " + javaException.message
+}
+
+func (javaException *JavaException) GetClassName() string {
+	return javaException.className
+}
+
+func (javaException *JavaException) GetMessage() string {
+	return javaException.message
+}
+
+func (javaException *JavaException) GetCause() error {
+	return javaException.cause
+}
+
+func (javaException *JavaException) SetCause(cause error) {
+	javaException.cause = cause
+}
@@ -0,0 +1,148 @@
+package heap
+
+import "strconv"
+
+// Flags of LambdaMetafactory.altMetafactory
+const (
+	lambdaFlagSerializable = 1 << 0
+	lambdaFlagMarkers      = 1 << 1
+	lambdaFlagBridges      = 1 << 2
+)
+
+// Spin a class implementing the functional interface, as LambdaMetafactory does. The call site
+// method takes the captured arguments and returns a new instance of that class.
+func linkLambdaCallSite(hostClass *Class, name, descriptor string, bootstrapArguments []Constant, isAltMetafactory bool) *Method {
+	classLoader := hostClass.classLoader
+	invokedType := parseMethodDescriptor(descriptor)
+	samMethodType := bootstrapArguments[0].(*MethodTypeReference)
+	implMethodHandle := bootstrapArguments[1].(*MethodHandleReference)
+	instantiatedMethodType := bootstrapArguments[2].(*MethodTypeReference)
+	interfaces := []*Class{classLoader.LoadClass(convertDescriptorToClassName(invokedType.returnType))}
+	methodDescriptors := []string{samMethodType.descriptor}
+
+	if isAltMetafactory {
+		flags := bootstrapArguments[3].(int32)
+		arguments := bootstrapArguments[4:]
+
+		if flags&lambdaFlagMarkers != 0 {
+			markersCount := int(arguments[0].(int32))
+
+			for _, marker := range arguments[1 : 1+markersCount] {
+				interfaces = append(interfaces, marker.(*ClassReference).GetResolvedClass())
+			}
+
+			arguments = arguments[1+markersCount:]
+		}
+
+		if flags&lambdaFlagBridges != 0 {
+			bridgesCount := int(arguments[0].(int32))
+
+			for _, bridge := range arguments[1 : 1+bridgesCount] {
+				if bridge.(*MethodTypeReference).descriptor != samMethodType.descriptor {
+					methodDescriptors = append(methodDescriptors, bridge.(*MethodTypeReference).descriptor)
+				}
+			}
+		}
+
+		if flags&lambdaFlagSerializable != 0 {
+			interfaces = append(interfaces, classLoader.LoadClass("java/io/Serializable"))
+		}
+	}
+
+	class := newSyntheticClass(hostClass, "$$Lambda$", interfaces)
+	capturedFields := make([]*Field, len(invokedType.parameterTypes))
+
+	for i, parameterType := range invokedType.parameterTypes {
+		capturedFields[i] = class.addSyntheticField("arg$"+strconv.Itoa(i+1), parameterType)
+	}
+
+	prepareClass(class)
+
+	implMethod := implMethodHandle.GetResolvedMethod()
+
+	for _, methodDescriptor := range methodDescriptors {
+		codeBuilder := newLambdaMethodCodeBuilder(class, capturedFields, methodDescriptor, instantiatedMethodType.descriptor,
+			implMethodHandle.referenceKind, implMethod)
+
+		class.addSyntheticMethod(ACC_PUBLIC, name, methodDescriptor, codeBuilder)
+	}
+
+	return class.addSyntheticMethod(ACC_PUBLIC|ACC_STATIC, "get$Lambda", descriptor, newLambdaFactoryCodeBuilder(class, capturedFields))
+}
+
+// Create an instance and store the captured arguments in its fields
+func newLambdaFactoryCodeBuilder(class *Class, capturedFields []*Field) *codeBuilder {
+	codeBuilder := newCodeBuilder(class)
+	codeBuilder.emitNew(class)
+	index := uint(0)
+
+	for _, field := range capturedFields {
+		codeBuilder.emitDup()
+		codeBuilder.emitLoad(field.descriptor, index)
+		codeBuilder.emitPutField(field)
+
+		index += getSlotsCount(field.descriptor)
+	}
+
+	codeBuilder.emitReturn(convertClassNameToDescriptor(class.name))
+
+	return codeBuilder
+}
+
+// Pass the captured arguments and then the method's own arguments to the implementation method
+func newLambdaMethodCodeBuilder(class *Class, capturedFields []*Field, methodDescriptor, instantiatedDescriptor string,
+	implReferenceKind uint8, implMethod *Method) *codeBuilder {
+	codeBuilder := newCodeBuilder(class)
+	implMethodType := parseMethodDescriptor(implMethod.descriptor)
+	implParameterTypes := implMethodType.parameterTypes
+	implReturnType := implMethodType.returnType
+	implClassType := convertClassNameToDescriptor(implMethod.class.name)
+
+	switch implReferenceKind {
+	case REF_newInvokeSpecial:
+		codeBuilder.emitNew(implMethod.class)
+		codeBuilder.emitDup()
+
+		implReturnType = implClassType
+	case REF_invokeVirtual, REF_invokeSpecial, REF_invokeInterface:
+		// The receiver is the first argument
+		implParameterTypes = append([]string{implClassType}, implParameterTypes...)
+	}
+
+	for i, field := range capturedFields {
+		codeBuilder.emitLoad(convertClassNameToDescriptor(class.name), 0)
+		codeBuilder.emitGetField(field)
+		codeBuilder.emitConversion(field.descriptor, implParameterTypes[i])
+	}
+
+	methodType := parseMethodDescriptor(methodDescriptor)
+	instantiatedMethodType := parseMethodDescriptor(instantiatedDescriptor)
+	index := uint(1)
+
+	for i, parameterType := range methodType.parameterTypes {
+		codeBuilder.emitLoad(parameterType, index)
+
+		index += getSlotsCount(parameterType)
+		instantiatedType := instantiatedMethodType.parameterTypes[i]
+
+		// The instantiated type tells which wrapper to unbox
+		if !isPrimitiveType(instantiatedType) {
+			codeBuilder.emitConversion(parameterType, instantiatedType)
+
+			parameterType = instantiatedType
+		}
+
+		codeBuilder.emitConversion(parameterType, implParameterTypes[len(capturedFields)+i])
+	}
+
+	if implReferenceKind == REF_newInvokeSpecial {
+		codeBuilder.emitInvoke(REF_invokeSpecial, implMethod)
+	} else {
+		codeBuilder.emitInvoke(implReferenceKind, implMethod)
+	}
+
+	codeBuilder.emitConversion(implReturnType, methodType.returnType)
+	codeBuilder.emitReturn(methodType.returnType)
+
+	return codeBuilder
+}
@@ -9,7 +9,15 @@ type Method struct {
 	code                      []byte
 	exceptionTable            ExceptionTable
 	lineNumberTable           *classfile.LineNumberTableAttribute
+	stackMapTable             *classfile.StackMapTableAttribute
 	argumentsCount            uint
+	// Constant pool indices of the classes in the throws clause
+	exceptionIndexTable []uint16
+	// Raw RuntimeVisibleParameterAnnotations and AnnotationDefault
+	parameterAnnotations []byte
+	annotationDefault    []byte
+	// Set on the methods linked to signature polymorphic call sites
+	signaturePolymorphicMethod *Method
 }
 
 func newMethods(class *Class, memberInfos []*classfile.MemberInfo) []*Method {
@@ -46,6 +54,25 @@ func (method *Method) copyAttributes(memberInfo *classfile.MemberInfo) {
 		method.code = codeAttribute.GetCode()
 		method.exceptionTable = newExceptionTable(codeAttribute.GetExceptionTable(), method.class.constantPool)
 		method.lineNumberTable = codeAttribute.GetLineNumberTableAttribute()
+		method.stackMapTable = codeAttribute.GetStackMapTableAttribute()
+	}
+
+	exceptionsAttribute := memberInfo.GetExceptionsAttribute()
+
+	if exceptionsAttribute != nil {
+		method.exceptionIndexTable = exceptionsAttribute.GetExceptionIndexTable()
+	}
+
+	parameterAnnotationsAttribute := memberInfo.GetRuntimeVisibleParameterAnnotationsAttribute()
+
+	if parameterAnnotationsAttribute != nil {
+		method.parameterAnnotations = parameterAnnotationsAttribute.GetData()
+	}
+
+	annotationDefaultAttribute := memberInfo.GetAnnotationDefaultAttribute()
+
+	if annotationDefaultAttribute != nil {
+		method.annotationDefault = annotationDefaultAttribute.GetData()
 	}
 }
 
@@ -90,6 +117,54 @@ func (method *Method) injectCodeAttribute(returnType string) {
 	}
 }
 
+// MethodHandle.invokeExact and friends accept any descriptor, each call site gets a copy with its own
+func newSignaturePolymorphicMethod(signaturePolymorphicMethod *Method, descriptor string) *Method {
+	method := &Method{}
+	method.class = signaturePolymorphicMethod.class
+	method.accessFlags = signaturePolymorphicMethod.accessFlags
+	method.name = signaturePolymorphicMethod.name
+	method.descriptor = descriptor
+	method.signaturePolymorphicMethod = signaturePolymorphicMethod
+	methodDescriptor := parseMethodDescriptor(descriptor)
+	method.calculateArgumentsCount(methodDescriptor.parameterTypes)
+	method.injectCodeAttribute(methodDescriptor.returnType)
+
+	return method
+}
+
+func lookupSignaturePolymorphicMethod(class *Class, name, descriptor string) *Method {
+	for _, method := range class.methods {
+		if method.name == name && method.IsSignaturePolymorphic() {
+			return newSignaturePolymorphicMethod(method, descriptor)
+		}
+	}
+
+	return nil
+}
+
+func (method *Method) IsSignaturePolymorphic() bool {
+	if method.signaturePolymorphicMethod != nil {
+		return true
+	}
+
+	if method.class.name != "java/lang/invoke/MethodHandle" && method.class.name != "java/lang/invoke/VarHandle" {
+		return false
+	}
+
+	parameterTypes := parseMethodDescriptor(method.descriptor).parameterTypes
+
+	return method.IsNative() && method.IsVarargs() && len(parameterTypes) == 1 && parameterTypes[0] == "[Ljava/lang/Object;"
+}
+
+// Natives are registered with the declared descriptor, not the one of the call site
+func (method *Method) GetDeclaredDescriptor() string {
+	if method.signaturePolymorphicMethod != nil {
+		return method.signaturePolymorphicMethod.descriptor
+	}
+
+	return method.descriptor
+}
+
 func (method *Method) IsSynchronized() bool {
 	return method.accessFlags&ACC_SYNCHRONIZED != 0
 }
@@ -151,3 +226,48 @@ func (method *Method) GetLineNumber(pc int) int {
 
 	return method.lineNumberTable.GetLineNumber(pc)
 }
+
+func (method *Method) IsConstructor() bool {
+	return !method.IsStatic() && method.name == "<init>"
+}
+
+func (method *Method) IsClassInitializer() bool {
+	return method.IsStatic() && method.name == "<clinit>"
+}
+
+// Loads the classes of the parameters, primitive types included
+func (method *Method) GetParameterTypes() []*Class {
+	parameterTypes := parseMethodDescriptor(method.descriptor).parameterTypes
+	parameterClasses := make([]*Class, len(parameterTypes))
+
+	for i, parameterType := range parameterTypes {
+		parameterClasses[i] = method.class.classLoader.LoadClass(convertDescriptorToClassName(parameterType))
+	}
+
+	return parameterClasses
+}
+
+func (method *Method) GetReturnType() *Class {
+	returnType := parseMethodDescriptor(method.descriptor).returnType
+
+	return method.class.classLoader.LoadClass(convertDescriptorToClassName(returnType))
+}
+
+// The classes of the throws clause
+func (method *Method) GetExceptionTypes() []*Class {
+	exceptionClasses := make([]*Class, len(method.exceptionIndexTable))
+
+	for i, exceptionIndex := range method.exceptionIndexTable {
+		exceptionClasses[i] = method.class.constantPool.GetConstant(uint(exceptionIndex)).(*ClassReference).GetResolvedClass()
+	}
+
+	return exceptionClasses
+}
+
+func (method *Method) GetParameterAnnotations() []byte {
+	return method.parameterAnnotations
+}
+
+func (method *Method) GetAnnotationDefault() []byte {
+	return method.annotationDefault
+}
@@ -0,0 +1,75 @@
+package heap
+
+import "github.com/Frederick-S/jvmgo/classfile"
+
+// Method handle reference kinds
+const (
+	REF_getField         = 1
+	REF_getStatic        = 2
+	REF_putField         = 3
+	REF_putStatic        = 4
+	REF_invokeVirtual    = 5
+	REF_invokeStatic     = 6
+	REF_invokeSpecial    = 7
+	REF_newInvokeSpecial = 8
+	REF_invokeInterface  = 9
+)
+
+type MethodHandleReference struct {
+	constantPool   *ConstantPool
+	referenceKind  uint8
+	referenceIndex uint
+	methodHandle   *Object
+}
+
+func newMethodHandleReference(constantPool *ConstantPool, constantMethodHandleInfo *classfile.ConstantMethodHandleInfo) *MethodHandleReference {
+	return &MethodHandleReference{
+		constantPool:   constantPool,
+		referenceKind:  constantMethodHandleInfo.GetMethodHandleKind(),
+		referenceIndex: uint(constantMethodHandleInfo.GetMethodHandleReferenceIndex()),
+	}
+}
+
+func (methodHandleReference *MethodHandleReference) GetReferenceKind() uint8 {
+	return methodHandleReference.referenceKind
+}
+
+// The MethodHandle object ldc created, nil until it was first loaded
+func (methodHandleReference *MethodHandleReference) GetMethodHandle() *Object {
+	return methodHandleReference.methodHandle
+}
+
+func (methodHandleReference *MethodHandleReference) SetMethodHandle(methodHandle *Object) {
+	methodHandleReference.methodHandle = methodHandle
+}
+
+// The field, method or interface method reference the handle points at
+func (methodHandleReference *MethodHandleReference) GetReference() Constant {
+	return methodHandleReference.constantPool.GetConstant(methodHandleReference.referenceIndex)
+}
+
+func (methodHandleReference *MethodHandleReference) GetMemberReference() *MemberReference {
+	reference := methodHandleReference.GetReference()
+
+	switch reference.(type) {
+	case *FieldReference:
+		return &reference.(*FieldReference).MemberReference
+	case *MethodReference:
+		return &reference.(*MethodReference).MemberReference
+	default:
+		return &reference.(*InterfaceMethodReference).MemberReference
+	}
+}
+
+func (methodHandleReference *MethodHandleReference) GetResolvedMethod() *Method {
+	reference := methodHandleReference.GetReference()
+
+	switch reference.(type) {
+	case *MethodReference:
+		return reference.(*MethodReference).GetResolvedMethod()
+	case *InterfaceMethodReference:
+		return reference.(*InterfaceMethodReference).GetResolvedInterfaceMethod()
+	default:
+		panic(NewJavaException("java/lang/IncompatibleClassChangeError", "Method handle does not reference a method"))
+	}
+}
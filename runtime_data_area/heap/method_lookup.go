@@ -12,6 +12,19 @@ func LookupMethodInClass(class *Class, name, descriptor string) *Method {
 	return nil
 }
 
+// Default methods are only found in interfaces, when the class hierarchy has no implementation
+func LookupDefaultMethod(class *Class, name, descriptor string) *Method {
+	for currentClass := class; currentClass != nil; currentClass = currentClass.superClass {
+		method := lookupMethodInInterfaces(currentClass.interfaces, name, descriptor)
+
+		if method != nil && !method.IsAbstract() {
+			return method
+		}
+	}
+
+	return nil
+}
+
 func lookupMethodInInterfaces(interfaces []*Class, name, descriptor string) *Method {
 	for _, currentInterface := range interfaces {
 		for _, method := range currentInterface.methods {
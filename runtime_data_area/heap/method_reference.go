@@ -15,6 +15,18 @@ func newMethodReference(constantPool *ConstantPool, constantMethodReferenceInfo
 	return methodReference
 }
 
+func newResolvedMethodReference(constantPool *ConstantPool, method *Method) *MethodReference {
+	methodReference := &MethodReference{}
+	methodReference.constantPool = constantPool
+	methodReference.className = method.class.name
+	methodReference.class = method.class
+	methodReference.name = method.name
+	methodReference.descriptor = method.descriptor
+	methodReference.method = method
+
+	return methodReference
+}
+
 func (methodReference *MethodReference) GetResolvedMethod() *Method {
 	if methodReference.method == nil {
 		methodReference.ResolveMethodReference()
@@ -28,23 +40,27 @@ func (methodReference *MethodReference) ResolveMethodReference() {
 	resolvedClass := methodReference.GetResolvedClass()
 
 	if resolvedClass.IsInterface() {
-		panic("java.lang.IncompatibleClassChangeError")
+		panic(NewJavaException("java/lang/IncompatibleClassChangeError", ""))
 	}
 
-	method := lookupMethod(resolvedClass, methodReference.name, methodReference.descriptor)
+	method := LookupMethod(resolvedClass, methodReference.name, methodReference.descriptor)
+
+	if method == nil {
+		method = lookupSignaturePolymorphicMethod(resolvedClass, methodReference.name, methodReference.descriptor)
+	}
 
 	if method == nil {
-		panic("java.lang.NoSuchMethodError")
+		panic(NewJavaException("java/lang/NoSuchMethodError", resolvedClass.GetJavaName()+"."+methodReference.name+methodReference.descriptor))
 	}
 
 	if !method.IsAccessibleTo(class) {
-		panic("java.lang.IllegalAccessError")
+		panic(NewJavaException("java/lang/IllegalAccessError", ""))
 	}
 
 	methodReference.method = method
 }
 
-func lookupMethod(class *Class, name, descriptor string) *Method {
+func LookupMethod(class *Class, name, descriptor string) *Method {
 	method := LookupMethodInClass(class, name, descriptor)
 
 	if method == nil {
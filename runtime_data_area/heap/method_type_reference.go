@@ -0,0 +1,27 @@
+package heap
+
+import "github.com/Frederick-S/jvmgo/classfile"
+
+type MethodTypeReference struct {
+	descriptor string
+	methodType *Object
+}
+
+func newMethodTypeReference(constantMethodTypeInfo *classfile.ConstantMethodTypeInfo) *MethodTypeReference {
+	return &MethodTypeReference{
+		descriptor: constantMethodTypeInfo.GetDescriptor(),
+	}
+}
+
+func (methodTypeReference *MethodTypeReference) GetDescriptor() string {
+	return methodTypeReference.descriptor
+}
+
+// The MethodType object ldc created, nil until it was first loaded
+func (methodTypeReference *MethodTypeReference) GetMethodType() *Object {
+	return methodTypeReference.methodType
+}
+
+func (methodTypeReference *MethodTypeReference) SetMethodType(methodType *Object) {
+	methodTypeReference.methodType = methodType
+}
@@ -0,0 +1,77 @@
+package heap
+
+// Reentrant lock of an object, only used while holding the interpreter lock
+type Monitor struct {
+	owner      interface{}
+	entryCount int
+	// Threads in Object.wait, in the order they started waiting
+	waiters []interface{}
+}
+
+func (monitor *Monitor) TryEnter(owner interface{}, entryCount int) bool {
+	if monitor.owner != nil && monitor.owner != owner {
+		return false
+	}
+
+	monitor.owner = owner
+	monitor.entryCount += entryCount
+
+	return true
+}
+
+// Returns false if the monitor is not owned by the given owner
+func (monitor *Monitor) Exit(owner interface{}) bool {
+	if monitor.owner != owner {
+		return false
+	}
+
+	monitor.entryCount--
+
+	if monitor.entryCount == 0 {
+		monitor.owner = nil
+	}
+
+	return true
+}
+
+// Release the monitor however many times it was entered, returns the entry count
+func (monitor *Monitor) ExitAll(owner interface{}) int {
+	if monitor.owner != owner {
+		return 0
+	}
+
+	entryCount := monitor.entryCount
+	monitor.owner = nil
+	monitor.entryCount = 0
+
+	return entryCount
+}
+
+func (monitor *Monitor) IsOwnedBy(owner interface{}) bool {
+	return monitor.owner == owner
+}
+
+func (monitor *Monitor) AddWaiter(waiter interface{}) {
+	monitor.waiters = append(monitor.waiters, waiter)
+}
+
+func (monitor *Monitor) RemoveWaiter(waiter interface{}) {
+	for i, currentWaiter := range monitor.waiters {
+		if currentWaiter == waiter {
+			monitor.waiters = append(monitor.waiters[:i], monitor.waiters[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (monitor *Monitor) RemoveFirstWaiter() interface{} {
+	if len(monitor.waiters) == 0 {
+		return nil
+	}
+
+	waiter := monitor.waiters[0]
+	monitor.waiters = monitor.waiters[1:]
+
+	return waiter
+}
@@ -4,6 +4,7 @@ type Object struct {
 	class     *Class
 	data      interface{}
 	extraData interface{}
+	monitor   *Monitor
 }
 
 func newObject(class *Class) *Object {
@@ -29,10 +30,67 @@ func (object *Object) SetExtraData(extraData interface{}) {
 	object.extraData = extraData
 }
 
+// Created on first use, most objects are never locked
+func (object *Object) GetMonitor() *Monitor {
+	if object.monitor == nil {
+		object.monitor = &Monitor{}
+	}
+
+	return object.monitor
+}
+
 func (object *Object) IsInstanceOf(class *Class) bool {
 	return class.IsAssignableFrom(object.class)
 }
 
+func (object *Object) GetIntegerValue(name, descriptor string) int32 {
+	field := object.class.GetField(name, descriptor, false)
+
+	return object.data.(Variables).GetIntegerValue(field.variableIndex)
+}
+
+func (object *Object) SetIntegerValue(name, descriptor string, value int32) {
+	field := object.class.GetField(name, descriptor, false)
+
+	object.data.(Variables).SetIntegerValue(field.variableIndex, value)
+}
+
+func (object *Object) GetLongValue(name, descriptor string) int64 {
+	field := object.class.GetField(name, descriptor, false)
+
+	return object.data.(Variables).GetLongValue(field.variableIndex)
+}
+
+func (object *Object) SetLongValue(name, descriptor string, value int64) {
+	field := object.class.GetField(name, descriptor, false)
+
+	object.data.(Variables).SetLongValue(field.variableIndex, value)
+}
+
+func (object *Object) GetFloatValue(name, descriptor string) float32 {
+	field := object.class.GetField(name, descriptor, false)
+
+	return object.data.(Variables).GetFloatValue(field.variableIndex)
+}
+
+func (object *Object) SetFloatValue(name, descriptor string, value float32) {
+	field := object.class.GetField(name, descriptor, false)
+
+	object.data.(Variables).SetFloatValue(field.variableIndex, value)
+}
+
+func (object *Object) GetDoubleValue(name, descriptor string) float64 {
+	field := object.class.GetField(name, descriptor, false)
+
+	return object.data.(Variables).GetDoubleValue(field.variableIndex)
+}
+
+func (object *Object) SetDoubleValue(name, descriptor string, value float64) {
+	field := object.class.GetField(name, descriptor, false)
+
+	object.data.(Variables).SetDoubleValue(field.variableIndex, value)
+}
+
 func (object *Object) GetReferenceValue(name, descriptor string) *Object {
 	field := object.class.GetField(name, descriptor, false)
 
@@ -0,0 +1,165 @@
+package heap
+
+// Methods without a class file, used to run code the VM generates itself
+var shimClass = &Class{
+	accessFlags:             ACC_PUBLIC,
+	name:                    "~shim",
+	isInitializationStarted: true,
+}
+
+var athrowShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<athrow>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize: 1,
+	// athrow
+	code: []byte{0xbf},
+}
+
+// Throws the exception on top of its operand stack when it gets control back
+func GetAThrowShimMethod() *Method {
+	return athrowShimMethod
+}
+
+var discardShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<discard>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize: 1,
+	// pop, return
+	code: []byte{0x57, 0xb1},
+}
+
+// Drops the single slot result of the method called on top of it
+func GetDiscardShimMethod() *Method {
+	return discardShimMethod
+}
+
+// Where a method called through reflection returns to, and where its exceptions are caught
+const (
+	ReflectionShimReturnPC  = 1
+	ReflectionShimHandlerPC = 3
+)
+
+// Sits below a method called through reflection, with the Method or Constructor object in
+// local 0 and the object being constructed in local 1. Its operand stack first holds the
+// arguments of the call, then the result, which is boxed before it is returned. Exceptions
+// are wrapped in an InvocationTargetException before they are thrown on
+var reflectionShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<reflect>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize:              255,
+	maxNumberOfLocalVariables: 2,
+	// nop, invokenative, areturn, invokenative, athrow
+	code: []byte{0x00, 0xfe, 0xb0, 0xfe, 0xbf},
+	exceptionTable: ExceptionTable{
+		&ExceptionHandler{startPC: 0, endPC: ReflectionShimReturnPC, handlerPC: ReflectionShimHandlerPC},
+	},
+}
+
+func GetReflectionShimMethod() *Method {
+	return reflectionShimMethod
+}
+
+// Sits below the loadClass call Class.forName makes on a class loader of the application,
+// with the initialize flag in local 0 and the class name in local 1. Its operand stack first
+// holds the arguments of the call, then the loaded class, which is returned once initialized
+var forNameShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<forName>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize:              2,
+	maxNumberOfLocalVariables: 2,
+	// invokenative, areturn
+	code: []byte{0xfe, 0xb0},
+}
+
+func GetForNameShimMethod() *Method {
+	return forNameShimMethod
+}
+
+// Where a PrivilegedExceptionAction returns to, and where its exceptions are caught
+const (
+	PrivilegedShimReturnPC  = 1
+	PrivilegedShimHandlerPC = 2
+)
+
+// Sits below the run method of a PrivilegedExceptionAction, whose result it returns.
+// Checked exceptions are wrapped in a PrivilegedActionException before they are thrown on
+var privilegedShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<privileged>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize: 3,
+	// nop, areturn, invokenative, athrow
+	code: []byte{0x00, 0xb0, 0xfe, 0xbf},
+	exceptionTable: ExceptionTable{
+		&ExceptionHandler{startPC: 0, endPC: PrivilegedShimReturnPC, handlerPC: PrivilegedShimHandlerPC},
+	},
+}
+
+func GetPrivilegedShimMethod() *Method {
+	return privilegedShimMethod
+}
+
+// Where a method handle called through MethodHandle.invoke returns to
+const AsTypeShimReturnPC = 1
+
+// Sits between MethodHandle.invoke and the method handle when the type of the call site differs
+// from the type of the handle, with the handle in local 0 followed by the arguments converted to
+// its type. The result of the handle is converted to the type of the call site once it returns
+var asTypeShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<asType>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize:              4,
+	maxNumberOfLocalVariables: 255,
+	// invokenative, invokenative, return
+	code: []byte{0xfe, 0xfe, 0xb1},
+}
+
+func GetAsTypeShimMethod() *Method {
+	return asTypeShimMethod
+}
+
+// Sits below the Java method ldc calls to create a method type or method handle, with the index
+// of the constant in local 0. The created object is kept on the constant before it is returned
+var constantShimMethod = &Method{
+	ClassMember: ClassMember{
+		accessFlags: ACC_STATIC,
+		name:        "<constant>",
+		descriptor:  "()V",
+		class:       shimClass,
+	},
+	maxStackSize:              1,
+	maxNumberOfLocalVariables: 1,
+	// invokenative, areturn
+	code: []byte{0xfe, 0xb0},
+}
+
+func GetConstantShimMethod() *Method {
+	return constantShimMethod
+}
+
+func (method *Method) IsShim() bool {
+	return method.class == shimClass
+}
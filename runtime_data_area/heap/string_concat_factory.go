@@ -0,0 +1,107 @@
+package heap
+
+import "strings"
+
+// Tags in a StringConcatFactory recipe
+const (
+	recipeArgumentTag = '\u0001'
+	recipeConstantTag = '\u0002'
+)
+
+// Spin a static method appending the arguments to a StringBuilder, as javac did before StringConcatFactory
+func linkStringConcatCallSite(hostClass *Class, descriptor, recipe string, constants []Constant) *Method {
+	class := newSyntheticClass(hostClass, "$$StringConcat$", nil)
+	stringBuilderClass := hostClass.classLoader.LoadClass("java/lang/StringBuilder")
+	concatType := parseMethodDescriptor(descriptor)
+	codeBuilder := newCodeBuilder(class)
+
+	codeBuilder.emitNew(stringBuilderClass)
+	codeBuilder.emitDup()
+	codeBuilder.emitInvoke(REF_invokeSpecial, stringBuilderClass.GetConstructor("()V"))
+
+	text := ""
+	argumentIndex := 0
+	constantIndex := 0
+	index := uint(0)
+
+	for _, character := range recipe {
+		switch character {
+		case recipeArgumentTag:
+			codeBuilder.emitAppendText(stringBuilderClass, text)
+
+			text = ""
+			parameterType := concatType.parameterTypes[argumentIndex]
+
+			codeBuilder.emitLoad(parameterType, index)
+			codeBuilder.emitInvoke(REF_invokeVirtual, getStringBuilderAppendMethod(stringBuilderClass, parameterType))
+
+			argumentIndex++
+			index += getSlotsCount(parameterType)
+		case recipeConstantTag:
+			constant := constants[constantIndex]
+
+			if goString, ok := constant.(string); ok {
+				text += goString
+			} else {
+				codeBuilder.emitAppendText(stringBuilderClass, text)
+
+				text = ""
+
+				codeBuilder.emitAppendConstant(stringBuilderClass, constant)
+			}
+
+			constantIndex++
+		default:
+			text += string(character)
+		}
+	}
+
+	codeBuilder.emitAppendText(stringBuilderClass, text)
+	codeBuilder.emitInvoke(REF_invokeVirtual, stringBuilderClass.GetInstanceMethod("toString", "()Ljava/lang/String;"))
+	codeBuilder.emitReturn("Ljava/lang/String;")
+
+	return class.addSyntheticMethod(ACC_PUBLIC|ACC_STATIC, "concat", descriptor, codeBuilder)
+}
+
+// The makeConcat bootstrap method has no recipe, every argument is appended in order
+func getStringConcatRecipe(descriptor string) string {
+	return strings.Repeat(string(recipeArgumentTag), len(parseMethodDescriptor(descriptor).parameterTypes))
+}
+
+func (codeBuilder *codeBuilder) emitAppendText(stringBuilderClass *Class, text string) {
+	if text != "" {
+		codeBuilder.emitLoadString(text)
+		codeBuilder.emitInvoke(REF_invokeVirtual, getStringBuilderAppendMethod(stringBuilderClass, "Ljava/lang/String;"))
+	}
+}
+
+// Other constants are appended by StringBuilder so they are formatted the way Java formats them
+func (codeBuilder *codeBuilder) emitAppendConstant(stringBuilderClass *Class, constant Constant) {
+	parameterType := "Ljava/lang/Object;"
+
+	switch constant.(type) {
+	case int32:
+		parameterType = "I"
+	case float32:
+		parameterType = "F"
+	case int64:
+		parameterType = "J"
+	case float64:
+		parameterType = "D"
+	}
+
+	codeBuilder.emitLoadConstant(parameterType, constant)
+	codeBuilder.emitInvoke(REF_invokeVirtual, getStringBuilderAppendMethod(stringBuilderClass, parameterType))
+}
+
+func getStringBuilderAppendMethod(stringBuilderClass *Class, parameterType string) *Method {
+	switch parameterType {
+	case "Z", "C", "I", "J", "F", "D", "Ljava/lang/String;":
+	case "B", "S":
+		parameterType = "I"
+	default:
+		parameterType = "Ljava/lang/Object;"
+	}
+
+	return stringBuilderClass.GetInstanceMethod("append", "("+parameterType+")Ljava/lang/StringBuilder;")
+}
@@ -19,7 +19,7 @@ func (symbolicReference *SymbolicReference) ResolveClassReference() {
 	referencedClass := class.classLoader.LoadClass(symbolicReference.className)
 
 	if !referencedClass.IsAccessibleTo(class) {
-		panic("java.lang.IllegalAccessError")
+		panic(NewJavaException("java/lang/IllegalAccessError", ""))
 	}
 
 	symbolicReference.class = referencedClass
@@ -0,0 +1,319 @@
+package heap
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+var syntheticClassesCount uint32
+
+var boxedTypes = map[string]string{
+	"Z": "java/lang/Boolean",
+	"B": "java/lang/Byte",
+	"C": "java/lang/Character",
+	"S": "java/lang/Short",
+	"I": "java/lang/Integer",
+	"J": "java/lang/Long",
+	"F": "java/lang/Float",
+	"D": "java/lang/Double",
+}
+
+var unboxingMethodNames = map[string]string{
+	"Z": "booleanValue",
+	"B": "byteValue",
+	"C": "charValue",
+	"S": "shortValue",
+	"I": "intValue",
+	"J": "longValue",
+	"F": "floatValue",
+	"D": "doubleValue",
+}
+
+// Classes the VM spins at runtime, like the ones java.lang.invoke defines for call sites
+func newSyntheticClass(hostClass *Class, namePrefix string, interfaces []*Class) *Class {
+	classLoader := hostClass.classLoader
+	class := &Class{
+		accessFlags:             ACC_PUBLIC | ACC_FINAL | ACC_SUPER | ACC_SYNTHETIC,
+		name:                    hostClass.name + namePrefix + strconv.Itoa(int(atomic.AddUint32(&syntheticClassesCount, 1))),
+		superClassName:          "java/lang/Object",
+		sourceFileName:          hostClass.sourceFileName,
+		classLoader:             classLoader,
+		superClass:              classLoader.LoadClass("java/lang/Object"),
+		interfaces:              interfaces,
+		isInitializationStarted: true,
+	}
+
+	for _, interfaceMember := range interfaces {
+		class.interfaceNames = append(class.interfaceNames, interfaceMember.name)
+	}
+
+	class.constantPool = &ConstantPool{class, []Constant{nil}}
+	classLoader.createJavaClass(class)
+
+	return class
+}
+
+func (class *Class) addSyntheticField(name, descriptor string) *Field {
+	field := &Field{}
+	field.class = class
+	field.accessFlags = ACC_PRIVATE | ACC_SYNTHETIC
+	field.name = name
+	field.descriptor = descriptor
+	class.fields = append(class.fields, field)
+
+	return field
+}
+
+func (class *Class) addSyntheticMethod(accessFlags uint16, name, descriptor string, codeBuilder *codeBuilder) *Method {
+	method := &Method{}
+	method.class = class
+	method.accessFlags = accessFlags | ACC_SYNTHETIC
+	method.name = name
+	method.descriptor = descriptor
+	method.calculateArgumentsCount(parseMethodDescriptor(descriptor).parameterTypes)
+	method.maxNumberOfLocalVariables = method.argumentsCount
+	method.maxStackSize = codeBuilder.maxStackSize
+	method.code = codeBuilder.code
+	class.methods = append(class.methods, method)
+
+	return method
+}
+
+func (constantPool *ConstantPool) addConstant(constant Constant) uint {
+	constantPool.constants = append(constantPool.constants, constant)
+
+	return uint(len(constantPool.constants) - 1)
+}
+
+// Emits bytecode for synthetic methods, constants go to the synthetic class's own pool
+type codeBuilder struct {
+	class        *Class
+	code         []byte
+	stackSize    uint
+	maxStackSize uint
+}
+
+func newCodeBuilder(class *Class) *codeBuilder {
+	return &codeBuilder{class: class}
+}
+
+// Emit an instruction and account for its effect on the operand stack
+func (codeBuilder *codeBuilder) emit(pushedSlotsCount, poppedSlotsCount uint, code ...byte) {
+	codeBuilder.code = append(codeBuilder.code, code...)
+	codeBuilder.stackSize += pushedSlotsCount
+
+	if codeBuilder.stackSize > codeBuilder.maxStackSize {
+		codeBuilder.maxStackSize = codeBuilder.stackSize
+	}
+
+	codeBuilder.stackSize -= poppedSlotsCount
+}
+
+func (codeBuilder *codeBuilder) emitWithConstant(operationCode byte, pushedSlotsCount, poppedSlotsCount uint, constant Constant) {
+	index := codeBuilder.class.constantPool.addConstant(constant)
+
+	codeBuilder.emit(pushedSlotsCount, poppedSlotsCount, operationCode, byte(index>>8), byte(index))
+}
+
+func (codeBuilder *codeBuilder) emitNew(class *Class) {
+	// new
+	codeBuilder.emitWithConstant(0xbb, 1, 0, newResolvedClassReference(codeBuilder.class.constantPool, class))
+}
+
+func (codeBuilder *codeBuilder) emitDup() {
+	// dup
+	codeBuilder.emit(1, 0, 0x59)
+}
+
+func (codeBuilder *codeBuilder) emitLoadString(goString string) {
+	// ldc_w
+	codeBuilder.emitWithConstant(0x13, 1, 0, goString)
+}
+
+func (codeBuilder *codeBuilder) emitLoadConstant(typeDescriptor string, constant Constant) {
+	if getSlotsCount(typeDescriptor) == 2 {
+		// ldc2_w
+		codeBuilder.emitWithConstant(0x14, 2, 0, constant)
+	} else {
+		// ldc_w
+		codeBuilder.emitWithConstant(0x13, 1, 0, constant)
+	}
+}
+
+func (codeBuilder *codeBuilder) emitLoad(typeDescriptor string, index uint) {
+	operationCode := byte(0x19)
+
+	switch typeDescriptor[0] {
+	case 'Z', 'B', 'C', 'S', 'I':
+		// iload
+		operationCode = 0x15
+	case 'J':
+		// lload
+		operationCode = 0x16
+	case 'F':
+		// fload
+		operationCode = 0x17
+	case 'D':
+		// dload
+		operationCode = 0x18
+	}
+
+	if index > 0xff {
+		// wide
+		codeBuilder.emit(getSlotsCount(typeDescriptor), 0, 0xc4, operationCode, byte(index>>8), byte(index))
+	} else {
+		codeBuilder.emit(getSlotsCount(typeDescriptor), 0, operationCode, byte(index))
+	}
+}
+
+func (codeBuilder *codeBuilder) emitReturn(typeDescriptor string) {
+	switch typeDescriptor[0] {
+	case 'V':
+		// return
+		codeBuilder.emit(0, 0, 0xb1)
+	case 'Z', 'B', 'C', 'S', 'I':
+		// ireturn
+		codeBuilder.emit(0, 1, 0xac)
+	case 'J':
+		// lreturn
+		codeBuilder.emit(0, 2, 0xad)
+	case 'F':
+		// freturn
+		codeBuilder.emit(0, 1, 0xae)
+	case 'D':
+		// dreturn
+		codeBuilder.emit(0, 2, 0xaf)
+	default:
+		// areturn
+		codeBuilder.emit(0, 1, 0xb0)
+	}
+}
+
+func (codeBuilder *codeBuilder) emitGetField(field *Field) {
+	// getfield
+	codeBuilder.emitWithConstant(0xb4, getSlotsCount(field.descriptor), 1, newResolvedFieldReference(codeBuilder.class.constantPool, field))
+}
+
+func (codeBuilder *codeBuilder) emitPutField(field *Field) {
+	// putfield
+	codeBuilder.emitWithConstant(0xb5, 0, getSlotsCount(field.descriptor)+1, newResolvedFieldReference(codeBuilder.class.constantPool, field))
+}
+
+func (codeBuilder *codeBuilder) emitCheckCast(class *Class) {
+	// checkcast
+	codeBuilder.emitWithConstant(0xc0, 0, 0, newResolvedClassReference(codeBuilder.class.constantPool, class))
+}
+
+func (codeBuilder *codeBuilder) emitInvoke(referenceKind uint8, method *Method) {
+	constantPool := codeBuilder.class.constantPool
+	returnType := parseMethodDescriptor(method.descriptor).returnType
+	poppedSlotsCount := method.argumentsCount
+	pushedSlotsCount := uint(0)
+
+	if returnType != "V" {
+		pushedSlotsCount = getSlotsCount(returnType)
+	}
+
+	switch referenceKind {
+	case REF_invokeStatic:
+		// invokestatic
+		codeBuilder.emitWithConstant(0xb8, pushedSlotsCount, poppedSlotsCount, newResolvedMethodReference(constantPool, method))
+	case REF_invokeVirtual:
+		// invokevirtual
+		codeBuilder.emitWithConstant(0xb6, pushedSlotsCount, poppedSlotsCount, newResolvedMethodReference(constantPool, method))
+	case REF_invokeInterface:
+		// invokeinterface
+		codeBuilder.emitWithConstant(0xb9, pushedSlotsCount, poppedSlotsCount, newResolvedInterfaceMethodReference(constantPool, method))
+		codeBuilder.emit(0, 0, byte(method.argumentsCount), 0)
+	default:
+		// invokespecial
+		codeBuilder.emitWithConstant(0xb7, pushedSlotsCount, poppedSlotsCount, newResolvedMethodReference(constantPool, method))
+	}
+}
+
+// Adapt the value on top of the stack, boxing, unboxing, widening or casting as needed
+func (codeBuilder *codeBuilder) emitConversion(fromType, toType string) {
+	if fromType == toType {
+		return
+	}
+
+	if toType == "V" {
+		if getSlotsCount(fromType) == 2 {
+			// pop2
+			codeBuilder.emit(0, 2, 0x58)
+		} else {
+			// pop
+			codeBuilder.emit(0, 1, 0x57)
+		}
+
+		return
+	}
+
+	classLoader := codeBuilder.class.classLoader
+	isFromPrimitive := isPrimitiveType(fromType)
+	isToPrimitive := isPrimitiveType(toType)
+
+	if isFromPrimitive && isToPrimitive {
+		codeBuilder.emitPrimitiveWidening(fromType, toType)
+	} else if isFromPrimitive {
+		boxedClass := classLoader.LoadClass(boxedTypes[fromType])
+		valueOfMethod := boxedClass.GetStaticMethod("valueOf", "("+fromType+")"+convertClassNameToDescriptor(boxedClass.name))
+
+		codeBuilder.emitInvoke(REF_invokeStatic, valueOfMethod)
+	} else if isToPrimitive {
+		primitiveType := toType
+
+		// Integer -> long unboxes to int first, then widens
+		for boxedPrimitiveType, boxedClassName := range boxedTypes {
+			if fromType == convertClassNameToDescriptor(boxedClassName) {
+				primitiveType = boxedPrimitiveType
+			}
+		}
+
+		boxedClass := classLoader.LoadClass(boxedTypes[primitiveType])
+
+		if fromType != convertClassNameToDescriptor(boxedClass.name) {
+			codeBuilder.emitCheckCast(boxedClass)
+		}
+
+		codeBuilder.emitInvoke(REF_invokeVirtual, boxedClass.GetInstanceMethod(unboxingMethodNames[primitiveType], "()"+primitiveType))
+		codeBuilder.emitPrimitiveWidening(primitiveType, toType)
+	} else if toType != "Ljava/lang/Object;" {
+		codeBuilder.emitCheckCast(classLoader.LoadClass(convertDescriptorToClassName(toType)))
+	}
+}
+
+func (codeBuilder *codeBuilder) emitPrimitiveWidening(fromType, toType string) {
+	switch fromType + toType {
+	case "BJ", "CJ", "SJ", "IJ":
+		// i2l
+		codeBuilder.emit(2, 1, 0x85)
+	case "BF", "CF", "SF", "IF":
+		// i2f
+		codeBuilder.emit(1, 1, 0x86)
+	case "BD", "CD", "SD", "ID":
+		// i2d
+		codeBuilder.emit(2, 1, 0x87)
+	case "JF":
+		// l2f
+		codeBuilder.emit(1, 2, 0x89)
+	case "JD":
+		// l2d
+		codeBuilder.emit(2, 2, 0x8a)
+	case "FD":
+		// f2d
+		codeBuilder.emit(2, 1, 0x8d)
+	}
+}
+
+func isPrimitiveType(typeDescriptor string) bool {
+	return typeDescriptor[0] != 'L' && typeDescriptor[0] != '['
+}
+
+func getSlotsCount(typeDescriptor string) uint {
+	if typeDescriptor == "J" || typeDescriptor == "D" {
+		return 2
+	}
+
+	return 1
+}
@@ -0,0 +1,247 @@
+package heap
+
+import "strings"
+
+// Kinds of the types the verifier tracks in local variables and on the operand stack
+const (
+	typeTop = iota
+	typeInteger
+	typeFloat
+	typeLong
+	typeDouble
+	// Second slots of long and double values
+	typeLong2
+	typeDouble2
+	typeNull
+	typeUninitializedThis
+	// Result of a new instruction whose constructor has not run yet
+	typeUninitialized
+	typeReference
+	// Pushed by jsr, offset is the subroutine it returns from
+	typeReturnAddress
+)
+
+type verificationType struct {
+	kind int
+	// Class name of a reference, array classes are named by their descriptors
+	className string
+	// Offset of the new instruction of an uninitialized type
+	offset int
+}
+
+var (
+	topType               = verificationType{kind: typeTop}
+	integerType           = verificationType{kind: typeInteger}
+	floatType             = verificationType{kind: typeFloat}
+	longType              = verificationType{kind: typeLong}
+	doubleType            = verificationType{kind: typeDouble}
+	long2Type             = verificationType{kind: typeLong2}
+	double2Type           = verificationType{kind: typeDouble2}
+	nullType              = verificationType{kind: typeNull}
+	uninitializedThisType = verificationType{kind: typeUninitializedThis}
+)
+
+func newReferenceType(className string) verificationType {
+	return verificationType{kind: typeReference, className: className}
+}
+
+func newUninitializedType(offset int) verificationType {
+	return verificationType{kind: typeUninitialized, offset: offset}
+}
+
+func newReturnAddressType(subroutinePC int) verificationType {
+	return verificationType{kind: typeReturnAddress, offset: subroutinePC}
+}
+
+// boolean, byte, char and short values are ints to the verifier
+func newVerificationTypeFromDescriptor(descriptor string) verificationType {
+	switch descriptor[0] {
+	case 'Z', 'B', 'C', 'S', 'I':
+		return integerType
+	case 'F':
+		return floatType
+	case 'J':
+		return longType
+	case 'D':
+		return doubleType
+	case 'L':
+		return newReferenceType(descriptor[1 : len(descriptor)-1])
+	default:
+		return newReferenceType(descriptor)
+	}
+}
+
+func (verificationType verificationType) isCategory2() bool {
+	return verificationType.kind == typeLong || verificationType.kind == typeDouble
+}
+
+// Second slot that goes with a long or double
+func (verificationType verificationType) getSecondSlot() verificationType {
+	if verificationType.kind == typeLong {
+		return long2Type
+	}
+
+	return double2Type
+}
+
+func (verificationType verificationType) isSecondSlot() bool {
+	return verificationType.kind == typeLong2 || verificationType.kind == typeDouble2
+}
+
+// Anything aload, astore and the reference comparisons accept, initialized or not
+func (verificationType verificationType) isReference() bool {
+	switch verificationType.kind {
+	case typeNull, typeUninitializedThis, typeUninitialized, typeReference:
+		return true
+	}
+
+	return false
+}
+
+func (verificationType verificationType) isArray() bool {
+	return verificationType.kind == typeReference && verificationType.className[0] == '['
+}
+
+func (verificationType verificationType) getComponentType() verificationType {
+	return newVerificationTypeFromDescriptor(verificationType.className[1:])
+}
+
+func (verificationType verificationType) toString() string {
+	switch verificationType.kind {
+	case typeTop:
+		return "top"
+	case typeInteger:
+		return "integer"
+	case typeFloat:
+		return "float"
+	case typeLong:
+		return "long"
+	case typeDouble:
+		return "double"
+	case typeLong2:
+		return "long_2nd"
+	case typeDouble2:
+		return "double_2nd"
+	case typeNull:
+		return "null"
+	case typeUninitializedThis:
+		return "uninitializedThis"
+	case typeUninitialized:
+		return "uninitialized"
+	case typeReturnAddress:
+		return "returnAddress"
+	default:
+		return "'" + strings.Replace(verificationType.className, "/", ".", -1) + "'"
+	}
+}
+
+// Classes are loaded to compare references, interfaces take any reference like the JVMS says
+func (classLoader *ClassLoader) isAssignable(fromType, toType verificationType) bool {
+	if fromType == toType || toType.kind == typeTop {
+		return true
+	}
+
+	if toType.kind != typeReference {
+		return false
+	}
+
+	if fromType.kind == typeNull {
+		return true
+	}
+
+	if fromType.kind != typeReference {
+		return false
+	}
+
+	return classLoader.isClassNameAssignable(fromType.className, toType.className)
+}
+
+func (classLoader *ClassLoader) isClassNameAssignable(fromClassName, toClassName string) bool {
+	if fromClassName == toClassName || toClassName == "java/lang/Object" {
+		return true
+	}
+
+	if toClassName[0] != '[' && classLoader.LoadClass(toClassName).IsInterface() {
+		return true
+	}
+
+	if fromClassName[0] == '[' {
+		if toClassName[0] != '[' {
+			return false
+		}
+
+		fromComponentName := fromClassName[1:]
+		toComponentName := toClassName[1:]
+
+		if fromComponentName[0] != 'L' && fromComponentName[0] != '[' ||
+			toComponentName[0] != 'L' && toComponentName[0] != '[' {
+			return fromComponentName == toComponentName
+		}
+
+		return classLoader.isClassNameAssignable(convertDescriptorToClassName(fromComponentName), convertDescriptorToClassName(toComponentName))
+	}
+
+	if toClassName[0] == '[' {
+		return false
+	}
+
+	return classLoader.LoadClass(fromClassName).IsSubClassOf(classLoader.LoadClass(toClassName))
+}
+
+// The type inference verifier joins the types flowing into an instruction, top if they have nothing in common
+func (classLoader *ClassLoader) mergeTypes(type1, type2 verificationType) verificationType {
+	if type1 == type2 {
+		return type1
+	}
+
+	if type1.kind == typeNull && type2.kind == typeReference {
+		return type2
+	}
+
+	if type1.kind == typeReference && type2.kind == typeNull {
+		return type1
+	}
+
+	if type1.kind == typeReference && type2.kind == typeReference {
+		return newReferenceType(classLoader.mergeClassNames(type1.className, type2.className))
+	}
+
+	return topType
+}
+
+// Closest common super class, interfaces merge to Object like the JVMS says
+func (classLoader *ClassLoader) mergeClassNames(className1, className2 string) string {
+	if className1 == className2 {
+		return className1
+	}
+
+	if className1[0] == '[' || className2[0] == '[' {
+		if className1[0] != '[' || className2[0] != '[' {
+			return "java/lang/Object"
+		}
+
+		componentName1 := className1[1:]
+		componentName2 := className2[1:]
+
+		if componentName1[0] != 'L' && componentName1[0] != '[' || componentName2[0] != 'L' && componentName2[0] != '[' {
+			return "java/lang/Object"
+		}
+
+		return getArrayClassName(classLoader.mergeClassNames(convertDescriptorToClassName(componentName1), convertDescriptorToClassName(componentName2)))
+	}
+
+	class1 := classLoader.LoadClass(className1)
+	class2 := classLoader.LoadClass(className2)
+
+	if class1.IsInterface() || class2.IsInterface() {
+		return "java/lang/Object"
+	}
+
+	for superClass := class1; superClass != nil; superClass = superClass.superClass {
+		if superClass == class2 || class2.IsSubClassOf(superClass) {
+			return superClass.name
+		}
+	}
+
+	return "java/lang/Object"
+}
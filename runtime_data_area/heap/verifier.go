@@ -0,0 +1,371 @@
+package heap
+
+import (
+	"fmt"
+
+	"github.com/Frederick-S/jvmgo/classfile"
+)
+
+// Class files from this version on carry a StackMapTable and are verified by type checking,
+// older ones by type inference
+const typeCheckingMajorVersion = 50
+
+type verificationFrame struct {
+	locals []verificationType
+	stack  []verificationType
+	// Set in a constructor until it calls super() or this()
+	isThisUninitialized bool
+}
+
+type methodVerifier struct {
+	class            *Class
+	method           *Method
+	code             []byte
+	pc               int
+	returnDescriptor string
+	// Lengths of the instructions by their offsets, 0 inside an instruction
+	instructionLengths []int
+	// Types of the initial frame, long and double take one entry each
+	initialLocals  []verificationType
+	frame          *verificationFrame
+	stackMapFrames map[int]*verificationFrame
+	// State of the type inference of class files without stack maps
+	inferredFrames map[int]*verificationFrame
+	pendingPCs     []int
+	isPending      map[int]bool
+	subroutines    map[int]*subroutine
+}
+
+// Classes of the runtime are trusted, everything else is checked before it can run
+func verifyClass(class *Class) {
+	if class.isTrusted {
+		return
+	}
+
+	for _, method := range class.methods {
+		if method.IsAbstract() || method.IsNative() {
+			continue
+		}
+
+		verifier := newMethodVerifier(method)
+
+		if class.majorVersion >= typeCheckingMajorVersion {
+			verifier.typeCheck()
+		} else {
+			verifier.inferTypes()
+		}
+	}
+}
+
+func newMethodVerifier(method *Method) *methodVerifier {
+	verifier := &methodVerifier{
+		class:            method.class,
+		method:           method,
+		code:             method.code,
+		returnDescriptor: parseMethodDescriptor(method.descriptor).returnType,
+		stackMapFrames:   map[int]*verificationFrame{},
+	}
+
+	verifier.decodeInstructions()
+	verifier.frame = verifier.newInitialFrame()
+
+	return verifier
+}
+
+func (verifier *methodVerifier) fail(reason string) {
+	panic(NewJavaException("java/lang/VerifyError", fmt.Sprintf("%s in method %s.%s%s at offset %d",
+		reason, verifier.class.GetJavaName(), verifier.method.name, verifier.method.descriptor, verifier.pc)))
+}
+
+func (verifier *methodVerifier) typeCheck() {
+	verifier.parseStackMapTable()
+	verifier.checkExceptionTable()
+
+	for pc := 0; pc < len(verifier.code); pc += verifier.instructionLengths[pc] {
+		verifier.pc = pc
+		stackMapFrame, ok := verifier.stackMapFrames[pc]
+
+		if ok {
+			if verifier.frame != nil && !verifier.isFrameAssignable(verifier.frame, stackMapFrame) {
+				verifier.fail("Instruction type does not match stack map")
+			}
+
+			verifier.frame = stackMapFrame.copy()
+		} else if verifier.frame == nil {
+			verifier.fail("Expecting a stack map frame")
+		}
+
+		verifier.checkExceptionHandlers()
+
+		if !verifier.executeInstruction(verifier.checkBranchTarget) {
+			verifier.frame = nil
+		} else if verifier.isStoreInstruction() {
+			// Handlers see the locals of both sides of a store
+			verifier.checkExceptionHandlers()
+		}
+	}
+
+	if verifier.frame != nil {
+		verifier.fail("Falling off the end of the code")
+	}
+}
+
+// Stack map frames have to be at the targets of branches, and accept the frame branching there
+func (verifier *methodVerifier) checkBranchTarget(targetPC int) {
+	verifier.checkInstructionOffset(targetPC, "Illegal target of jump or branch")
+
+	stackMapFrame, ok := verifier.stackMapFrames[targetPC]
+
+	if !ok {
+		verifier.fail(fmt.Sprintf("Expecting a stack map frame at branch target %d", targetPC))
+	}
+
+	if !verifier.isFrameAssignable(verifier.frame, stackMapFrame) {
+		verifier.fail(fmt.Sprintf("Inconsistent stack map frame at branch target %d", targetPC))
+	}
+}
+
+func (verifier *methodVerifier) checkExceptionHandlers() {
+	for _, exceptionHandler := range verifier.method.exceptionTable {
+		if verifier.pc < exceptionHandler.startPC || verifier.pc >= exceptionHandler.endPC {
+			continue
+		}
+
+		stackMapFrame, ok := verifier.stackMapFrames[exceptionHandler.handlerPC]
+
+		if !ok {
+			verifier.fail(fmt.Sprintf("Expecting a stack map frame at exception handler %d", exceptionHandler.handlerPC))
+		}
+
+		exceptionFrame := &verificationFrame{
+			locals:              verifier.frame.locals,
+			stack:               []verificationType{getCatchVerificationType(exceptionHandler)},
+			isThisUninitialized: verifier.frame.isThisUninitialized,
+		}
+
+		if !verifier.isFrameAssignable(exceptionFrame, stackMapFrame) {
+			verifier.fail(fmt.Sprintf("Inconsistent stack map frame at exception handler %d", exceptionHandler.handlerPC))
+		}
+	}
+}
+
+func getCatchVerificationType(exceptionHandler *ExceptionHandler) verificationType {
+	if exceptionHandler.catchType == nil {
+		return newReferenceType("java/lang/Throwable")
+	}
+
+	return newReferenceType(exceptionHandler.catchType.className)
+}
+
+func (verifier *methodVerifier) checkExceptionTable() {
+	for _, exceptionHandler := range verifier.method.exceptionTable {
+		if exceptionHandler.startPC >= exceptionHandler.endPC {
+			verifier.fail("Illegal exception table range")
+		}
+
+		verifier.checkInstructionOffset(exceptionHandler.startPC, "Illegal exception table start_pc")
+
+		if exceptionHandler.endPC != len(verifier.code) {
+			verifier.checkInstructionOffset(exceptionHandler.endPC, "Illegal exception table end_pc")
+		}
+
+		verifier.checkInstructionOffset(exceptionHandler.handlerPC, "Illegal exception table handler_pc")
+
+		catchType := getCatchVerificationType(exceptionHandler)
+
+		if !verifier.class.classLoader.isAssignable(catchType, newReferenceType("java/lang/Throwable")) {
+			verifier.fail("Catch type is not a subclass of Throwable")
+		}
+	}
+}
+
+func (verifier *methodVerifier) checkInstructionOffset(offset int, reason string) {
+	if offset < 0 || offset >= len(verifier.code) || verifier.instructionLengths[offset] == 0 {
+		verifier.fail(reason)
+	}
+}
+
+func (verifier *methodVerifier) newInitialFrame() *verificationFrame {
+	frame := &verificationFrame{
+		locals: make([]verificationType, verifier.method.maxNumberOfLocalVariables),
+	}
+
+	if !verifier.method.IsStatic() {
+		if verifier.method.IsConstructor() && !verifier.class.IsJavaObjectClass() {
+			verifier.initialLocals = append(verifier.initialLocals, uninitializedThisType)
+		} else {
+			verifier.initialLocals = append(verifier.initialLocals, newReferenceType(verifier.class.name))
+		}
+	}
+
+	for _, parameterType := range parseMethodDescriptor(verifier.method.descriptor).parameterTypes {
+		verifier.initialLocals = append(verifier.initialLocals, newVerificationTypeFromDescriptor(parameterType))
+	}
+
+	if verifier.method.argumentsCount > verifier.method.maxNumberOfLocalVariables {
+		verifier.fail("Arguments can't fit into locals")
+	}
+
+	verifier.expandLocals(frame, verifier.initialLocals)
+
+	return frame
+}
+
+// Long and double locals take their second slots, unused locals are top
+func (verifier *methodVerifier) expandLocals(frame *verificationFrame, locals []verificationType) {
+	index := 0
+
+	for _, local := range locals {
+		if index >= len(frame.locals) || local.isCategory2() && index+1 >= len(frame.locals) {
+			verifier.fail("StackMapTable error: local variables exceed max_locals")
+		}
+
+		frame.locals[index] = local
+		index++
+
+		if local.isCategory2() {
+			frame.locals[index] = local.getSecondSlot()
+			index++
+		}
+
+		if local.kind == typeUninitializedThis {
+			frame.isThisUninitialized = true
+		}
+	}
+
+	for ; index < len(frame.locals); index++ {
+		frame.locals[index] = topType
+	}
+}
+
+/*
+The first frame is at offset_delta, every later one at offset_delta + 1 past the previous,
+chop and append frames change the locals of the previous frame
+*/
+func (verifier *methodVerifier) parseStackMapTable() {
+	if verifier.method.stackMapTable == nil {
+		return
+	}
+
+	locals := verifier.initialLocals
+	offset := -1
+
+	for _, stackMapFrame := range verifier.method.stackMapTable.GetEntries() {
+		frameType := stackMapFrame.GetFrameType()
+		offset += int(stackMapFrame.GetOffsetDelta()) + 1
+		verifier.pc = offset
+
+		switch {
+		case frameType <= 127 || frameType == 247:
+			// same and same_locals_1_stack_item frames
+		case frameType >= 248 && frameType <= 250:
+			choppedLocalsCount := int(251 - frameType)
+
+			if choppedLocalsCount > len(locals) {
+				verifier.fail("StackMapTable error: chop frame removes too many locals")
+			}
+
+			locals = locals[:len(locals)-choppedLocalsCount]
+		case frameType == 251:
+			// same_frame_extended
+		case frameType >= 252 && frameType <= 254:
+			locals = append(append([]verificationType{}, locals...), verifier.convertVerificationTypeInfos(stackMapFrame.GetLocals())...)
+		case frameType == 255:
+			locals = verifier.convertVerificationTypeInfos(stackMapFrame.GetLocals())
+		default:
+			verifier.fail(fmt.Sprintf("StackMapTable error: reserved frame type %d", frameType))
+		}
+
+		verifier.checkInstructionOffset(offset, "StackMapTable error: bad offset")
+
+		frame := &verificationFrame{
+			locals: make([]verificationType, verifier.method.maxNumberOfLocalVariables),
+		}
+
+		verifier.expandLocals(frame, locals)
+
+		for _, stackType := range verifier.convertVerificationTypeInfos(stackMapFrame.GetStack()) {
+			frame.stack = append(frame.stack, stackType)
+
+			if stackType.isCategory2() {
+				frame.stack = append(frame.stack, stackType.getSecondSlot())
+			}
+		}
+
+		if uint(len(frame.stack)) > verifier.method.maxStackSize {
+			verifier.fail("StackMapTable error: operand stack exceeds max_stack")
+		}
+
+		verifier.stackMapFrames[offset] = frame
+	}
+
+	verifier.pc = 0
+}
+
+func (verifier *methodVerifier) convertVerificationTypeInfos(verificationTypeInfos []*classfile.VerificationTypeInfo) []verificationType {
+	verificationTypes := make([]verificationType, len(verificationTypeInfos))
+
+	for i, verificationTypeInfo := range verificationTypeInfos {
+		switch verificationTypeInfo.GetTag() {
+		case classfile.ITEM_Top:
+			verificationTypes[i] = topType
+		case classfile.ITEM_Integer:
+			verificationTypes[i] = integerType
+		case classfile.ITEM_Float:
+			verificationTypes[i] = floatType
+		case classfile.ITEM_Double:
+			verificationTypes[i] = doubleType
+		case classfile.ITEM_Long:
+			verificationTypes[i] = longType
+		case classfile.ITEM_Null:
+			verificationTypes[i] = nullType
+		case classfile.ITEM_UninitializedThis:
+			verificationTypes[i] = uninitializedThisType
+		case classfile.ITEM_Object:
+			verificationTypes[i] = newReferenceType(verifier.getClassReference(uint(verificationTypeInfo.GetValue())).className)
+		case classfile.ITEM_Uninitialized:
+			offset := int(verificationTypeInfo.GetValue())
+
+			if offset >= len(verifier.code) || verifier.instructionLengths[offset] == 0 || verifier.code[offset] != 0xbb {
+				verifier.fail("StackMapTable error: bad uninitialized type offset")
+			}
+
+			verificationTypes[i] = newUninitializedType(offset)
+		default:
+			verifier.fail("StackMapTable error: bad verification type")
+		}
+	}
+
+	return verificationTypes
+}
+
+// Assignable locals and operand stack of the same depth, and no constructor call the target still waits for
+func (verifier *methodVerifier) isFrameAssignable(fromFrame, toFrame *verificationFrame) bool {
+	if len(fromFrame.stack) != len(toFrame.stack) || fromFrame.isThisUninitialized && !toFrame.isThisUninitialized {
+		return false
+	}
+
+	classLoader := verifier.class.classLoader
+
+	for i, local := range fromFrame.locals {
+		if !classLoader.isAssignable(local, toFrame.locals[i]) {
+			return false
+		}
+	}
+
+	for i, stackType := range fromFrame.stack {
+		if !classLoader.isAssignable(stackType, toFrame.stack[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (frame *verificationFrame) copy() *verificationFrame {
+	return &verificationFrame{
+		locals:              append([]verificationType{}, frame.locals...),
+		stack:               append([]verificationType{}, frame.stack...),
+		isThisUninitialized: frame.isThisUninitialized,
+	}
+}
@@ -0,0 +1,948 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operand types of the instructions that only pop and push primitives, pops:pushes
+var simpleInstructionTypes = map[uint8]string{
+	0x00: ":",
+	0x02: ":I", 0x03: ":I", 0x04: ":I", 0x05: ":I", 0x06: ":I", 0x07: ":I", 0x08: ":I",
+	0x09: ":J", 0x0a: ":J",
+	0x0b: ":F", 0x0c: ":F", 0x0d: ":F",
+	0x0e: ":D", 0x0f: ":D",
+	0x10: ":I", 0x11: ":I",
+	// add, sub, mul, div, rem
+	0x60: "II:I", 0x61: "JJ:J", 0x62: "FF:F", 0x63: "DD:D",
+	0x64: "II:I", 0x65: "JJ:J", 0x66: "FF:F", 0x67: "DD:D",
+	0x68: "II:I", 0x69: "JJ:J", 0x6a: "FF:F", 0x6b: "DD:D",
+	0x6c: "II:I", 0x6d: "JJ:J", 0x6e: "FF:F", 0x6f: "DD:D",
+	0x70: "II:I", 0x71: "JJ:J", 0x72: "FF:F", 0x73: "DD:D",
+	// neg
+	0x74: "I:I", 0x75: "J:J", 0x76: "F:F", 0x77: "D:D",
+	// shl, shr, ushr, and, or, xor
+	0x78: "II:I", 0x79: "JI:J", 0x7a: "II:I", 0x7b: "JI:J", 0x7c: "II:I", 0x7d: "JI:J",
+	0x7e: "II:I", 0x7f: "JJ:J", 0x80: "II:I", 0x81: "JJ:J", 0x82: "II:I", 0x83: "JJ:J",
+	// conversions
+	0x85: "I:J", 0x86: "I:F", 0x87: "I:D",
+	0x88: "J:I", 0x89: "J:F", 0x8a: "J:D",
+	0x8b: "F:I", 0x8c: "F:J", 0x8d: "F:D",
+	0x8e: "D:I", 0x8f: "D:J", 0x90: "D:F",
+	0x91: "I:I", 0x92: "I:I", 0x93: "I:I",
+	// comparisons
+	0x94: "JJ:I", 0x95: "FF:I", 0x96: "FF:I", 0x97: "DD:I", 0x98: "DD:I",
+}
+
+// Lengths of the instructions with fixed lengths, 0 for the ones the verifier decodes or rejects
+var instructionLengths = [256]int{
+	0x10: 2, 0x11: 3, 0x12: 2, 0x13: 3, 0x14: 3,
+	0x15: 2, 0x16: 2, 0x17: 2, 0x18: 2, 0x19: 2,
+	0x36: 2, 0x37: 2, 0x38: 2, 0x39: 2, 0x3a: 2,
+	0x84: 3,
+	0x99: 3, 0x9a: 3, 0x9b: 3, 0x9c: 3, 0x9d: 3, 0x9e: 3, 0x9f: 3, 0xa0: 3,
+	0xa1: 3, 0xa2: 3, 0xa3: 3, 0xa4: 3, 0xa5: 3, 0xa6: 3, 0xa7: 3, 0xa8: 3, 0xa9: 2,
+	0xb2: 3, 0xb3: 3, 0xb4: 3, 0xb5: 3, 0xb6: 3, 0xb7: 3, 0xb8: 3, 0xb9: 5, 0xba: 5,
+	0xbb: 3, 0xbc: 2, 0xbd: 3, 0xc0: 3, 0xc1: 3, 0xc5: 4, 0xc6: 3, 0xc7: 3, 0xc8: 5, 0xc9: 5,
+}
+
+// Types of the iload, lload, fload and dload families, aload takes any reference
+var localVariableTypes = []verificationType{integerType, longType, floatType, doubleType}
+
+// Component types xaload and xastore accept, aaload and aastore take arrays of references
+var arrayComponentDescriptors = []string{"I", "J", "F", "D", "", "BZ", "C", "S"}
+
+// Array classes of the newarray atypes
+var primitiveArrayClassNames = map[uint8]string{
+	4:  "[Z",
+	5:  "[C",
+	6:  "[F",
+	7:  "[D",
+	8:  "[B",
+	9:  "[S",
+	10: "[I",
+	11: "[J",
+}
+
+func (verifier *methodVerifier) decodeInstructions() {
+	codeLength := len(verifier.code)
+
+	if codeLength == 0 || codeLength > 65535 {
+		verifier.fail("Invalid code length")
+	}
+
+	verifier.instructionLengths = make([]int, codeLength)
+
+	for pc := 0; pc < codeLength; {
+		verifier.pc = pc
+		length := verifier.getInstructionLength(pc)
+
+		if length == 0 || pc+length > codeLength {
+			verifier.fail("Bad instruction")
+		}
+
+		verifier.instructionLengths[pc] = length
+		pc += length
+	}
+
+	verifier.pc = 0
+}
+
+func (verifier *methodVerifier) getInstructionLength(pc int) int {
+	opcode := verifier.code[pc]
+
+	switch {
+	case opcode == 0xaa:
+		// tableswitch
+		basePC := pc + 1 + verifier.getSwitchPadding(pc)
+
+		if basePC+12 > len(verifier.code) {
+			return 0
+		}
+
+		low := verifier.readInt32(basePC + 4)
+		high := verifier.readInt32(basePC + 8)
+
+		if low > high || int64(high)-int64(low) >= int64(len(verifier.code)) {
+			return 0
+		}
+
+		return basePC + 12 + int(high-low+1)*4 - pc
+	case opcode == 0xab:
+		// lookupswitch
+		basePC := pc + 1 + verifier.getSwitchPadding(pc)
+
+		if basePC+8 > len(verifier.code) {
+			return 0
+		}
+
+		pairsCount := verifier.readInt32(basePC + 4)
+
+		if pairsCount < 0 || int(pairsCount) >= len(verifier.code) {
+			return 0
+		}
+
+		return basePC + 8 + int(pairsCount)*8 - pc
+	case opcode == 0xc4:
+		// wide
+		if pc+1 >= len(verifier.code) {
+			return 0
+		}
+
+		modifiedOpcode := verifier.code[pc+1]
+
+		if modifiedOpcode == 0x84 {
+			return 6
+		}
+
+		if modifiedOpcode >= 0x15 && modifiedOpcode <= 0x19 || modifiedOpcode >= 0x36 && modifiedOpcode <= 0x3a || modifiedOpcode == 0xa9 {
+			return 4
+		}
+
+		return 0
+	case opcode > 0xc9:
+		return 0
+	case instructionLengths[opcode] != 0:
+		return instructionLengths[opcode]
+	default:
+		return 1
+	}
+}
+
+// Operands of tableswitch and lookupswitch start at a multiple of 4
+func (verifier *methodVerifier) getSwitchPadding(pc int) int {
+	return (4 - (pc+1)%4) % 4
+}
+
+func (verifier *methodVerifier) readUint16(offset int) uint {
+	return uint(verifier.code[offset])<<8 | uint(verifier.code[offset+1])
+}
+
+func (verifier *methodVerifier) readInt16(offset int) int {
+	return int(int16(verifier.readUint16(offset)))
+}
+
+func (verifier *methodVerifier) readInt32(offset int) int32 {
+	return int32(uint32(verifier.readUint16(offset))<<16 | uint32(verifier.readUint16(offset+2)))
+}
+
+func (verifier *methodVerifier) isStoreInstruction() bool {
+	opcode := verifier.code[verifier.pc]
+
+	return opcode >= 0x36 && opcode <= 0x4e || opcode == 0x84 || opcode == 0xc4
+}
+
+/*
+The transfer function: checks the operands of the instruction at pc against the frame and
+changes the frame to the one after it. branch is called with every jump target while the
+frame is the one the target sees, the result is false if the next instruction is not reached
+*/
+func (verifier *methodVerifier) executeInstruction(branch func(targetPC int)) bool {
+	pc := verifier.pc
+	opcode := verifier.code[pc]
+
+	if operandTypes, ok := simpleInstructionTypes[opcode]; ok {
+		separatorIndex := strings.Index(operandTypes, ":")
+
+		for i := separatorIndex - 1; i >= 0; i-- {
+			verifier.popType(newVerificationTypeFromDescriptor(operandTypes[i : i+1]))
+		}
+
+		for _, pushType := range operandTypes[separatorIndex+1:] {
+			verifier.pushType(newVerificationTypeFromDescriptor(string(pushType)))
+		}
+
+		return true
+	}
+
+	switch {
+	case opcode == 0x01:
+		// aconst_null
+		verifier.pushType(nullType)
+	case opcode >= 0x12 && opcode <= 0x14:
+		verifier.executeLdcInstruction(opcode)
+	case opcode >= 0x15 && opcode <= 0x19:
+		verifier.executeLoadInstruction(int(opcode-0x15), uint(verifier.code[pc+1]))
+	case opcode >= 0x1a && opcode <= 0x2d:
+		verifier.executeLoadInstruction(int(opcode-0x1a)/4, uint(opcode-0x1a)%4)
+	case opcode >= 0x2e && opcode <= 0x35:
+		verifier.executeArrayLoadInstruction(arrayComponentDescriptors[opcode-0x2e])
+	case opcode >= 0x36 && opcode <= 0x3a:
+		verifier.executeStoreInstruction(int(opcode-0x36), uint(verifier.code[pc+1]))
+	case opcode >= 0x3b && opcode <= 0x4e:
+		verifier.executeStoreInstruction(int(opcode-0x3b)/4, uint(opcode-0x3b)%4)
+	case opcode >= 0x4f && opcode <= 0x56:
+		verifier.executeArrayStoreInstruction(arrayComponentDescriptors[opcode-0x4f])
+	case opcode >= 0x57 && opcode <= 0x5f:
+		verifier.executeStackInstruction(opcode)
+	case opcode == 0x84:
+		// iinc
+		verifier.getLocal(uint(verifier.code[pc+1]), integerType)
+	case opcode >= 0x99 && opcode <= 0x9e:
+		verifier.popType(integerType)
+		branch(pc + verifier.readInt16(pc+1))
+	case opcode >= 0x9f && opcode <= 0xa4:
+		verifier.popType(integerType)
+		verifier.popType(integerType)
+		branch(pc + verifier.readInt16(pc+1))
+	case opcode == 0xa5 || opcode == 0xa6:
+		verifier.popReference()
+		verifier.popReference()
+		branch(pc + verifier.readInt16(pc+1))
+	case opcode == 0xa7:
+		// goto
+		branch(pc + verifier.readInt16(pc+1))
+
+		return false
+	case opcode == 0xa8 || opcode == 0xc9:
+		verifier.executeJsrInstruction(branch)
+
+		return false
+	case opcode == 0xa9:
+		verifier.executeRetInstruction(uint(verifier.code[pc+1]))
+
+		return false
+	case opcode == 0xaa || opcode == 0xab:
+		verifier.executeSwitchInstruction(opcode, branch)
+
+		return false
+	case opcode >= 0xac && opcode <= 0xb1:
+		verifier.executeReturnInstruction(opcode)
+
+		return false
+	case opcode >= 0xb2 && opcode <= 0xb5:
+		verifier.executeFieldInstruction(opcode)
+	case opcode >= 0xb6 && opcode <= 0xba:
+		verifier.executeInvokeInstruction(opcode)
+	case opcode == 0xbb:
+		verifier.executeNewInstruction()
+	case opcode == 0xbc:
+		// newarray
+		arrayClassName, ok := primitiveArrayClassNames[verifier.code[pc+1]]
+
+		if !ok {
+			verifier.fail("Illegal newarray type")
+		}
+
+		verifier.popType(integerType)
+		verifier.pushType(newReferenceType(arrayClassName))
+	case opcode == 0xbd:
+		// anewarray
+		className := verifier.getClassReference(verifier.readUint16(pc + 1)).className
+
+		verifier.popType(integerType)
+		verifier.pushType(newReferenceType(getArrayClassName(className)))
+	case opcode == 0xbe:
+		// arraylength
+		arrayType := verifier.popSlot()
+
+		if !arrayType.isArray() && arrayType.kind != typeNull {
+			verifier.fail("Bad type on operand stack in arraylength")
+		}
+
+		verifier.pushType(integerType)
+	case opcode == 0xbf:
+		// athrow
+		verifier.popType(newReferenceType("java/lang/Throwable"))
+
+		return false
+	case opcode == 0xc0:
+		// checkcast
+		className := verifier.getClassReference(verifier.readUint16(pc + 1)).className
+
+		verifier.popType(newReferenceType("java/lang/Object"))
+		verifier.pushType(newReferenceType(className))
+	case opcode == 0xc1:
+		// instanceof
+		verifier.getClassReference(verifier.readUint16(pc + 1))
+		verifier.popType(newReferenceType("java/lang/Object"))
+		verifier.pushType(integerType)
+	case opcode == 0xc2 || opcode == 0xc3:
+		// monitorenter, monitorexit
+		verifier.popReference()
+	case opcode == 0xc4:
+		return verifier.executeWideInstruction()
+	case opcode == 0xc5:
+		verifier.executeMultiANewArrayInstruction()
+	case opcode == 0xc6 || opcode == 0xc7:
+		// ifnull, ifnonnull
+		verifier.popReference()
+		branch(pc + verifier.readInt16(pc+1))
+	case opcode == 0xc8:
+		// goto_w
+		branch(pc + int(verifier.readInt32(pc+1)))
+
+		return false
+	default:
+		verifier.fail("Bad instruction")
+	}
+
+	return true
+}
+
+func (verifier *methodVerifier) executeLdcInstruction(opcode uint8) {
+	var index uint
+
+	if opcode == 0x12 {
+		index = uint(verifier.code[verifier.pc+1])
+	} else {
+		index = verifier.readUint16(verifier.pc + 1)
+	}
+
+	constant := verifier.getConstant(index)
+
+	if opcode == 0x14 {
+		// ldc2_w
+		switch constant.(type) {
+		case int64:
+			verifier.pushType(longType)
+		case float64:
+			verifier.pushType(doubleType)
+		case *DynamicConstantReference:
+			descriptor := constant.(*DynamicConstantReference).descriptor
+
+			if descriptor != "J" && descriptor != "D" {
+				verifier.fail("Illegal type in constant pool for ldc2_w")
+			}
+
+			verifier.pushType(newVerificationTypeFromDescriptor(descriptor))
+		default:
+			verifier.fail("Illegal type in constant pool for ldc2_w")
+		}
+
+		return
+	}
+
+	switch constant.(type) {
+	case int32:
+		verifier.pushType(integerType)
+	case float32:
+		verifier.pushType(floatType)
+	case string:
+		verifier.pushType(newReferenceType("java/lang/String"))
+	case *ClassReference:
+		verifier.pushType(newReferenceType("java/lang/Class"))
+	case *MethodTypeReference:
+		verifier.pushType(newReferenceType("java/lang/invoke/MethodType"))
+	case *MethodHandleReference:
+		verifier.pushType(newReferenceType("java/lang/invoke/MethodHandle"))
+	case *DynamicConstantReference:
+		descriptor := constant.(*DynamicConstantReference).descriptor
+
+		if descriptor == "J" || descriptor == "D" {
+			verifier.fail("Illegal type in constant pool for ldc")
+		}
+
+		verifier.pushType(newVerificationTypeFromDescriptor(descriptor))
+	default:
+		verifier.fail("Illegal type in constant pool for ldc")
+	}
+}
+
+// localVariableTypeIndex is the position of the instruction in its iload, lload, fload, dload, aload family
+func (verifier *methodVerifier) executeLoadInstruction(localVariableTypeIndex int, index uint) {
+	if localVariableTypeIndex == 4 {
+		local := verifier.getLocal(index, topType)
+
+		if !local.isReference() {
+			verifier.fail("Bad local variable type")
+		}
+
+		verifier.pushType(local)
+
+		return
+	}
+
+	localVariableType := localVariableTypes[localVariableTypeIndex]
+
+	verifier.getLocal(index, localVariableType)
+	verifier.pushType(localVariableType)
+}
+
+func (verifier *methodVerifier) executeStoreInstruction(localVariableTypeIndex int, index uint) {
+	if localVariableTypeIndex == 4 {
+		value := verifier.popSlot()
+
+		if !value.isReference() && value.kind != typeReturnAddress {
+			verifier.fail("Bad type on operand stack in astore")
+		}
+
+		verifier.setLocal(index, value)
+
+		return
+	}
+
+	localVariableType := localVariableTypes[localVariableTypeIndex]
+
+	verifier.popType(localVariableType)
+	verifier.setLocal(index, localVariableType)
+}
+
+func (verifier *methodVerifier) executeWideInstruction() bool {
+	pc := verifier.pc
+	opcode := verifier.code[pc+1]
+	index := verifier.readUint16(pc + 2)
+
+	switch {
+	case opcode == 0x84:
+		verifier.getLocal(index, integerType)
+	case opcode >= 0x15 && opcode <= 0x19:
+		verifier.executeLoadInstruction(int(opcode-0x15), index)
+	case opcode >= 0x36 && opcode <= 0x3a:
+		verifier.executeStoreInstruction(int(opcode-0x36), index)
+	default:
+		verifier.executeRetInstruction(index)
+
+		return false
+	}
+
+	return true
+}
+
+func (verifier *methodVerifier) checkSubroutinesAllowed() {
+	if verifier.class.majorVersion >= typeCheckingMajorVersion {
+		verifier.fail("jsr and ret are not allowed in class files that are type checked")
+	}
+}
+
+// The return address is pushed for the subroutine, the instruction after jsr is reached through ret
+func (verifier *methodVerifier) executeJsrInstruction(branch func(targetPC int)) {
+	verifier.checkSubroutinesAllowed()
+
+	targetPC := verifier.getJsrTarget(verifier.pc)
+
+	verifier.pushType(newReturnAddressType(targetPC))
+	branch(targetPC)
+}
+
+func (verifier *methodVerifier) getJsrTarget(pc int) int {
+	if verifier.code[pc] == 0xc9 {
+		// jsr_w
+		return pc + int(verifier.readInt32(pc+1))
+	}
+
+	return pc + verifier.readInt16(pc+1)
+}
+
+func (verifier *methodVerifier) executeRetInstruction(index uint) {
+	verifier.checkSubroutinesAllowed()
+
+	if verifier.getLocal(index, topType).kind != typeReturnAddress {
+		verifier.fail("Expecting a return address in the local variable of ret")
+	}
+}
+
+// componentDescriptors are the primitive components the array may have, empty for arrays of references
+func (verifier *methodVerifier) popArray(componentDescriptors string) verificationType {
+	arrayType := verifier.popSlot()
+
+	if arrayType.kind == typeNull {
+		return arrayType
+	}
+
+	if !arrayType.isArray() {
+		verifier.fail("Bad type on operand stack, expecting an array")
+	}
+
+	componentDescriptor := arrayType.className[1:]
+
+	if componentDescriptors == "" && componentDescriptor[0] != 'L' && componentDescriptor[0] != '[' ||
+		componentDescriptors != "" && (len(componentDescriptor) != 1 || !strings.Contains(componentDescriptors, componentDescriptor)) {
+		verifier.fail("Bad type on operand stack, array of wrong component type")
+	}
+
+	return arrayType
+}
+
+func (verifier *methodVerifier) executeArrayLoadInstruction(componentDescriptors string) {
+	verifier.popType(integerType)
+
+	arrayType := verifier.popArray(componentDescriptors)
+
+	if componentDescriptors != "" {
+		verifier.pushType(newVerificationTypeFromDescriptor(componentDescriptors[:1]))
+	} else if arrayType.kind == typeNull {
+		verifier.pushType(nullType)
+	} else {
+		verifier.pushType(arrayType.getComponentType())
+	}
+}
+
+// Whether the value of aastore fits the component type is only known at runtime
+func (verifier *methodVerifier) executeArrayStoreInstruction(componentDescriptors string) {
+	if componentDescriptors != "" {
+		verifier.popType(newVerificationTypeFromDescriptor(componentDescriptors[:1]))
+	} else {
+		verifier.popType(newReferenceType("java/lang/Object"))
+	}
+
+	verifier.popType(integerType)
+	verifier.popArray(componentDescriptors)
+}
+
+// pop, pop2, dup, dup_x1, dup_x2, dup2, dup2_x1, dup2_x2 and swap move slots, without splitting a long or double
+func (verifier *methodVerifier) executeStackInstruction(opcode uint8) {
+	switch opcode {
+	case 0x57:
+		verifier.popSlots(1)
+	case 0x58:
+		verifier.popSlots(2)
+	case 0x59:
+		value := verifier.popSlots(1)
+		verifier.pushSlots(value, value)
+	case 0x5a:
+		value1 := verifier.popSlots(1)
+		value2 := verifier.popSlots(1)
+		verifier.pushSlots(value1, value2, value1)
+	case 0x5b:
+		value1 := verifier.popSlots(1)
+		value2 := verifier.popSlots(2)
+		verifier.pushSlots(value1, value2, value1)
+	case 0x5c:
+		value := verifier.popSlots(2)
+		verifier.pushSlots(value, value)
+	case 0x5d:
+		value1 := verifier.popSlots(2)
+		value2 := verifier.popSlots(1)
+		verifier.pushSlots(value1, value2, value1)
+	case 0x5e:
+		value1 := verifier.popSlots(2)
+		value2 := verifier.popSlots(2)
+		verifier.pushSlots(value1, value2, value1)
+	case 0x5f:
+		value1 := verifier.popSlots(1)
+		value2 := verifier.popSlots(1)
+		verifier.pushSlots(value1, value2)
+	}
+}
+
+func (verifier *methodVerifier) executeSwitchInstruction(opcode uint8, branch func(targetPC int)) {
+	verifier.popType(integerType)
+
+	if opcode == 0xab {
+		// lookupswitch, the keys have to be sorted
+		basePC := verifier.pc + 1 + verifier.getSwitchPadding(verifier.pc)
+		pairsCount := int(verifier.readInt32(basePC + 4))
+
+		for i := 1; i < pairsCount; i++ {
+			if verifier.readInt32(basePC+8+i*8) <= verifier.readInt32(basePC+i*8) {
+				verifier.fail("Bad lookupswitch instruction")
+			}
+		}
+	}
+
+	for _, targetPC := range verifier.getSwitchTargets(verifier.pc) {
+		branch(targetPC)
+	}
+}
+
+func (verifier *methodVerifier) executeReturnInstruction(opcode uint8) {
+	if opcode == 0xb1 {
+		// return
+		if verifier.returnDescriptor != "V" {
+			verifier.fail("Method expects a return value")
+		}
+
+		if verifier.frame.isThisUninitialized {
+			verifier.fail("Constructor must call super() or this() before return")
+		}
+
+		return
+	}
+
+	if verifier.returnDescriptor == "V" {
+		verifier.fail("Method does not expect a return value")
+	}
+
+	returnType := newVerificationTypeFromDescriptor(verifier.returnDescriptor)
+
+	// ireturn, lreturn, freturn, dreturn, areturn
+	isMatched := returnType.kind == []int{typeInteger, typeLong, typeFloat, typeDouble, typeReference}[opcode-0xac]
+
+	if !isMatched {
+		verifier.fail("Bad return type")
+	}
+
+	verifier.popType(returnType)
+}
+
+func (verifier *methodVerifier) executeFieldInstruction(opcode uint8) {
+	fieldReference, ok := verifier.getConstant(verifier.readUint16(verifier.pc + 1)).(*FieldReference)
+
+	if !ok {
+		verifier.fail("Illegal constant pool index for field instruction")
+	}
+
+	fieldType := newVerificationTypeFromDescriptor(fieldReference.descriptor)
+	classType := newReferenceType(fieldReference.className)
+
+	switch opcode {
+	case 0xb2:
+		// getstatic
+		verifier.pushType(fieldType)
+	case 0xb3:
+		// putstatic
+		verifier.popType(fieldType)
+	case 0xb4:
+		// getfield
+		verifier.popType(classType)
+		verifier.pushType(fieldType)
+	case 0xb5:
+		// putfield, a constructor may set the fields of its own class before calling super()
+		verifier.popType(fieldType)
+
+		if verifier.peekSlot().kind == typeUninitializedThis && fieldReference.className == verifier.class.name &&
+			verifier.class.GetField(fieldReference.name, fieldReference.descriptor, false) != nil {
+			verifier.popSlot()
+		} else {
+			verifier.popType(classType)
+		}
+	}
+}
+
+func (verifier *methodVerifier) executeInvokeInstruction(opcode uint8) {
+	pc := verifier.pc
+	className, name, descriptor := verifier.getInvokedMethod(opcode, verifier.readUint16(pc+1))
+
+	if strings.HasPrefix(name, "<") && (name != "<init>" || opcode != 0xb7) {
+		verifier.fail("Illegal call to internal method " + name)
+	}
+
+	methodDescriptor := parseMethodDescriptor(descriptor)
+	argumentSlotsCount := 1
+
+	for i := len(methodDescriptor.parameterTypes) - 1; i >= 0; i-- {
+		parameterType := newVerificationTypeFromDescriptor(methodDescriptor.parameterTypes[i])
+		verifier.popType(parameterType)
+
+		if parameterType.isCategory2() {
+			argumentSlotsCount += 2
+		} else {
+			argumentSlotsCount++
+		}
+	}
+
+	switch opcode {
+	case 0xb6:
+		// invokevirtual
+		verifier.popType(newReferenceType(className))
+	case 0xb7:
+		// invokespecial
+		if name == "<init>" {
+			verifier.initializeObject(className)
+		} else {
+			verifier.popType(newReferenceType(verifier.class.name))
+		}
+	case 0xb9:
+		// invokeinterface
+		if int(verifier.code[pc+3]) != argumentSlotsCount || verifier.code[pc+4] != 0 {
+			verifier.fail("Inconsistent args count operand in invokeinterface")
+		}
+
+		verifier.popType(newReferenceType(className))
+	case 0xba:
+		// invokedynamic
+		if verifier.code[pc+3] != 0 || verifier.code[pc+4] != 0 {
+			verifier.fail("Third and fourth operand bytes of invokedynamic must be zero")
+		}
+	}
+
+	if methodDescriptor.returnType != "V" {
+		verifier.pushType(newVerificationTypeFromDescriptor(methodDescriptor.returnType))
+	}
+}
+
+// Class name, name and descriptor of the method an invoke instruction refers to
+func (verifier *methodVerifier) getInvokedMethod(opcode uint8, index uint) (string, string, string) {
+	constant := verifier.getConstant(index)
+
+	switch constant.(type) {
+	case *MethodReference:
+		if opcode != 0xb9 && opcode != 0xba {
+			methodReference := constant.(*MethodReference)
+
+			return methodReference.className, methodReference.name, methodReference.descriptor
+		}
+	case *InterfaceMethodReference:
+		// Static and private interface methods are called with invokestatic and invokespecial
+		if opcode != 0xb6 && opcode != 0xba {
+			interfaceMethodReference := constant.(*InterfaceMethodReference)
+
+			return interfaceMethodReference.className, interfaceMethodReference.name, interfaceMethodReference.descriptor
+		}
+	case *InvokeDynamicReference:
+		if opcode == 0xba {
+			invokeDynamicReference := constant.(*InvokeDynamicReference)
+
+			return "", invokeDynamicReference.name, invokeDynamicReference.descriptor
+		}
+	}
+
+	verifier.fail("Illegal constant pool index for invoke instruction")
+
+	return "", "", ""
+}
+
+/*
+invokespecial <init> turns every copy of the uninitialized receiver into the initialized object,
+uninitializedThis may only be passed to a constructor of this class or of its super class
+*/
+func (verifier *methodVerifier) initializeObject(className string) {
+	receiver := verifier.popSlot()
+	var initializedType verificationType
+
+	switch receiver.kind {
+	case typeUninitializedThis:
+		if className != verifier.class.name && className != verifier.class.superClassName {
+			verifier.fail("Bad <init> method call")
+		}
+
+		initializedType = newReferenceType(verifier.class.name)
+		verifier.frame.isThisUninitialized = false
+	case typeUninitialized:
+		newClassName := verifier.getClassReference(verifier.readUint16(receiver.offset + 1)).className
+
+		if className != newClassName {
+			verifier.fail("Call to wrong <init> method")
+		}
+
+		initializedType = newReferenceType(newClassName)
+	default:
+		verifier.fail("Bad operand type when invoking <init>")
+	}
+
+	verifier.replaceType(receiver, initializedType)
+}
+
+func (verifier *methodVerifier) executeNewInstruction() {
+	className := verifier.getClassReference(verifier.readUint16(verifier.pc + 1)).className
+
+	if className[0] == '[' {
+		verifier.fail("Illegal use of new with an array class")
+	}
+
+	uninitializedType := newUninitializedType(verifier.pc)
+
+	for _, stackType := range verifier.frame.stack {
+		if stackType == uninitializedType {
+			verifier.fail("Uninitialized object exists on backward branch")
+		}
+	}
+
+	verifier.replaceType(uninitializedType, topType)
+	verifier.pushType(uninitializedType)
+}
+
+func (verifier *methodVerifier) executeMultiANewArrayInstruction() {
+	className := verifier.getClassReference(verifier.readUint16(verifier.pc + 1)).className
+	dimensions := int(verifier.code[verifier.pc+3])
+
+	if dimensions == 0 || len(className)-len(strings.TrimLeft(className, "[")) < dimensions {
+		verifier.fail("Illegal dimension in multianewarray")
+	}
+
+	for i := 0; i < dimensions; i++ {
+		verifier.popType(integerType)
+	}
+
+	verifier.pushType(newReferenceType(className))
+}
+
+func (verifier *methodVerifier) replaceType(fromType, toType verificationType) {
+	for i, local := range verifier.frame.locals {
+		if local == fromType {
+			verifier.frame.locals[i] = toType
+		}
+	}
+
+	for i, stackType := range verifier.frame.stack {
+		if stackType == fromType {
+			verifier.frame.stack[i] = toType
+		}
+	}
+}
+
+func (verifier *methodVerifier) getConstant(index uint) Constant {
+	constants := verifier.class.constantPool.constants
+
+	if index == 0 || index >= uint(len(constants)) || constants[index] == nil {
+		verifier.fail(fmt.Sprintf("Illegal constant pool index %d", index))
+	}
+
+	return constants[index]
+}
+
+func (verifier *methodVerifier) getClassReference(index uint) *ClassReference {
+	classReference, ok := verifier.getConstant(index).(*ClassReference)
+
+	if !ok {
+		verifier.fail(fmt.Sprintf("Illegal constant pool index %d, expecting a class", index))
+	}
+
+	return classReference
+}
+
+func (verifier *methodVerifier) pushType(pushType verificationType) {
+	if pushType.isCategory2() {
+		verifier.pushSlots([]verificationType{pushType, pushType.getSecondSlot()})
+	} else {
+		verifier.pushSlots([]verificationType{pushType})
+	}
+}
+
+func (verifier *methodVerifier) pushSlots(slotsList ...[]verificationType) {
+	for _, slots := range slotsList {
+		verifier.frame.stack = append(verifier.frame.stack, slots...)
+	}
+
+	if uint(len(verifier.frame.stack)) > verifier.method.maxStackSize {
+		verifier.fail("Operand stack overflow")
+	}
+}
+
+// The slots below the popped ones must not be the first half of a long or double
+func (verifier *methodVerifier) popSlots(count int) []verificationType {
+	stackSize := len(verifier.frame.stack)
+
+	if stackSize < count {
+		verifier.fail("Operand stack underflow")
+	}
+
+	slots := append([]verificationType{}, verifier.frame.stack[stackSize-count:]...)
+
+	if slots[0].isSecondSlot() {
+		verifier.fail("Bad type on operand stack, long or double split")
+	}
+
+	verifier.frame.stack = verifier.frame.stack[:stackSize-count]
+
+	return slots
+}
+
+func (verifier *methodVerifier) popSlot() verificationType {
+	return verifier.popSlots(1)[0]
+}
+
+func (verifier *methodVerifier) peekSlot() verificationType {
+	if len(verifier.frame.stack) == 0 {
+		verifier.fail("Operand stack underflow")
+	}
+
+	return verifier.frame.stack[len(verifier.frame.stack)-1]
+}
+
+func (verifier *methodVerifier) popType(expectedType verificationType) verificationType {
+	if expectedType.isCategory2() {
+		slots := verifier.popSlots(2)
+
+		if slots[0] != expectedType || slots[1] != expectedType.getSecondSlot() {
+			verifier.fail(fmt.Sprintf("Bad type on operand stack, expecting %s", expectedType.toString()))
+		}
+
+		return slots[0]
+	}
+
+	popType := verifier.popSlot()
+
+	if !verifier.class.classLoader.isAssignable(popType, expectedType) {
+		verifier.fail(fmt.Sprintf("Bad type on operand stack, %s is not assignable to %s", popType.toString(), expectedType.toString()))
+	}
+
+	return popType
+}
+
+func (verifier *methodVerifier) popReference() verificationType {
+	popType := verifier.popSlot()
+
+	if !popType.isReference() {
+		verifier.fail("Bad type on operand stack, expecting a reference")
+	}
+
+	return popType
+}
+
+// expectedType top accepts any single slot value
+func (verifier *methodVerifier) getLocal(index uint, expectedType verificationType) verificationType {
+	locals := verifier.frame.locals
+
+	if index >= uint(len(locals)) || expectedType.isCategory2() && index+1 >= uint(len(locals)) {
+		verifier.fail(fmt.Sprintf("Illegal local variable number %d", index))
+	}
+
+	local := locals[index]
+
+	if expectedType.isCategory2() && (local != expectedType || locals[index+1] != expectedType.getSecondSlot()) ||
+		!expectedType.isCategory2() && (local.isCategory2() || local.isSecondSlot() || !verifier.class.classLoader.isAssignable(local, expectedType)) {
+		verifier.fail(fmt.Sprintf("Bad local variable type, expecting %s", expectedType.toString()))
+	}
+
+	return local
+}
+
+// Overwriting half of a long or double leaves the other half unusable
+func (verifier *methodVerifier) setLocal(index uint, localType verificationType) {
+	locals := verifier.frame.locals
+	size := uint(1)
+
+	if localType.isCategory2() {
+		size = 2
+	}
+
+	if index+size > uint(len(locals)) {
+		verifier.fail(fmt.Sprintf("Illegal local variable number %d", index))
+	}
+
+	if index > 0 && locals[index-1].isCategory2() {
+		locals[index-1] = topType
+	}
+
+	if index+size < uint(len(locals)) && locals[index+size].isSecondSlot() {
+		locals[index+size] = topType
+	}
+
+	locals[index] = localType
+
+	if localType.isCategory2() {
+		locals[index+1] = localType.getSecondSlot()
+	}
+}
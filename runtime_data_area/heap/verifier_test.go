@@ -0,0 +1,114 @@
+package heap
+
+import "testing"
+
+type verifierTest struct {
+	name         string
+	majorVersion uint16
+	descriptor   string
+	maxStack     uint
+	maxLocals    uint
+	code         []byte
+	isValid      bool
+}
+
+var verifierTests = []verifierTest{
+	// iconst_1, ireturn
+	{"int return", 52, "()I", 1, 0, []byte{0x04, 0xac}, true},
+	// aconst_null, ireturn
+	{"null returned as int", 52, "()I", 1, 0, []byte{0x01, 0xac}, false},
+	// iconst_1
+	{"falls off the end", 52, "()V", 1, 0, []byte{0x04}, false},
+	// iconst_1, iconst_1, pop2, return
+	{"stack overflow", 52, "()V", 1, 0, []byte{0x04, 0x04, 0x58, 0xb1}, false},
+	// pop, return
+	{"stack underflow", 52, "()V", 1, 0, []byte{0x57, 0xb1}, false},
+	// iload_0, ireturn
+	{"int parameter", 52, "(I)I", 1, 1, []byte{0x1a, 0xac}, true},
+	// iload_1, ireturn
+	{"local out of range", 52, "(I)I", 1, 1, []byte{0x1b, 0xac}, false},
+	// aload_0, ireturn
+	{"int loaded as reference", 52, "(I)I", 1, 1, []byte{0x2a, 0xac}, false},
+	// lconst_0, lstore_0, lload_0, lreturn
+	{"long in two locals", 52, "()J", 2, 2, []byte{0x09, 0x3f, 0x1e, 0xad}, true},
+	// lconst_0, lstore_0, lload_0, lreturn
+	{"long in one local", 52, "()J", 2, 1, []byte{0x09, 0x3f, 0x1e, 0xad}, false},
+	// lconst_0, lstore_0, iload_1, ireturn
+	{"second half of a long", 52, "()I", 2, 2, []byte{0x09, 0x3f, 0x1b, 0xac}, false},
+	// iconst_0, ifeq +5, iconst_1, ireturn, iconst_2, ireturn
+	{"branch without stack map", 52, "()I", 1, 0, []byte{0x03, 0x99, 0x00, 0x05, 0x04, 0xac, 0x05, 0xac}, false},
+	{"branch by type inference", 49, "()I", 1, 0, []byte{0x03, 0x99, 0x00, 0x05, 0x04, 0xac, 0x05, 0xac}, true},
+	// goto +1 into its own operand
+	{"branch into an instruction", 49, "()V", 0, 0, []byte{0xa7, 0x00, 0x01, 0xb1}, false},
+	// iconst_0, ifeq +7, iconst_1, goto +4, aconst_null, ireturn
+	{"merged int and null", 49, "()I", 1, 0, []byte{0x03, 0x99, 0x00, 0x07, 0x04, 0xa7, 0x00, 0x04, 0x01, 0xac}, false},
+	// jsr +5, iconst_1, ireturn, astore_0, ret 0
+	{"subroutine", 49, "()I", 1, 1, []byte{0xa8, 0x00, 0x05, 0x04, 0xac, 0x4b, 0xa9, 0x00}, true},
+	{"subroutine in a type checked class", 51, "()I", 1, 1, []byte{0xa8, 0x00, 0x05, 0x04, 0xac, 0x4b, 0xa9, 0x00}, false},
+	// jsr +5, iconst_1, ireturn, iconst_0, ret 0
+	{"ret without return address", 49, "()I", 2, 1, []byte{0xa8, 0x00, 0x05, 0x04, 0xac, 0x03, 0xa9, 0x00}, false},
+}
+
+func TestVerifyClass(t *testing.T) {
+	classLoader := newTestClassLoader(t)
+
+	for _, test := range verifierTests {
+		err := verifyTestMethod(classLoader, test)
+
+		if test.isValid && err != nil {
+			t.Errorf("%s: unexpected %v", test.name, err)
+		}
+
+		if !test.isValid && err == nil {
+			t.Errorf("%s: verified", test.name)
+		}
+
+		if err != nil {
+			t.Logf("%s: %v", test.name, err)
+		}
+	}
+}
+
+// A static method of its own class, with no constant pool entries
+func verifyTestMethod(classLoader *ClassLoader, test verifierTest) (err error) {
+	class := &Class{
+		majorVersion: test.majorVersion,
+		accessFlags:  ACC_PUBLIC | ACC_SUPER,
+		name:         "T",
+		classLoader:  classLoader,
+		superClass:   classLoader.loadedClasses["java/lang/Object"],
+	}
+
+	class.constantPool = &ConstantPool{class, []Constant{nil}}
+
+	method := &Method{}
+	method.class = class
+	method.accessFlags = ACC_PUBLIC | ACC_STATIC
+	method.name = "test"
+	method.descriptor = test.descriptor
+	method.maxStackSize = test.maxStack
+	method.maxNumberOfLocalVariables = test.maxLocals
+	method.code = test.code
+	method.calculateArgumentsCount(parseMethodDescriptor(test.descriptor).parameterTypes)
+	class.methods = []*Method{method}
+
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		javaException, ok := r.(*JavaException)
+
+		if !ok || javaException.GetClassName() != "java/lang/VerifyError" {
+			panic(r)
+		}
+
+		err = javaException
+	}()
+
+	verifyClass(class)
+
+	return nil
+}
@@ -0,0 +1,333 @@
+package heap
+
+import "fmt"
+
+// A jsr target, the instructions before its ret run with the locals of every caller merged
+type subroutine struct {
+	callerPCs []int
+	// Locals the subroutine or the subroutines it calls store to
+	storedLocals        map[uint]bool
+	calledSubroutinePCs []int
+	// Merge of the frames at its ret instructions
+	returnFrame *verificationFrame
+}
+
+/*
+Data flow analysis for class files without a StackMapTable: the frames flowing into every
+instruction are merged until nothing changes. After a ret the locals the subroutine did not
+store to are the ones of the jsr that called it
+*/
+func (verifier *methodVerifier) inferTypes() {
+	verifier.checkExceptionTable()
+	verifier.findSubroutines()
+
+	verifier.inferredFrames = map[int]*verificationFrame{}
+	verifier.isPending = map[int]bool{}
+	verifier.mergeFrame(0, verifier.frame)
+
+	for len(verifier.pendingPCs) > 0 {
+		pc := verifier.pendingPCs[0]
+		verifier.pendingPCs = verifier.pendingPCs[1:]
+		verifier.isPending[pc] = false
+		verifier.pc = pc
+		verifier.frame = verifier.inferredFrames[pc].copy()
+
+		verifier.mergeExceptionHandlers()
+
+		isFallThrough := verifier.executeInstruction(verifier.mergeBranchTarget)
+
+		if isFallThrough && verifier.isStoreInstruction() {
+			verifier.mergeExceptionHandlers()
+		}
+
+		switch {
+		case isFallThrough:
+			verifier.mergeFrame(pc+verifier.instructionLengths[pc], verifier.frame)
+		case verifier.code[pc] == 0xa8 || verifier.code[pc] == 0xc9:
+			subroutine := verifier.subroutines[verifier.getJsrTarget(pc)]
+
+			if subroutine.returnFrame != nil {
+				verifier.returnFromSubroutine(subroutine, pc)
+			}
+		case verifier.isRetInstruction():
+			verifier.executeSubroutineReturn()
+		}
+	}
+}
+
+func (verifier *methodVerifier) mergeBranchTarget(targetPC int) {
+	verifier.checkInstructionOffset(targetPC, "Illegal target of jump or branch")
+	verifier.mergeFrame(targetPC, verifier.frame)
+}
+
+func (verifier *methodVerifier) mergeExceptionHandlers() {
+	for _, exceptionHandler := range verifier.method.exceptionTable {
+		if verifier.pc < exceptionHandler.startPC || verifier.pc >= exceptionHandler.endPC {
+			continue
+		}
+
+		exceptionFrame := &verificationFrame{
+			locals:              verifier.frame.locals,
+			stack:               []verificationType{getCatchVerificationType(exceptionHandler)},
+			isThisUninitialized: verifier.frame.isThisUninitialized,
+		}
+
+		verifier.mergeFrame(exceptionHandler.handlerPC, exceptionFrame)
+	}
+}
+
+// The instruction is analyzed again when the frame flowing into it changes
+func (verifier *methodVerifier) mergeFrame(pc int, frame *verificationFrame) {
+	if pc >= len(verifier.code) {
+		verifier.fail("Falling off the end of the code")
+	}
+
+	inferredFrame, ok := verifier.inferredFrames[pc]
+
+	if ok && !verifier.mergeFrameInto(inferredFrame, frame) {
+		return
+	}
+
+	if !ok {
+		verifier.inferredFrames[pc] = frame.copy()
+	}
+
+	if !verifier.isPending[pc] {
+		verifier.isPending[pc] = true
+		verifier.pendingPCs = append(verifier.pendingPCs, pc)
+	}
+}
+
+// Locals that do not merge become unusable, the operand stacks have to agree
+func (verifier *methodVerifier) mergeFrameInto(toFrame, fromFrame *verificationFrame) bool {
+	if len(toFrame.stack) != len(fromFrame.stack) {
+		verifier.fail("Inconsistent stack height")
+	}
+
+	classLoader := verifier.class.classLoader
+	isChanged := false
+
+	for i, local := range fromFrame.locals {
+		mergedType := classLoader.mergeTypes(toFrame.locals[i], local)
+
+		if mergedType != toFrame.locals[i] {
+			toFrame.locals[i] = mergedType
+			isChanged = true
+		}
+	}
+
+	for i, stackType := range fromFrame.stack {
+		mergedType := classLoader.mergeTypes(toFrame.stack[i], stackType)
+
+		if mergedType.kind == typeTop && stackType.kind != typeTop {
+			verifier.fail("Mismatched stack types")
+		}
+
+		if mergedType != toFrame.stack[i] {
+			toFrame.stack[i] = mergedType
+			isChanged = true
+		}
+	}
+
+	if fromFrame.isThisUninitialized && !toFrame.isThisUninitialized {
+		toFrame.isThisUninitialized = true
+		isChanged = true
+	}
+
+	return isChanged
+}
+
+func (verifier *methodVerifier) isRetInstruction() bool {
+	pc := verifier.pc
+
+	return verifier.code[pc] == 0xa9 || verifier.code[pc] == 0xc4 && verifier.code[pc+1] == 0xa9
+}
+
+func (verifier *methodVerifier) executeSubroutineReturn() {
+	pc := verifier.pc
+	index := uint(verifier.code[pc+1])
+
+	if verifier.code[pc] == 0xc4 {
+		index = verifier.readUint16(pc + 2)
+	}
+
+	subroutine := verifier.subroutines[verifier.frame.locals[index].offset]
+
+	if subroutine.returnFrame == nil {
+		subroutine.returnFrame = verifier.frame.copy()
+	} else if !verifier.mergeFrameInto(subroutine.returnFrame, verifier.frame) {
+		return
+	}
+
+	for _, callerPC := range subroutine.callerPCs {
+		if verifier.inferredFrames[callerPC] != nil {
+			verifier.returnFromSubroutine(subroutine, callerPC)
+		}
+	}
+}
+
+func (verifier *methodVerifier) returnFromSubroutine(subroutine *subroutine, callerPC int) {
+	callerFrame := verifier.inferredFrames[callerPC]
+	frame := subroutine.returnFrame.copy()
+
+	for i, local := range callerFrame.locals {
+		if !subroutine.storedLocals[uint(i)] {
+			frame.locals[i] = local
+		}
+	}
+
+	verifier.mergeFrame(callerPC+verifier.instructionLengths[callerPC], frame)
+}
+
+// Subroutines are found from the jsr instructions, before any types are known
+func (verifier *methodVerifier) findSubroutines() {
+	verifier.subroutines = map[int]*subroutine{}
+
+	for pc := 0; pc < len(verifier.code); pc += verifier.instructionLengths[pc] {
+		opcode := verifier.code[pc]
+
+		if opcode != 0xa8 && opcode != 0xc9 {
+			continue
+		}
+
+		verifier.pc = pc
+		targetPC := verifier.getJsrTarget(pc)
+		verifier.checkInstructionOffset(targetPC, "Illegal target of jsr")
+
+		if verifier.subroutines[targetPC] == nil {
+			verifier.subroutines[targetPC] = &subroutine{storedLocals: map[uint]bool{}}
+		}
+
+		verifier.subroutines[targetPC].callerPCs = append(verifier.subroutines[targetPC].callerPCs, pc)
+	}
+
+	for subroutinePC, subroutine := range verifier.subroutines {
+		verifier.findStoredLocals(subroutinePC, subroutine)
+	}
+
+	for subroutinePC, subroutine := range verifier.subroutines {
+		verifier.addCalledSubroutineLocals(subroutine, subroutinePC, map[int]bool{})
+	}
+
+	verifier.pc = 0
+}
+
+// Walks the instructions reachable from the subroutine entry without entering the subroutines it calls
+func (verifier *methodVerifier) findStoredLocals(subroutinePC int, subroutine *subroutine) {
+	isVisited := map[int]bool{}
+	pendingPCs := []int{subroutinePC}
+
+	for len(pendingPCs) > 0 {
+		pc := pendingPCs[len(pendingPCs)-1]
+		pendingPCs = pendingPCs[:len(pendingPCs)-1]
+
+		if pc < 0 || pc >= len(verifier.code) || verifier.instructionLengths[pc] == 0 || isVisited[pc] {
+			continue
+		}
+
+		isVisited[pc] = true
+
+		for _, index := range verifier.getStoredLocals(pc) {
+			subroutine.storedLocals[index] = true
+		}
+
+		if verifier.code[pc] == 0xa8 || verifier.code[pc] == 0xc9 {
+			subroutine.calledSubroutinePCs = append(subroutine.calledSubroutinePCs, verifier.getJsrTarget(pc))
+		}
+
+		pendingPCs = append(pendingPCs, verifier.getSuccessors(pc)...)
+	}
+}
+
+func (verifier *methodVerifier) addCalledSubroutineLocals(subroutine *subroutine, subroutinePC int, isCalling map[int]bool) {
+	isCalling[subroutinePC] = true
+
+	for _, calledSubroutinePC := range verifier.subroutines[subroutinePC].calledSubroutinePCs {
+		if isCalling[calledSubroutinePC] {
+			verifier.fail(fmt.Sprintf("Recursive call to the subroutine at %d", calledSubroutinePC))
+		}
+
+		for index := range verifier.subroutines[calledSubroutinePC].storedLocals {
+			subroutine.storedLocals[index] = true
+		}
+
+		verifier.addCalledSubroutineLocals(subroutine, calledSubroutinePC, isCalling)
+	}
+
+	isCalling[subroutinePC] = false
+}
+
+// Indices of the locals a store instruction writes
+func (verifier *methodVerifier) getStoredLocals(pc int) []uint {
+	opcode := verifier.code[pc]
+	var index uint
+	var localVariableTypeIndex int
+
+	switch {
+	case opcode >= 0x36 && opcode <= 0x3a:
+		index = uint(verifier.code[pc+1])
+		localVariableTypeIndex = int(opcode - 0x36)
+	case opcode >= 0x3b && opcode <= 0x4e:
+		index = uint(opcode-0x3b) % 4
+		localVariableTypeIndex = int(opcode-0x3b) / 4
+	case opcode == 0xc4 && verifier.code[pc+1] >= 0x36 && verifier.code[pc+1] <= 0x3a:
+		index = verifier.readUint16(pc + 2)
+		localVariableTypeIndex = int(verifier.code[pc+1] - 0x36)
+	default:
+		return nil
+	}
+
+	// lstore and dstore
+	if localVariableTypeIndex == 1 || localVariableTypeIndex == 3 {
+		return []uint{index, index + 1}
+	}
+
+	return []uint{index}
+}
+
+// Instructions control can pass to from pc, jsr continues after itself like the subroutine returned
+func (verifier *methodVerifier) getSuccessors(pc int) []int {
+	opcode := verifier.code[pc]
+	nextPC := pc + verifier.instructionLengths[pc]
+
+	switch {
+	case opcode >= 0x99 && opcode <= 0xa6 || opcode == 0xc6 || opcode == 0xc7:
+		return []int{nextPC, pc + verifier.readInt16(pc+1)}
+	case opcode == 0xa7:
+		return []int{pc + verifier.readInt16(pc+1)}
+	case opcode == 0xc8:
+		return []int{pc + int(verifier.readInt32(pc+1))}
+	case opcode == 0xaa || opcode == 0xab:
+		return verifier.getSwitchTargets(pc)
+	case opcode >= 0xac && opcode <= 0xb1 || opcode == 0xbf || opcode == 0xa9:
+		return nil
+	case opcode == 0xc4 && verifier.code[pc+1] == 0xa9:
+		return nil
+	default:
+		return []int{nextPC}
+	}
+}
+
+func (verifier *methodVerifier) getSwitchTargets(pc int) []int {
+	basePC := pc + 1 + verifier.getSwitchPadding(pc)
+	targetPCs := []int{pc + int(verifier.readInt32(basePC))}
+
+	if verifier.code[pc] == 0xaa {
+		low := verifier.readInt32(basePC + 4)
+		high := verifier.readInt32(basePC + 8)
+
+		for i := 0; i <= int(high-low); i++ {
+			targetPCs = append(targetPCs, pc+int(verifier.readInt32(basePC+12+i*4)))
+		}
+
+		return targetPCs
+	}
+
+	pairsCount := int(verifier.readInt32(basePC + 4))
+
+	for i := 0; i < pairsCount; i++ {
+		targetPCs = append(targetPCs, pc+int(verifier.readInt32(basePC+12+i*8)))
+	}
+
+	return targetPCs
+}
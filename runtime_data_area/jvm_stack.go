@@ -1,9 +1,15 @@
 package runtime_data_area
 
+import "github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+
+// Extra frames for constructing a StackOverflowError once the stack is full
+const reservedFramesCount = 64
+
 type JVMStack struct {
-	maxSize  uint
-	size     uint
-	topFrame *Frame
+	maxSize         uint
+	size            uint
+	topFrame        *Frame
+	isReservedInUse bool
 }
 
 func newJVMStack(maxStackSize uint) *JVMStack {
@@ -14,7 +20,15 @@ func newJVMStack(maxStackSize uint) *JVMStack {
 
 func (jvmStack *JVMStack) PushFrame(frame *Frame) {
 	if jvmStack.size >= jvmStack.maxSize {
-		panic("java.lang.StackOverflowError")
+		if jvmStack.isReservedInUse {
+			if jvmStack.size >= jvmStack.maxSize+reservedFramesCount {
+				panic(heap.NewJavaException("java/lang/StackOverflowError", ""))
+			}
+		} else {
+			jvmStack.isReservedInUse = true
+
+			panic(heap.NewJavaException("java/lang/StackOverflowError", ""))
+		}
 	}
 
 	if jvmStack.topFrame != nil {
@@ -35,6 +49,10 @@ func (jvmStack *JVMStack) PopFrame() *Frame {
 	topFrame.lower = nil
 	jvmStack.size--
 
+	if jvmStack.size < jvmStack.maxSize {
+		jvmStack.isReservedInUse = false
+	}
+
 	return topFrame
 }
 
@@ -56,12 +74,15 @@ func (jvmStack *JVMStack) GetFrames() []*Frame {
 	return frames
 }
 
-func (jvmStack *JVMStack) IsEmpty() bool {
-	return jvmStack.topFrame == nil
+// Frames that can still be pushed, counting the reserved ones
+func (jvmStack *JVMStack) GetFreeFramesCount() uint {
+	if jvmStack.size >= jvmStack.maxSize+reservedFramesCount {
+		return 0
+	}
+
+	return jvmStack.maxSize + reservedFramesCount - jvmStack.size
 }
 
-func (jvmStack *JVMStack) Clear() {
-	for !jvmStack.IsEmpty() {
-		jvmStack.PopFrame()
-	}
+func (jvmStack *JVMStack) IsEmpty() bool {
+	return jvmStack.topFrame == nil
 }
@@ -70,6 +70,10 @@ func (localVariables LocalVariables) SetVariable(index uint, variable Variable)
 	localVariables[index] = variable
 }
 
+func (localVariables LocalVariables) GetVariable(index uint) Variable {
+	return localVariables[index]
+}
+
 func (localVariables LocalVariables) GetThis() *heap.Object {
 	return localVariables.GetReferenceValue(0)
 }
@@ -0,0 +1,99 @@
+package runtime_data_area
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Signalled whenever a monitor is released, blocked threads then retry entering
+var monitorReleased = sync.NewCond(&interpreterLock)
+
+// Block until the monitor of the object is acquired, waiting gives up the interpreter lock
+func (thread *Thread) EnterMonitor(object *heap.Object) {
+	thread.reenterMonitor(object.GetMonitor(), 1)
+}
+
+func (thread *Thread) reenterMonitor(monitor *heap.Monitor, entryCount int) {
+	for !monitor.TryEnter(thread, entryCount) {
+		monitorReleased.Wait()
+	}
+}
+
+// Returns false if the thread does not own the monitor of the object
+func (thread *Thread) ExitMonitor(object *heap.Object) bool {
+	if !object.GetMonitor().Exit(thread) {
+		return false
+	}
+
+	monitorReleased.Broadcast()
+
+	return true
+}
+
+func (thread *Thread) HoldsMonitor(object *heap.Object) bool {
+	return object.GetMonitor().IsOwnedBy(thread)
+}
+
+// Release the monitor of the object until notified, interrupted or timed out,
+// then take it back with the same entry count
+func (thread *Thread) Wait(object *heap.Object, timeout time.Duration) {
+	monitor := object.GetMonitor()
+
+	if !monitor.IsOwnedBy(thread) {
+		panic(heap.NewJavaException("java/lang/IllegalMonitorStateException", "current thread is not owner"))
+	}
+
+	thread.checkInterrupted("")
+
+	entryCount := monitor.ExitAll(thread)
+	monitorReleased.Broadcast()
+
+	thread.isNotified = false
+	monitor.AddWaiter(thread)
+
+	thread.park(timeout, func() bool {
+		return thread.isNotified
+	})
+
+	monitor.RemoveWaiter(thread)
+	thread.reenterMonitor(monitor, entryCount)
+	thread.checkInterrupted("")
+}
+
+func (thread *Thread) Notify(object *heap.Object) {
+	thread.notify(object, false)
+}
+
+func (thread *Thread) NotifyAll(object *heap.Object) {
+	thread.notify(object, true)
+}
+
+func (thread *Thread) notify(object *heap.Object, isNotifyAll bool) {
+	monitor := object.GetMonitor()
+
+	if !monitor.IsOwnedBy(thread) {
+		panic(heap.NewJavaException("java/lang/IllegalMonitorStateException", "current thread is not owner"))
+	}
+
+	notifyWaiters(monitor, isNotifyAll)
+}
+
+func notifyWaiters(monitor *heap.Monitor, isNotifyAll bool) {
+	for {
+		waiter := monitor.RemoveFirstWaiter()
+
+		if waiter == nil {
+			return
+		}
+
+		waitingThread := waiter.(*Thread)
+		waitingThread.isNotified = true
+		waitingThread.condition.Broadcast()
+
+		if !isNotifyAll {
+			return
+		}
+	}
+}
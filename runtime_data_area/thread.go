@@ -1,15 +1,35 @@
 package runtime_data_area
 
-import "github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+import (
+	"sync"
+
+	"github.com/Frederick-S/jvmgo/options"
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Rough size of one frame, used to turn -Xss bytes into a frame limit
+const frameSize = 1024
 
 type Thread struct {
-	pc       int
-	jvmStack *JVMStack
+	pc                int
+	jvmStack          *JVMStack
+	uncaughtException *heap.Object
+	javaThread        *heap.Object
+	isAlive           bool
+	priority          int32
+	isInterrupted     bool
+	isNotified        bool
+	isParkPermitted   bool
+	// Signalled to wake the thread up from sleeping, waiting or parking
+	condition *sync.Cond
 }
 
 func NewThread() *Thread {
 	return &Thread{
-		jvmStack: newJVMStack(1024),
+		jvmStack:  newJVMStack(uint(options.ThreadStackSize / frameSize)),
+		isAlive:   true,
+		priority:  5,
+		condition: sync.NewCond(&interpreterLock),
 	}
 }
 
@@ -25,8 +45,15 @@ func (thread *Thread) PushFrame(frame *Frame) {
 	thread.jvmStack.PushFrame(frame)
 }
 
+// Returning or unwinding from a synchronized method releases its monitor
 func (thread *Thread) PopFrame() *Frame {
-	return thread.jvmStack.PopFrame()
+	frame := thread.jvmStack.PopFrame()
+
+	if frame.monitorObject != nil {
+		thread.ExitMonitor(frame.monitorObject)
+	}
+
+	return frame
 }
 
 func (thread *Thread) GetCurrentFrame() *Frame {
@@ -49,6 +76,45 @@ func (thread *Thread) IsJVMStackEmpty() bool {
 	return thread.jvmStack.IsEmpty()
 }
 
+func (thread *Thread) GetFreeFramesCount() uint {
+	return thread.jvmStack.GetFreeFramesCount()
+}
+
 func (thread *Thread) ClearStack() {
-	thread.jvmStack.Clear()
+	for !thread.IsJVMStackEmpty() {
+		thread.PopFrame()
+	}
+}
+
+func (thread *Thread) GetUncaughtException() *heap.Object {
+	return thread.uncaughtException
+}
+
+func (thread *Thread) SetUncaughtException(exception *heap.Object) {
+	thread.uncaughtException = exception
+}
+
+func (thread *Thread) GetJavaThread() *heap.Object {
+	return thread.javaThread
+}
+
+func (thread *Thread) SetJavaThread(javaThread *heap.Object) {
+	thread.javaThread = javaThread
+	javaThread.SetExtraData(thread)
+}
+
+func (thread *Thread) IsAlive() bool {
+	return thread.isAlive
+}
+
+func (thread *Thread) SetAlive(isAlive bool) {
+	thread.isAlive = isAlive
+}
+
+func (thread *Thread) GetPriority() int32 {
+	return thread.priority
+}
+
+func (thread *Thread) SetPriority(priority int32) {
+	thread.priority = priority
 }
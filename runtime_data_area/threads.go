@@ -0,0 +1,140 @@
+package runtime_data_area
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Frederick-S/jvmgo/runtime_data_area/heap"
+)
+
+// Class loading, constant pool resolution and the string pool are not thread safe,
+// so only one thread interprets bytecode at a time
+var interpreterLock sync.Mutex
+
+// Threads which keep the VM alive until they finish
+var nonDaemonThreads sync.WaitGroup
+
+// Runs the interpreter loop of a thread until its stack is empty
+var threadLoop func(thread *Thread)
+
+func SetThreadLoop(loop func(thread *Thread)) {
+	threadLoop = loop
+}
+
+func AcquireInterpreterLock() {
+	interpreterLock.Lock()
+}
+
+func ReleaseInterpreterLock() {
+	interpreterLock.Unlock()
+}
+
+// Let other threads run while this one sleeps or blocks
+func RunWithoutInterpreterLock(operation func()) {
+	interpreterLock.Unlock()
+	defer interpreterLock.Lock()
+
+	operation()
+}
+
+func YieldInterpreterLock() {
+	RunWithoutInterpreterLock(runtime.Gosched)
+}
+
+// Run the thread on its own goroutine, onExit is called with the interpreter lock held
+func (thread *Thread) Start(isDaemon bool, onExit func()) {
+	if !isDaemon {
+		nonDaemonThreads.Add(1)
+	}
+
+	go func() {
+		AcquireInterpreterLock()
+
+		defer func() {
+			thread.isAlive = false
+			onExit()
+
+			// Threads joining this one wait on its Thread object
+			notifyWaiters(thread.javaThread.GetMonitor(), true)
+			ReleaseInterpreterLock()
+
+			if !isDaemon {
+				nonDaemonThreads.Done()
+			}
+		}()
+
+		threadLoop(thread)
+	}()
+}
+
+func WaitForNonDaemonThreads() {
+	nonDaemonThreads.Wait()
+}
+
+// Block until isDone returns true, the thread is interrupted or the timeout elapses,
+// a zero timeout never elapses
+func (thread *Thread) park(timeout time.Duration, isDone func() bool) {
+	isTimedOut := false
+
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			interpreterLock.Lock()
+			defer interpreterLock.Unlock()
+
+			isTimedOut = true
+			thread.condition.Broadcast()
+		})
+
+		defer timer.Stop()
+	}
+
+	for !isDone() && !thread.isInterrupted && !isTimedOut {
+		thread.condition.Wait()
+	}
+}
+
+func (thread *Thread) Sleep(duration time.Duration) {
+	thread.checkInterrupted("sleep interrupted")
+	thread.park(duration, func() bool {
+		return false
+	})
+	thread.checkInterrupted("sleep interrupted")
+}
+
+// Wake the thread up from sleeping or waiting
+func (thread *Thread) Interrupt() {
+	thread.isInterrupted = true
+	thread.condition.Broadcast()
+}
+
+func (thread *Thread) IsInterrupted(isClearInterrupted bool) bool {
+	isInterrupted := thread.isInterrupted
+
+	if isClearInterrupted {
+		thread.isInterrupted = false
+	}
+
+	return isInterrupted
+}
+
+// Consume a pending interrupt by throwing InterruptedException
+func (thread *Thread) checkInterrupted(message string) {
+	if thread.IsInterrupted(true) {
+		panic(heap.NewJavaException("java/lang/InterruptedException", message))
+	}
+}
+
+// LockSupport.park, returns at once if a permit from Unpark is available
+func (thread *Thread) Park(timeout time.Duration) {
+	thread.park(timeout, func() bool {
+		return thread.isParkPermitted
+	})
+
+	thread.isParkPermitted = false
+}
+
+func (thread *Thread) Unpark() {
+	thread.isParkPermitted = true
+	thread.condition.Broadcast()
+}